@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -13,33 +15,93 @@ import (
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/janisto/huma-observability/v2"
+	"github.com/quic-go/quic-go/http3"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/janisto/huma-playground/internal/http/health"
 	"github.com/janisto/huma-playground/internal/http/v1/routes"
 	"github.com/janisto/huma-playground/internal/platform/auth"
+	"github.com/janisto/huma-playground/internal/platform/fields"
 	"github.com/janisto/huma-playground/internal/platform/firebase"
+	"github.com/janisto/huma-playground/internal/platform/idempotency"
+	"github.com/janisto/huma-playground/internal/platform/limits"
+	"github.com/janisto/huma-playground/internal/platform/metrics"
 	appmiddleware "github.com/janisto/huma-playground/internal/platform/middleware"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/ratelimit"
 	"github.com/janisto/huma-playground/internal/platform/respond"
+	"github.com/janisto/huma-playground/internal/platform/stream"
+	"github.com/janisto/huma-playground/internal/platform/tracing"
 	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
 	profilesvc "github.com/janisto/huma-playground/internal/service/profile"
 )
 
 type dependencies struct {
-	verifier auth.Verifier
-	profiles profilesvc.Store
-	github   githubsvc.Service
+	verifier    auth.Verifier
+	items       itemsvc.Store
+	profiles    profilesvc.Store
+	github      githubsvc.Service
+	idempotency idempotency.Store
+	cursors     *pagination.Codec
 }
 
-const observabilityTraceContextLevel = obs.TraceContextLevel1
+const (
+	observabilityTraceContextLevel = obs.TraceContextLevel1
+
+	// certificateReloadInterval is how often TLS certificate files are checked for rotation.
+	certificateReloadInterval = 30 * time.Second
+
+	// compressionMinSize is the smallest response body worth compressing; below it the coding
+	// overhead outweighs the savings.
+	compressionMinSize = 1024
+
+	// idempotencyKeyTTL is how long a completed response is replayed for a retried Idempotency-Key.
+	idempotencyKeyTTL = 24 * time.Hour
+
+	// activityPollInterval is the shortest wait between polls of one repository's activity, GitHub's
+	// default poll interval for event APIs.
+	activityPollInterval = time.Minute
+
+	// readinessCheckTimeout bounds each dependency probe so /readyz answers before typical probe timeouts.
+	readinessCheckTimeout = 2 * time.Second
+)
+
+// telemetry holds the process-wide metrics registry and tracer provider shared by the router and
+// dependency instrumentation.
+type telemetry struct {
+	metrics        *metrics.Metrics
+	tracerProvider trace.TracerProvider
+}
 
 type applicationClients struct {
 	*firebase.Clients
 	dependencies
+	checks []health.Check
 }
 
-func newApplicationClients(ctx context.Context, cfg config, logger *zap.Logger) (*applicationClients, error) {
-	githubHTTPClient := &http.Client{Timeout: 10 * time.Second}
+func newApplicationClients(
+	ctx context.Context,
+	cfg config,
+	tracerProvider trace.TracerProvider,
+	logger *zap.Logger,
+) (*applicationClients, error) {
+	// GitHub is a third party, so outbound spans are recorded without propagating trace context to it.
+	githubHTTPClient := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithTracerProvider(tracerProvider),
+			otelhttp.WithPropagators(propagation.NewCompositeTextMapPropagator()),
+		),
+	}
+	cursors, err := pagination.NewCodec(cfg.Cursors)
+	if err != nil {
+		return nil, fmt.Errorf("create cursor codec: %w", err)
+	}
+
 	var githubOptions []githubsvc.Option
 	if cfg.GitHubToken != "" {
 		githubOptions = append(githubOptions, githubsvc.WithToken(cfg.GitHubToken))
@@ -49,13 +111,19 @@ func newApplicationClients(ctx context.Context, cfg config, logger *zap.Logger)
 		return nil, fmt.Errorf("create GitHub client: %w", err)
 	}
 
+	// GitHub only backs the proxy examples, so an outage degrades readiness without failing it.
+	checks := []health.Check{{Name: "github", CacheTTL: 30 * time.Second, Checker: githubClient}}
+
 	if cfg.FirebaseMode == firebaseModeOffline {
-		logger.Warn("Firebase is offline; protected routes return service unavailable")
+		logger.Warn("Firebase is offline; protected routes return service unavailable and items are kept in memory")
 		return &applicationClients{dependencies: dependencies{
-			verifier: unavailableVerifier{},
-			profiles: unavailableProfileStore{},
-			github:   githubClient,
-		}}, nil
+			verifier:    unavailableVerifier{},
+			items:       itemsvc.NewMemoryStore(itemsvc.SampleItems()...),
+			profiles:    unavailableProfileStore{},
+			github:      githubClient,
+			idempotency: idempotency.NewMemoryStore(),
+			cursors:     cursors,
+		}, checks: checks}, nil
 	}
 	if cfg.FirebaseMode == firebaseModeEmulator {
 		logger.Info("using Firebase emulators", zap.String("project_id", cfg.FirebaseProjectID))
@@ -64,13 +132,31 @@ func newApplicationClients(ctx context.Context, cfg config, logger *zap.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("initialize Firebase clients: %w", err)
 	}
+	profiles := profilesvc.NewFirestoreStore(clients.Firestore)
+	keysURL := auth.IDTokenCertificatesURL
+	if cfg.AuthEmulatorHost != "" {
+		keysURL = "http://" + cfg.AuthEmulatorHost + "/"
+	}
+	checks = append(checks,
+		health.Check{Name: "firestore", Critical: true, CacheTTL: 5 * time.Second, Checker: profiles},
+		health.Check{
+			Name:     "firebase_auth",
+			Critical: true,
+			CacheTTL: 30 * time.Second,
+			Checker:  auth.NewKeyFetchChecker(http.DefaultClient, keysURL),
+		},
+	)
 	return &applicationClients{
 		Clients: clients,
 		dependencies: dependencies{
-			verifier: auth.NewFirebaseVerifier(clients.Auth),
-			profiles: profilesvc.NewFirestoreStore(clients.Firestore),
-			github:   githubClient,
+			verifier:    auth.NewFirebaseVerifier(clients.Auth),
+			items:       itemsvc.NewFirestoreStore(clients.Firestore),
+			profiles:    profiles,
+			github:      githubClient,
+			idempotency: idempotency.NewFirestoreStore(clients.Firestore),
+			cursors:     cursors,
 		},
+		checks: checks,
 	}, nil
 }
 
@@ -105,7 +191,14 @@ func (unavailableProfileStore) Delete(context.Context, string) error {
 	return profilesvc.ErrUnavailable
 }
 
-func newRouter(cfg config, deps dependencies, logger *zap.Logger) http.Handler {
+func newRouter(
+	cfg config,
+	deps dependencies,
+	activity *githubsvc.ActivityFeed,
+	readiness *health.Readiness,
+	tel telemetry,
+	logger *zap.Logger,
+) http.Handler {
 	apiConfig := huma.DefaultConfig("Huma Playground API", Version)
 	apiConfig.DocsPath = "/api-docs"
 	apiConfig.OpenAPIPath = "/openapi"
@@ -114,14 +207,17 @@ func newRouter(cfg config, deps dependencies, logger *zap.Logger) http.Handler {
 	apiConfig.Info.License = &huma.License{Name: "MIT", URL: "https://opensource.org/license/mit"}
 	apiConfig.Tags = []*huma.Tag{
 		{Name: "Hello", Description: "Minimal Huma operation examples."},
-		{Name: "Items", Description: "Static cursor-pagination example."},
+		{Name: "Items", Description: "Item catalog with cursor pagination; changes require the admin role."},
 		{Name: "Profile", Description: "Firebase-authenticated Firestore profile CRUD."},
 		{Name: "GitHub", Description: "Bounded read-only GitHub API proxy examples."},
+		{Name: "Batch", Description: "Several API requests in one round trip, each run as the caller."},
 	}
 	apiConfig.RejectUnknownQueryParameters = true
+	apiConfig.Transformers = append(apiConfig.Transformers, fields.Transform)
 
 	apiRouter := chi.NewRouter()
 	api := humachi.New(apiRouter, apiConfig)
+	api.UseMiddleware(tracing.Middleware(tel.tracerProvider))
 	api.UseMiddleware(obs.RequestContext(obs.RequestContextConfig{
 		Logger:            logger,
 		Preset:            obs.PresetGCP,
@@ -132,9 +228,51 @@ func newRouter(cfg config, deps dependencies, logger *zap.Logger) http.Handler {
 		Preset:            obs.PresetGCP,
 		TraceContextLevel: observabilityTraceContextLevel,
 	}))
+	api.UseMiddleware(tel.metrics.Middleware())
+	operationLimits := limits.New(
+		limits.Limits{MaxBodyBytes: cfg.MaxBodyBytes, Timeout: cfg.RequestTimeout},
+		cfg.WriteTimeout-cfg.RequestTimeout,
+	)
+	operationLimits.Register(api)
+	api.UseMiddleware(operationLimits.Middleware())
+	// Streams start inside the operation timeout, so it bounds the whole export.
+	api.UseMiddleware(stream.Middleware())
+	var routeMiddlewares []func(huma.Context, func(huma.Context))
+	if cfg.RateLimitPerMin > 0 {
+		limiter := ratelimit.New(
+			ratelimit.NewMemoryStore(),
+			rateLimitPolicy(cfg.RateLimitPerMin),
+			authRateLimitPolicy(cfg.RateLimitPerMin),
+		)
+		limiter.DocumentResponses(api)
+		// routes.Register adds authentication, so this runs before it.
+		api.UseMiddleware(limiter.AuthMiddleware(api))
+		routeMiddlewares = append(routeMiddlewares, limiter.Middleware(api))
+	}
+	// The lock outlives the operation's context so an in-flight request cannot lose its key.
+	replayer := idempotency.New(deps.idempotency, idempotencyKeyTTL, func(op *huma.Operation) time.Duration {
+		return operationLimits.For(op).Timeout + time.Minute
+	})
+	replayer.DocumentRequests(api)
+	routeMiddlewares = append(routeMiddlewares, replayer.Middleware(api))
 	addCBOROpenAPIContent(api)
+	stream.DocumentResponses(api)
+	fields.Register(api)
 	auth.RegisterSecurityScheme(api)
-	routes.Register(api, cfg.APIPrefix, deps.verifier, deps.profiles, deps.github)
+	routes.Register(
+		api,
+		cfg.APIPrefix,
+		deps.verifier,
+		deps.items,
+		deps.profiles,
+		deps.github,
+		activity,
+		deps.cursors,
+		routeMiddlewares...,
+	)
+	// The body limit runs before routing, so it admits the largest operation override and each
+	// operation enforces its own. The timeout backstop uses the default, which operations may extend.
+	ceiling := operationLimits.Max()
 
 	router := chi.NewRouter()
 	httpAccessLogger := appmiddleware.AccessLogger()
@@ -143,29 +281,58 @@ func newRouter(cfg config, deps dependencies, logger *zap.Logger) http.Handler {
 	router.NotFound(httpAccessLogger(respond.NotFoundHandler(api)).ServeHTTP)
 	router.MethodNotAllowed(httpAccessLogger(respond.MethodNotAllowedHandler(api)).ServeHTTP)
 	router.Use(
-		appmiddleware.IgnoreForwardedHeaders(),
+		appmiddleware.ForwardedHeaders(cfg.TrustedProxies),
 		obs.HTTPRequestContext(obs.HTTPRequestContextConfig{
 			Logger:            logger,
 			Preset:            obs.PresetGCP,
 			TraceContextLevel: observabilityTraceContextLevel,
 		}),
 		respond.Recoverer(api, logger),
-		requestContextTimeout(cfg.RequestTimeout),
+		appmiddleware.Compress(compressionMinSize),
+		operationLimits.Backstop(),
 		appmiddleware.Security(cfg.APIPrefix),
 		appmiddleware.Vary(),
 		appmiddleware.CORS(cfg.CORSOrigins),
 		chimiddleware.ClientIPFromRemoteAddr,
-		chimiddleware.RequestSize(1<<20),
+		chimiddleware.RequestSize(ceiling.MaxBodyBytes),
+		appmiddleware.DecompressRequests(ceiling.MaxBodyBytes, respond.Problem(api)),
 	)
 
 	router.Group(func(r chi.Router) {
 		r.Use(httpAccessLogger)
 		r.Get("/health", health.Handler)
+		r.Get("/livez", health.Handler)
+		r.Get("/readyz", readiness.Handler)
 	})
 	router.Mount(cfg.APIPrefix, apiRouter)
 	return router
 }
 
+// authRateLimitPolicy bounds requests to protected operations per client IP before their tokens are
+// verified. It allows the default budget, so only clients that the per-user policies would already
+// limit are affected, unless several users share an address.
+func authRateLimitPolicy(perMinute int) ratelimit.Policy {
+	return ratelimit.Policy{Name: "auth", Limit: perMinute, Window: time.Minute}
+}
+
+// rateLimitPolicy gives GitHub proxy calls and profile writes a quarter of the default budget because
+// they spend the shared GitHub quota and Firestore writes.
+func rateLimitPolicy(perMinute int) ratelimit.PolicyFunc {
+	defaultPolicy := ratelimit.Policy{Name: "default", Limit: perMinute, Window: time.Minute}
+	githubPolicy := ratelimit.Policy{Name: "github", Limit: max(perMinute/4, 1), Window: time.Minute}
+	profileWritePolicy := ratelimit.Policy{Name: "profile-write", Limit: max(perMinute/4, 1), Window: time.Minute}
+	return func(op *huma.Operation) ratelimit.Policy {
+		switch {
+		case slices.Contains(op.Tags, "GitHub"):
+			return githubPolicy
+		case slices.Contains(op.Tags, "Profile") && op.Method != http.MethodGet:
+			return profileWritePolicy
+		default:
+			return defaultPolicy
+		}
+	}
+}
+
 func addCBOROpenAPIContent(api huma.API) {
 	api.OpenAPI().OnAddOperation = append(api.OpenAPI().OnAddOperation, func(_ *huma.OpenAPI, op *huma.Operation) {
 		if op.RequestBody != nil && op.RequestBody.Content != nil {
@@ -187,29 +354,63 @@ func addCBOROpenAPIContent(api huma.API) {
 	})
 }
 
-func requestContextTimeout(timeout time.Duration) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx, cancel := context.WithTimeout(r.Context(), timeout)
-			defer cancel()
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
-	}
-}
-
 func newServer(cfg config, handler http.Handler) *http.Server {
-	return &http.Server{
+	server := &http.Server{
 		Addr:              cfg.Address,
 		Handler:           handler,
 		ReadTimeout:       cfg.ReadTimeout,
 		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
 		WriteTimeout:      cfg.WriteTimeout,
 		IdleTimeout:       cfg.IdleTimeout,
-		MaxHeaderBytes:    64 << 10,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+	if cfg.H2C {
+		// Cloud Run's end-to-end HTTP/2 and other proxies speak HTTP/2 without TLS to the container.
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return server
+}
+
+// newHTTP3Server serves handler over QUIC on the same port as the TCP listener.
+func newHTTP3Server(cfg config, handler http.Handler, tlsConfig *tls.Config) *http3.Server {
+	return &http3.Server{
+		Addr:           cfg.Address,
+		Handler:        handler,
+		TLSConfig:      http3.ConfigureTLSConfig(tlsConfig),
+		IdleTimeout:    cfg.IdleTimeout,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
 	}
 }
 
-func serve(ctx context.Context, server *http.Server, shutdownTimeout time.Duration, logger *zap.Logger) error {
+// advertiseHTTP3 adds Alt-Svc to TCP responses so clients can switch to the QUIC listener.
+func advertiseHTTP3(server *http3.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// ErrNoAltSvcPort only occurs before the QUIC listener is bound; nothing is advertised then.
+		_ = server.SetQUICHeaders(w.Header())
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newMetricsServer serves only /metrics so it can be bound to an address that is not publicly routed.
+func newMetricsServer(cfg config, m *metrics.Metrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", m.Handler())
+	server := newServer(cfg, mux)
+	server.Addr = cfg.MetricsAddress
+	return server
+}
+
+// serve runs server until ctx is done, then calls beforeShutdown, if set, and shuts down gracefully.
+// A server with a TLSConfig serves TLS and negotiates HTTP/2 over ALPN.
+func serve(
+	ctx context.Context,
+	server *http.Server,
+	shutdownTimeout time.Duration,
+	beforeShutdown func(),
+	logger *zap.Logger,
+) error {
 	if ctx.Err() != nil {
 		return nil
 	}
@@ -218,11 +419,55 @@ func serve(ctx context.Context, server *http.Server, shutdownTimeout time.Durati
 	if err != nil {
 		return fmt.Errorf("listen on %s: %w", server.Addr, err)
 	}
-	logger.Info("server listening", zap.String("addr", listener.Addr().String()), zap.String("version", Version))
+	logger.Info("server listening", zap.String("addr", listener.Addr().String()),
+		zap.Bool("tls", server.TLSConfig != nil), zap.String("version", Version))
+	return serveUntilDone(ctx, server.Addr, func() error {
+		if server.TLSConfig != nil {
+			return server.ServeTLS(listener, "", "")
+		}
+		return server.Serve(listener)
+	}, server.Shutdown, server.Close, shutdownTimeout, beforeShutdown)
+}
+
+// serveHTTP3 runs an HTTP/3 server on a UDP socket with the same lifecycle as serve.
+func serveHTTP3(
+	ctx context.Context,
+	server *http3.Server,
+	shutdownTimeout time.Duration,
+	beforeShutdown func(),
+	logger *zap.Logger,
+) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	var listenConfig net.ListenConfig
+	conn, err := listenConfig.ListenPacket(ctx, "udp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("listen on udp %s: %w", server.Addr, err)
+	}
+	// The server does not close a connection it was handed.
+	defer func() { _ = conn.Close() }()
+	logger.Info("HTTP/3 server listening", zap.String("addr", conn.LocalAddr().String()),
+		zap.String("version", Version))
+	return serveUntilDone(ctx, "udp "+server.Addr, func() error {
+		return server.Serve(conn)
+	}, server.Shutdown, server.Close, shutdownTimeout, beforeShutdown)
+}
 
+// serveUntilDone runs start until it fails or ctx is done, then calls beforeShutdown, if set, and
+// shuts down gracefully, closing forcibly if shutdownTimeout elapses.
+func serveUntilDone(
+	ctx context.Context,
+	addr string,
+	start func() error,
+	shutdown func(context.Context) error,
+	closeNow func() error,
+	shutdownTimeout time.Duration,
+	beforeShutdown func(),
+) error {
 	listenErr := make(chan error, 1)
 	go func() {
-		listenErr <- server.Serve(listener)
+		listenErr <- start()
 	}()
 
 	select {
@@ -230,14 +475,17 @@ func serve(ctx context.Context, server *http.Server, shutdownTimeout time.Durati
 		if errors.Is(err, http.ErrServerClosed) {
 			return nil
 		}
-		return fmt.Errorf("serve on %s: %w", server.Addr, err)
+		return fmt.Errorf("serve on %s: %w", addr, err)
 	case <-ctx.Done():
 	}
 
+	if beforeShutdown != nil {
+		beforeShutdown()
+	}
 	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
 	defer cancel()
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		closeErr := server.Close()
+	if err := shutdown(shutdownCtx); err != nil {
+		closeErr := closeNow()
 		return errors.Join(fmt.Errorf("graceful shutdown: %w", err), closeErr)
 	}
 	if err := <-listenErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"go.uber.org/zap/zapcore"
+
+	appmiddleware "github.com/janisto/huma-playground/internal/platform/middleware"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/tracing"
 )
 
 const (
@@ -24,19 +31,31 @@ const (
 
 type config struct {
 	Address           string
+	MetricsAddress    string
 	Environment       string
 	APIPrefix         string
 	FirebaseMode      string
 	FirebaseProjectID string
+	AuthEmulatorHost  string
 	GitHubToken       string
 	CORSOrigins       []string
+	TrustedProxies    appmiddleware.TrustedProxies
+	Cursors           pagination.CodecConfig
 	LogLevel          zapcore.Level
+	TracesExporter    string
+	RateLimitPerMin   int
+	H2C               bool
+	TLSCertFile       string
+	TLSKeyFile        string
+	HTTP3             bool
 	RequestTimeout    time.Duration
 	ShutdownTimeout   time.Duration
 	ReadTimeout       time.Duration
 	ReadHeaderTimeout time.Duration
 	WriteTimeout      time.Duration
 	IdleTimeout       time.Duration
+	MaxBodyBytes      int64
+	MaxHeaderBytes    int
 }
 
 func loadConfig(getenv func(string) string) (config, error) {
@@ -50,6 +69,18 @@ func loadConfig(getenv func(string) string) (config, error) {
 		return config{}, errors.New("PORT must be an integer from 1 to 65535")
 	}
 
+	metricsAddress := ""
+	if metricsPort := strings.TrimSpace(getenv("METRICS_PORT")); metricsPort != "" {
+		metricsPortNumber, err := strconv.Atoi(metricsPort)
+		if err != nil || metricsPortNumber < 1 || metricsPortNumber > 65535 {
+			return config{}, errors.New("METRICS_PORT must be an integer from 1 to 65535")
+		}
+		if metricsPortNumber == portNumber {
+			return config{}, errors.New("METRICS_PORT must differ from PORT")
+		}
+		metricsAddress = net.JoinHostPort(host, metricsPort)
+	}
+
 	environment := valueOrDefault(strings.TrimSpace(getenv("APP_ENVIRONMENT")), environmentDevelopment)
 	switch environment {
 	case environmentDevelopment, environmentStaging, environmentProduction:
@@ -84,6 +115,20 @@ func loadConfig(getenv func(string) string) (config, error) {
 		return config{}, err
 	}
 
+	trustedProxies, err := parseTrustedProxies(
+		getenv("TRUSTED_PROXIES"),
+		getenv("TRUSTED_PROXY_HOPS"),
+		getenv("TRUSTED_PROXY_HEADER"),
+	)
+	if err != nil {
+		return config{}, err
+	}
+
+	cursors, err := parseCursors(environment, getenv)
+	if err != nil {
+		return config{}, err
+	}
+
 	levelName := valueOrDefault(strings.TrimSpace(getenv("LOG_LEVEL")), "info")
 	switch levelName {
 	case "debug", "info", "warn", "error":
@@ -95,21 +140,56 @@ func loadConfig(getenv func(string) string) (config, error) {
 		return config{}, fmt.Errorf("parse LOG_LEVEL: %w", err)
 	}
 
+	tracesExporter := valueOrDefault(strings.TrimSpace(getenv("TRACES_EXPORTER")), tracing.ExporterNone)
+	switch tracesExporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		return config{}, errors.New("TRACES_EXPORTER must be none, stdout, or otlp")
+	}
+
+	rateLimit := valueOrDefault(strings.TrimSpace(getenv("RATE_LIMIT_PER_MINUTE")), "120")
+	rateLimitPerMin, err := strconv.Atoi(rateLimit)
+	if err != nil || rateLimitPerMin < 0 || rateLimitPerMin > 100000 {
+		return config{}, errors.New("RATE_LIMIT_PER_MINUTE must be an integer from 0 to 100000")
+	}
+
+	protocols, err := parseProtocols(getenv)
+	if err != nil {
+		return config{}, err
+	}
+
+	limits, err := parseServerLimits(getenv)
+	if err != nil {
+		return config{}, err
+	}
+
 	return config{
 		Address:           net.JoinHostPort(host, port),
+		MetricsAddress:    metricsAddress,
 		Environment:       environment,
 		APIPrefix:         "/v1",
 		FirebaseMode:      mode,
 		FirebaseProjectID: projectID,
+		AuthEmulatorHost:  authEmulator,
 		GitHubToken:       getenv("GITHUB_TOKEN"),
 		CORSOrigins:       origins,
+		TrustedProxies:    trustedProxies,
+		Cursors:           cursors,
 		LogLevel:          level,
-		RequestTimeout:    8 * time.Second,
-		ShutdownTimeout:   10 * time.Second,
-		ReadTimeout:       5 * time.Second,
-		ReadHeaderTimeout: 2 * time.Second,
-		WriteTimeout:      10 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		TracesExporter:    tracesExporter,
+		RateLimitPerMin:   rateLimitPerMin,
+		H2C:               protocols.h2c,
+		TLSCertFile:       protocols.certFile,
+		TLSKeyFile:        protocols.keyFile,
+		HTTP3:             protocols.http3,
+		RequestTimeout:    limits.request,
+		ShutdownTimeout:   limits.shutdown,
+		ReadTimeout:       limits.read,
+		ReadHeaderTimeout: limits.readHeader,
+		WriteTimeout:      limits.write,
+		IdleTimeout:       limits.idle,
+		MaxBodyBytes:      limits.maxBodyBytes,
+		MaxHeaderBytes:    limits.maxHeaderBytes,
 	}, nil
 }
 
@@ -202,6 +282,185 @@ func parseCORSOrigins(environment, value string) ([]string, error) {
 	return origins, nil
 }
 
+// parseCursors reads the pagination cursor signing keys as comma-separated id:secret pairs, where each
+// secret is standard Base64 and the first key signs. Development without keys uses a random key, so
+// cursors do not survive a restart there.
+func parseCursors(environment string, getenv func(string) string) (pagination.CodecConfig, error) {
+	var cursors pagination.CodecConfig
+	value := strings.TrimSpace(getenv("CURSOR_SIGNING_KEYS"))
+	switch {
+	case value != "":
+		for entry := range strings.SplitSeq(value, ",") {
+			id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			secret, err := base64.StdEncoding.DecodeString(encoded)
+			if !ok || err != nil {
+				return cursors, errors.New("CURSOR_SIGNING_KEYS entries must be id:base64-secret")
+			}
+			cursors.Keys = append(cursors.Keys, pagination.Key{ID: id, Secret: secret})
+		}
+	case environment != environmentDevelopment:
+		return cursors, errors.New("CURSOR_SIGNING_KEYS is required outside development")
+	default:
+		secret := make([]byte, 32)
+		_, _ = rand.Read(secret)
+		cursors.Keys = []pagination.Key{{ID: "dev", Secret: secret}}
+	}
+
+	ttl := valueOrDefault(strings.TrimSpace(getenv("CURSOR_TTL")), "24h")
+	var err error
+	cursors.TTL, err = time.ParseDuration(ttl)
+	if err != nil || (cursors.TTL != 0 && cursors.TTL < time.Minute) || cursors.TTL > 90*24*time.Hour {
+		return cursors, errors.New("CURSOR_TTL must be 0 or a duration from 1m to 2160h")
+	}
+	if cursors.AcceptUnsigned, err = parseBool("CURSOR_ACCEPT_UNSIGNED", getenv("CURSOR_ACCEPT_UNSIGNED")); err != nil {
+		return cursors, err
+	}
+	// Validate key IDs and sizes now rather than when the router is built.
+	if _, err := pagination.NewCodec(cursors); err != nil {
+		return cursors, fmt.Errorf("CURSOR_SIGNING_KEYS: %w", err)
+	}
+	return cursors, nil
+}
+
+// parseTrustedProxies accepts comma-separated CIDRs or single IP addresses, a hop count, and the
+// forwarding header family the proxies write.
+func parseTrustedProxies(prefixes, hops, header string) (appmiddleware.TrustedProxies, error) {
+	var proxies appmiddleware.TrustedProxies
+	switch strings.ToLower(strings.TrimSpace(header)) {
+	case "", "x-forwarded":
+		proxies.Header = appmiddleware.HeaderXForwarded
+	case "forwarded":
+		proxies.Header = appmiddleware.HeaderForwarded
+	default:
+		return proxies, errors.New("TRUSTED_PROXY_HEADER must be forwarded or x-forwarded")
+	}
+	if hops = strings.TrimSpace(hops); hops != "" {
+		count, err := strconv.Atoi(hops)
+		if err != nil || count < 0 || count > 10 {
+			return proxies, errors.New("TRUSTED_PROXY_HOPS must be an integer from 0 to 10")
+		}
+		proxies.Hops = count
+	}
+	if prefixes = strings.TrimSpace(prefixes); prefixes == "" {
+		return proxies, nil
+	}
+	for entry := range strings.SplitSeq(prefixes, ",") {
+		entry = strings.TrimSpace(entry)
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				return proxies, fmt.Errorf("TRUSTED_PROXIES contains invalid CIDR or IP %q", entry)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		proxies.Prefixes = append(proxies.Prefixes, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+type protocolConfig struct {
+	h2c      bool
+	certFile string
+	keyFile  string
+	http3    bool
+}
+
+// parseProtocols validates the serving protocols: h2c for cleartext HTTP/2 behind a proxy, or native
+// TLS with optional HTTP/3, which requires TLS.
+func parseProtocols(getenv func(string) string) (protocolConfig, error) {
+	var protocols protocolConfig
+	var err error
+	if protocols.h2c, err = parseBool("H2C_ENABLED", getenv("H2C_ENABLED")); err != nil {
+		return protocols, err
+	}
+	if protocols.http3, err = parseBool("HTTP3_ENABLED", getenv("HTTP3_ENABLED")); err != nil {
+		return protocols, err
+	}
+	protocols.certFile = strings.TrimSpace(getenv("TLS_CERT_FILE"))
+	protocols.keyFile = strings.TrimSpace(getenv("TLS_KEY_FILE"))
+	tls := protocols.certFile != ""
+	switch {
+	case tls != (protocols.keyFile != ""):
+		return protocols, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be configured together")
+	case tls && protocols.h2c:
+		return protocols, errors.New("H2C_ENABLED applies only without TLS; HTTP/2 is negotiated over TLS")
+	case protocols.http3 && !tls:
+		return protocols, errors.New("HTTP3_ENABLED requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+	return protocols, nil
+}
+
+type serverLimits struct {
+	request        time.Duration
+	shutdown       time.Duration
+	read           time.Duration
+	readHeader     time.Duration
+	write          time.Duration
+	idle           time.Duration
+	maxBodyBytes   int64
+	maxHeaderBytes int
+}
+
+// parseServerLimits reads the server timeouts and size limits and checks that they fit together: the
+// handler timeout must leave time to write its response, and graceful shutdown must let a request
+// that just started finish.
+func parseServerLimits(getenv func(string) string) (serverLimits, error) {
+	var limits serverLimits
+	for _, setting := range []struct {
+		name     string
+		fallback time.Duration
+		target   *time.Duration
+	}{
+		{name: "REQUEST_TIMEOUT", fallback: 8 * time.Second, target: &limits.request},
+		{name: "SHUTDOWN_TIMEOUT", fallback: 10 * time.Second, target: &limits.shutdown},
+		{name: "READ_TIMEOUT", fallback: 5 * time.Second, target: &limits.read},
+		{name: "READ_HEADER_TIMEOUT", fallback: 2 * time.Second, target: &limits.readHeader},
+		{name: "WRITE_TIMEOUT", fallback: 10 * time.Second, target: &limits.write},
+		{name: "IDLE_TIMEOUT", fallback: 60 * time.Second, target: &limits.idle},
+	} {
+		value := valueOrDefault(strings.TrimSpace(getenv(setting.name)), setting.fallback.String())
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 100*time.Millisecond || duration > time.Hour {
+			return limits, fmt.Errorf("%s must be a duration from 100ms to 1h, such as 8s", setting.name)
+		}
+		*setting.target = duration
+	}
+	switch {
+	case limits.readHeader > limits.read:
+		return limits, errors.New("READ_HEADER_TIMEOUT must not exceed READ_TIMEOUT")
+	case limits.request >= limits.write:
+		return limits, errors.New("REQUEST_TIMEOUT must be less than WRITE_TIMEOUT so timeout responses can be written")
+	case limits.shutdown < limits.request:
+		return limits, errors.New("SHUTDOWN_TIMEOUT must be at least REQUEST_TIMEOUT so in-flight requests can drain")
+	}
+
+	maxBodyBytes := valueOrDefault(strings.TrimSpace(getenv("MAX_REQUEST_BODY_BYTES")), strconv.Itoa(1<<20))
+	var err error
+	limits.maxBodyBytes, err = strconv.ParseInt(maxBodyBytes, 10, 64)
+	if err != nil || limits.maxBodyBytes < 1<<10 || limits.maxBodyBytes > 64<<20 {
+		return limits, errors.New("MAX_REQUEST_BODY_BYTES must be an integer from 1024 to 67108864")
+	}
+	maxHeaderBytes := valueOrDefault(strings.TrimSpace(getenv("MAX_HEADER_BYTES")), strconv.Itoa(64<<10))
+	limits.maxHeaderBytes, err = strconv.Atoi(maxHeaderBytes)
+	if err != nil || limits.maxHeaderBytes < 4<<10 || limits.maxHeaderBytes > 1<<20 {
+		return limits, errors.New("MAX_HEADER_BYTES must be an integer from 4096 to 1048576")
+	}
+	return limits, nil
+}
+
+func parseBool(name, value string) (bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return parsed, nil
+}
+
 func valueOrDefault(value, fallback string) string {
 	if value == "" {
 		return fallback
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/janisto/huma-playground/internal/platform/auth"
+	"github.com/janisto/huma-playground/internal/platform/idempotency"
+	"github.com/janisto/huma-playground/internal/platform/metrics"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/tracing"
+	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
+	profilesvc "github.com/janisto/huma-playground/internal/service/profile"
+)
+
+const (
+	dependencyFirebaseAuth = "firebase_auth"
+	dependencyFirestore    = "firestore"
+	dependencyGitHub       = "github"
+)
+
+// instrumentDependencies wraps each dependency so its calls are recorded as dependency metrics and
+// client spans.
+func instrumentDependencies(deps dependencies, tel telemetry) dependencies {
+	instruments := dependencyInstruments{
+		metrics: tel.metrics,
+		tracer:  tel.tracerProvider.Tracer(tracing.InstrumentationName),
+	}
+	return dependencies{
+		verifier:    instrumentedVerifier{next: deps.verifier, instruments: instruments},
+		items:       instrumentedItemStore{next: deps.items, instruments: instruments},
+		profiles:    instrumentedProfileStore{next: deps.profiles, instruments: instruments},
+		github:      instrumentedGitHubService{next: deps.github, instruments: instruments},
+		idempotency: instrumentedIdempotencyStore{next: deps.idempotency, instruments: instruments},
+		cursors:     deps.cursors,
+	}
+}
+
+type dependencyInstruments struct {
+	metrics *metrics.Metrics
+	tracer  trace.Tracer
+}
+
+// observe runs call inside a client span and records it as a dependency call. Failed spans carry
+// the bounded outcome rather than the error text, which may contain upstream details.
+func observe[T any](
+	ctx context.Context,
+	in dependencyInstruments,
+	dependency, operation string,
+	classify func(error) string,
+	call func(context.Context) (T, error),
+) (T, error) {
+	ctx, span := in.tracer.Start(ctx, dependency+" "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("dependency.name", dependency)),
+	)
+	defer span.End()
+	result, err := metrics.Observe(in.metrics, dependency, operation, classify, func() (T, error) {
+		return call(ctx)
+	})
+	if err != nil {
+		outcome := classify(err)
+		span.SetAttributes(attribute.String("dependency.outcome", outcome))
+		span.SetStatus(codes.Error, outcome)
+	}
+	return result, err
+}
+
+// dependencyOutcome covers context failures shared by every dependency; ok is false when err is
+// something else and needs dependency-specific classification.
+func dependencyOutcome(err error) (string, bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled", true
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout", true
+	default:
+		return "", false
+	}
+}
+
+func classifyVerifierError(err error) string {
+	if outcome, ok := dependencyOutcome(err); ok {
+		return outcome
+	}
+	switch {
+	case errors.Is(err, auth.ErrAuthUnavailable):
+		return "unavailable"
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenExpired),
+		errors.Is(err, auth.ErrTokenRevoked), errors.Is(err, auth.ErrUserDisabled):
+		return "rejected"
+	default:
+		return "error"
+	}
+}
+
+func classifyProfileError(err error) string {
+	if outcome, ok := dependencyOutcome(err); ok {
+		return outcome
+	}
+	switch {
+	case errors.Is(err, profilesvc.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, profilesvc.ErrAlreadyExists):
+		return "already_exists"
+	case errors.Is(err, profilesvc.ErrUnavailable):
+		return "unavailable"
+	default:
+		return "error"
+	}
+}
+
+func classifyItemError(err error) string {
+	if outcome, ok := dependencyOutcome(err); ok {
+		return outcome
+	}
+	switch {
+	case errors.Is(err, itemsvc.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return "invalid_cursor"
+	case errors.Is(err, itemsvc.ErrUnavailable):
+		return "unavailable"
+	default:
+		return "error"
+	}
+}
+
+func classifyIdempotencyError(err error) string {
+	if outcome, ok := dependencyOutcome(err); ok {
+		return outcome
+	}
+	return "error"
+}
+
+func classifyGitHubError(err error) string {
+	if outcome, ok := dependencyOutcome(err); ok {
+		return outcome
+	}
+	if upstreamErr, ok := errors.AsType[*githubsvc.UpstreamError](err); ok {
+		switch upstreamErr.Kind {
+		case githubsvc.UpstreamErrorKindNotFound:
+			return "not_found"
+		case githubsvc.UpstreamErrorKindRateLimited:
+			return "rate_limited"
+		case githubsvc.UpstreamErrorKindForbidden:
+			return "forbidden"
+		default:
+			return "error"
+		}
+	}
+	switch {
+	case errors.Is(err, githubsvc.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, githubsvc.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, githubsvc.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, githubsvc.ErrContentTooLarge), errors.Is(err, githubsvc.ErrUnsupportedContent):
+		return "rejected"
+	default:
+		return "error"
+	}
+}
+
+type instrumentedVerifier struct {
+	next        auth.Verifier
+	instruments dependencyInstruments
+}
+
+func (v instrumentedVerifier) Verify(ctx context.Context, token string) (*auth.FirebaseUser, error) {
+	return observe(ctx, v.instruments, dependencyFirebaseAuth, "verify_id_token", classifyVerifierError,
+		func(ctx context.Context) (*auth.FirebaseUser, error) { return v.next.Verify(ctx, token) })
+}
+
+type instrumentedProfileStore struct {
+	next        profilesvc.Store
+	instruments dependencyInstruments
+}
+
+func (s instrumentedProfileStore) Create(
+	ctx context.Context,
+	userID string,
+	params profilesvc.CreateParams,
+) (*profilesvc.Profile, error) {
+	return observe(ctx, s.instruments, dependencyFirestore, "create_profile", classifyProfileError,
+		func(ctx context.Context) (*profilesvc.Profile, error) { return s.next.Create(ctx, userID, params) })
+}
+
+func (s instrumentedProfileStore) Get(ctx context.Context, userID string) (*profilesvc.Profile, error) {
+	return observe(ctx, s.instruments, dependencyFirestore, "get_profile", classifyProfileError,
+		func(ctx context.Context) (*profilesvc.Profile, error) { return s.next.Get(ctx, userID) })
+}
+
+func (s instrumentedProfileStore) Update(
+	ctx context.Context,
+	userID string,
+	params profilesvc.UpdateParams,
+) (*profilesvc.Profile, error) {
+	return observe(ctx, s.instruments, dependencyFirestore, "update_profile", classifyProfileError,
+		func(ctx context.Context) (*profilesvc.Profile, error) { return s.next.Update(ctx, userID, params) })
+}
+
+func (s instrumentedProfileStore) Delete(ctx context.Context, userID string) error {
+	_, err := observe(ctx, s.instruments, dependencyFirestore, "delete_profile", classifyProfileError,
+		func(ctx context.Context) (struct{}, error) { return struct{}{}, s.next.Delete(ctx, userID) })
+	return err
+}
+
+type instrumentedItemStore struct {
+	next        itemsvc.Store
+	instruments dependencyInstruments
+}
+
+func (s instrumentedItemStore) Create(
+	ctx context.Context,
+	actorID string,
+	params itemsvc.CreateParams,
+) (*itemsvc.Item, error) {
+	return observe(ctx, s.instruments, dependencyFirestore, "create_item", classifyItemError,
+		func(ctx context.Context) (*itemsvc.Item, error) { return s.next.Create(ctx, actorID, params) })
+}
+
+func (s instrumentedItemStore) Get(ctx context.Context, id string) (*itemsvc.Item, error) {
+	return observe(ctx, s.instruments, dependencyFirestore, "get_item", classifyItemError,
+		func(ctx context.Context) (*itemsvc.Item, error) { return s.next.Get(ctx, id) })
+}
+
+func (s instrumentedItemStore) List(
+	ctx context.Context,
+	params itemsvc.ListParams,
+) (pagination.Result[itemsvc.Item], error) {
+	return observe(ctx, s.instruments, dependencyFirestore, "list_items", classifyItemError,
+		func(ctx context.Context) (pagination.Result[itemsvc.Item], error) { return s.next.List(ctx, params) })
+}
+
+func (s instrumentedItemStore) Update(
+	ctx context.Context,
+	actorID, id string,
+	params itemsvc.UpdateParams,
+) (*itemsvc.Item, error) {
+	return observe(ctx, s.instruments, dependencyFirestore, "update_item", classifyItemError,
+		func(ctx context.Context) (*itemsvc.Item, error) { return s.next.Update(ctx, actorID, id, params) })
+}
+
+func (s instrumentedItemStore) Delete(ctx context.Context, actorID, id string) error {
+	_, err := observe(ctx, s.instruments, dependencyFirestore, "delete_item", classifyItemError,
+		func(ctx context.Context) (struct{}, error) { return struct{}{}, s.next.Delete(ctx, actorID, id) })
+	return err
+}
+
+type instrumentedIdempotencyStore struct {
+	next        idempotency.Store
+	instruments dependencyInstruments
+}
+
+type idempotencyReservation struct {
+	existing idempotency.Record
+	reserved bool
+}
+
+func (s instrumentedIdempotencyStore) Reserve(
+	ctx context.Context,
+	key string,
+	pending idempotency.Record,
+) (idempotency.Record, bool, error) {
+	result, err := observe(ctx, s.instruments, dependencyFirestore, "reserve_idempotency_key",
+		classifyIdempotencyError, func(ctx context.Context) (idempotencyReservation, error) {
+			existing, reserved, err := s.next.Reserve(ctx, key, pending)
+			return idempotencyReservation{existing: existing, reserved: reserved}, err
+		})
+	return result.existing, result.reserved, err
+}
+
+func (s instrumentedIdempotencyStore) Complete(ctx context.Context, key string, record idempotency.Record) error {
+	_, err := observe(ctx, s.instruments, dependencyFirestore, "complete_idempotency_key", classifyIdempotencyError,
+		func(ctx context.Context) (struct{}, error) { return struct{}{}, s.next.Complete(ctx, key, record) })
+	return err
+}
+
+func (s instrumentedIdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := observe(ctx, s.instruments, dependencyFirestore, "release_idempotency_key", classifyIdempotencyError,
+		func(ctx context.Context) (struct{}, error) { return struct{}{}, s.next.Release(ctx, key) })
+	return err
+}
+
+type instrumentedGitHubService struct {
+	next        githubsvc.Service
+	instruments dependencyInstruments
+}
+
+func (s instrumentedGitHubService) GetOwner(ctx context.Context, owner string) (*githubsvc.Owner, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "get_owner", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.Owner, error) { return s.next.GetOwner(ctx, owner) })
+}
+
+func (s instrumentedGitHubService) ListRepos(ctx context.Context, owner string) ([]githubsvc.RepoSummary, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "list_owner_repositories", classifyGitHubError,
+		func(ctx context.Context) ([]githubsvc.RepoSummary, error) { return s.next.ListRepos(ctx, owner) })
+}
+
+func (s instrumentedGitHubService) GetRepo(ctx context.Context, owner, repo string) (*githubsvc.Repo, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "get_repository", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.Repo, error) { return s.next.GetRepo(ctx, owner, repo) })
+}
+
+func (s instrumentedGitHubService) ListActivity(
+	ctx context.Context,
+	owner, repo string,
+	limit int,
+	afterCursor string,
+) (*githubsvc.ActivityPage, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "list_repository_activity", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.ActivityPage, error) {
+			return s.next.ListActivity(ctx, owner, repo, limit, afterCursor)
+		})
+}
+
+func (s instrumentedGitHubService) PollActivity(
+	ctx context.Context,
+	owner, repo, etag string,
+) (*githubsvc.ActivityPoll, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "poll_repository_activity", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.ActivityPoll, error) {
+			return s.next.PollActivity(ctx, owner, repo, etag)
+		})
+}
+
+func (s instrumentedGitHubService) ListLanguages(ctx context.Context, owner, repo string) (map[string]int64, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "get_repository_languages", classifyGitHubError,
+		func(ctx context.Context) (map[string]int64, error) { return s.next.ListLanguages(ctx, owner, repo) })
+}
+
+func (s instrumentedGitHubService) ListTags(ctx context.Context, owner, repo string) ([]githubsvc.Tag, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "list_repository_tags", classifyGitHubError,
+		func(ctx context.Context) ([]githubsvc.Tag, error) { return s.next.ListTags(ctx, owner, repo) })
+}
+
+func (s instrumentedGitHubService) ListReleases(
+	ctx context.Context,
+	owner, repo string,
+	limit int,
+	pageCursor string,
+) (*githubsvc.ReleasePage, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "list_repository_releases", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.ReleasePage, error) {
+			return s.next.ListReleases(ctx, owner, repo, limit, pageCursor)
+		})
+}
+
+func (s instrumentedGitHubService) GetLatestRelease(
+	ctx context.Context,
+	owner, repo string,
+) (*githubsvc.Release, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "get_repository_latest_release", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.Release, error) {
+			return s.next.GetLatestRelease(ctx, owner, repo)
+		})
+}
+
+func (s instrumentedGitHubService) GetReleaseByTag(
+	ctx context.Context,
+	owner, repo, tag string,
+) (*githubsvc.Release, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "get_repository_release_by_tag", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.Release, error) {
+			return s.next.GetReleaseByTag(ctx, owner, repo, tag)
+		})
+}
+
+func (s instrumentedGitHubService) ListPullRequests(
+	ctx context.Context,
+	owner, repo string,
+	params githubsvc.PullRequestListParams,
+) (*githubsvc.PullRequestPage, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "list_repository_pull_requests", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.PullRequestPage, error) {
+			return s.next.ListPullRequests(ctx, owner, repo, params)
+		})
+}
+
+func (s instrumentedGitHubService) ListIssues(
+	ctx context.Context,
+	owner, repo string,
+	params githubsvc.IssueListParams,
+) (*githubsvc.IssuePage, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "list_repository_issues", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.IssuePage, error) {
+			return s.next.ListIssues(ctx, owner, repo, params)
+		})
+}
+
+func (s instrumentedGitHubService) ListCommits(
+	ctx context.Context,
+	owner, repo string,
+	params githubsvc.CommitListParams,
+) (*githubsvc.CommitPage, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "list_repository_commits", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.CommitPage, error) {
+			return s.next.ListCommits(ctx, owner, repo, params)
+		})
+}
+
+func (s instrumentedGitHubService) CompareCommits(
+	ctx context.Context,
+	owner, repo, base, head string,
+) (*githubsvc.Comparison, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "compare_repository_refs", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.Comparison, error) {
+			return s.next.CompareCommits(ctx, owner, repo, base, head)
+		})
+}
+
+func (s instrumentedGitHubService) GetContents(
+	ctx context.Context,
+	owner, repo, path, ref string,
+) (*githubsvc.Content, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "get_repository_contents", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.Content, error) {
+			return s.next.GetContents(ctx, owner, repo, path, ref)
+		})
+}
+
+func (s instrumentedGitHubService) GetReadme(ctx context.Context, owner, repo, ref string) (*githubsvc.Content, error) {
+	return observe(ctx, s.instruments, dependencyGitHub, "get_repository_readme", classifyGitHubError,
+		func(ctx context.Context) (*githubsvc.Content, error) { return s.next.GetReadme(ctx, owner, repo, ref) })
+}
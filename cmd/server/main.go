@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,9 +12,20 @@ import (
 	_ "github.com/danielgtaylor/huma/v2/formats/cbor"
 	"github.com/janisto/huma-observability/v2"
 	_ "github.com/joho/godotenv/autoload"
+	"github.com/quic-go/quic-go/http3"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/janisto/huma-playground/internal/http/health"
+	"github.com/janisto/huma-playground/internal/platform/metrics"
+	"github.com/janisto/huma-playground/internal/platform/tlscert"
+	"github.com/janisto/huma-playground/internal/platform/tracing"
+	githubsvc "github.com/janisto/huma-playground/internal/service/github"
 )
 
+// serviceName identifies this process in telemetry.
+const serviceName = "huma-playground"
+
 var Version = "dev"
 
 func main() {
@@ -46,7 +58,24 @@ func run(parent context.Context) (runErr error) {
 
 	ctx, stop := signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
-	clients, err := newApplicationClients(ctx, cfg, logger)
+	tracerProvider, err := tracing.New(ctx, tracing.Config{
+		Exporter:       cfg.TracesExporter,
+		ServiceName:    serviceName,
+		ServiceVersion: Version,
+		Environment:    cfg.Environment,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.ShutdownTimeout)
+		defer cancel()
+		if shutdownErr := tracerProvider.Shutdown(shutdownCtx); shutdownErr != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("shut down tracer provider: %w", shutdownErr))
+		}
+	}()
+
+	clients, err := newApplicationClients(ctx, cfg, tracerProvider, logger)
 	if err != nil {
 		return err
 	}
@@ -56,8 +85,51 @@ func run(parent context.Context) (runErr error) {
 		}
 	}()
 
-	server := newServer(cfg, newRouter(cfg, clients.dependencies, logger))
-	if err := serve(ctx, server, cfg.ShutdownTimeout, logger); err != nil {
+	tel := telemetry{metrics: metrics.New(), tracerProvider: tracerProvider}
+	deps := instrumentDependencies(clients.dependencies, tel)
+	shutdown := &health.Shutdown{}
+	readiness := health.NewReadiness(readinessCheckTimeout,
+		append([]health.Check{{Name: "shutdown", Critical: true, Checker: shutdown}}, clients.checks...)...)
+	activity := githubsvc.NewActivityFeed(deps.github, activityPollInterval, logger)
+	defer activity.Close()
+	router := newRouter(cfg, deps, activity, readiness, tel, logger)
+	apiServer := newServer(cfg, router)
+	var http3Server *http3.Server
+	if cfg.TLSCertFile != "" {
+		certificates, err := tlscert.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return err
+		}
+		go certificates.Watch(ctx, certificateReloadInterval, logger)
+		apiServer.TLSConfig = certificates.TLSConfig()
+		if cfg.HTTP3 {
+			http3Server = newHTTP3Server(cfg, router, certificates.TLSConfig())
+			apiServer.Handler = advertiseHTTP3(http3Server, router)
+		}
+	}
+	servers := []*http.Server{apiServer}
+	if cfg.MetricsAddress != "" {
+		servers = append(servers, newMetricsServer(cfg, tel.metrics))
+	}
+	// A failing listener cancels serveCtx so the remaining servers shut down too. Readiness fails as
+	// soon as any server begins shutting down, and activity streams end, since graceful shutdown
+	// would otherwise wait for them until it times out.
+	beforeShutdown := func() {
+		shutdown.Begin()
+		activity.Close()
+	}
+	group, serveCtx := errgroup.WithContext(ctx)
+	for _, server := range servers {
+		group.Go(func() error {
+			return serve(serveCtx, server, cfg.ShutdownTimeout, beforeShutdown, logger)
+		})
+	}
+	if http3Server != nil {
+		group.Go(func() error {
+			return serveHTTP3(serveCtx, http3Server, cfg.ShutdownTimeout, beforeShutdown, logger)
+		})
+	}
+	if err := group.Wait(); err != nil {
 		return err
 	}
 	if cause := context.Cause(ctx); cause != nil {
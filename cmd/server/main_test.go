@@ -2,26 +2,55 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"maps"
+	"math/big"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"net/url"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/janisto/huma-observability/v2"
+	"github.com/quic-go/quic-go/http3"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/zap"
 
 	"github.com/janisto/huma-playground/internal/http/health"
 	"github.com/janisto/huma-playground/internal/platform/auth"
+	"github.com/janisto/huma-playground/internal/platform/idempotency"
+	"github.com/janisto/huma-playground/internal/platform/metrics"
+	appmiddleware "github.com/janisto/huma-playground/internal/platform/middleware"
+	"github.com/janisto/huma-playground/internal/platform/stream"
+	"github.com/janisto/huma-playground/internal/platform/tlscert"
+	"github.com/janisto/huma-playground/internal/platform/tracing"
 	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
+	profilesvc "github.com/janisto/huma-playground/internal/service/profile"
+	"github.com/janisto/huma-playground/internal/testutil"
 )
 
 type stubVerifier struct {
@@ -46,6 +75,14 @@ func testConfig(t *testing.T) config {
 	return cfg
 }
 
+func testTelemetry() telemetry {
+	return telemetry{metrics: metrics.New(), tracerProvider: noop.NewTracerProvider()}
+}
+
+func testReadiness() *health.Readiness {
+	return health.NewReadiness(time.Second)
+}
+
 func testRouter(t *testing.T, cfg config) http.Handler {
 	t.Helper()
 	return testRouterWithLogger(t, cfg, zap.NewNop())
@@ -58,10 +95,21 @@ func testRouterWithLogger(t *testing.T, cfg config, logger *zap.Logger) http.Han
 		t.Fatalf("create GitHub client: %v", err)
 	}
 	return newRouter(cfg, dependencies{
-		verifier: &stubVerifier{User: testUser()},
-		profiles: unavailableProfileStore{},
-		github:   githubClient,
-	}, logger)
+		verifier:    &stubVerifier{User: testUser()},
+		items:       itemsvc.NewMemoryStore(itemsvc.SampleItems()...),
+		profiles:    unavailableProfileStore{},
+		github:      githubClient,
+		idempotency: idempotency.NewMemoryStore(),
+		cursors:     testutil.Cursors(),
+	}, testActivityFeed(t, githubClient), testReadiness(), testTelemetry(), logger)
+}
+
+// testActivityFeed returns a feed over svc that is closed when the test ends.
+func testActivityFeed(t *testing.T, svc githubsvc.Service) *githubsvc.ActivityFeed {
+	t.Helper()
+	feed := githubsvc.NewActivityFeed(svc, time.Minute, zap.NewNop())
+	t.Cleanup(feed.Close)
+	return feed
 }
 
 func TestLoadConfigDefaults(t *testing.T) {
@@ -75,6 +123,138 @@ func TestLoadConfigDefaults(t *testing.T) {
 	if len(cfg.CORSOrigins) != 1 || cfg.CORSOrigins[0] != "*" {
 		t.Fatalf("unexpected CORS defaults: %v", cfg.CORSOrigins)
 	}
+	if cfg.MetricsAddress != "" {
+		t.Fatalf("expected metrics listener to be disabled by default, got %q", cfg.MetricsAddress)
+	}
+	if cfg.TracesExporter != tracing.ExporterNone {
+		t.Fatalf("expected tracing to be disabled by default, got %q", cfg.TracesExporter)
+	}
+	if cfg.RateLimitPerMin != 120 {
+		t.Fatalf("expected default rate limit of 120, got %d", cfg.RateLimitPerMin)
+	}
+}
+
+func TestLoadConfigMetricsAddress(t *testing.T) {
+	values := map[string]string{"HOST": "127.0.0.1", "METRICS_PORT": "9090"}
+	cfg, err := loadConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.MetricsAddress != "127.0.0.1:9090" {
+		t.Fatalf("unexpected metrics address %q", cfg.MetricsAddress)
+	}
+}
+
+func TestLoadConfigTrustedProxies(t *testing.T) {
+	values := map[string]string{
+		"TRUSTED_PROXIES":      "10.0.0.0/8, 192.0.2.1, 2001:db8::/32",
+		"TRUSTED_PROXY_HOPS":   "1",
+		"TRUSTED_PROXY_HEADER": "Forwarded",
+	}
+	cfg, err := loadConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	want := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+	if !slices.Equal(cfg.TrustedProxies.Prefixes, want) || cfg.TrustedProxies.Hops != 1 ||
+		cfg.TrustedProxies.Header != appmiddleware.HeaderForwarded {
+		t.Fatalf("unexpected trusted proxies: %#v", cfg.TrustedProxies)
+	}
+}
+
+func TestLoadConfigServerLimits(t *testing.T) {
+	cfg := testConfig(t)
+	if cfg.RequestTimeout != 8*time.Second || cfg.ShutdownTimeout != 10*time.Second ||
+		cfg.ReadTimeout != 5*time.Second || cfg.ReadHeaderTimeout != 2*time.Second ||
+		cfg.WriteTimeout != 10*time.Second || cfg.IdleTimeout != time.Minute ||
+		cfg.MaxBodyBytes != 1<<20 || cfg.MaxHeaderBytes != 64<<10 {
+		t.Fatalf("unexpected default limits: %#v", cfg)
+	}
+	values := map[string]string{
+		"REQUEST_TIMEOUT":        "25s",
+		"WRITE_TIMEOUT":          "30s",
+		"SHUTDOWN_TIMEOUT":       "25s",
+		"READ_TIMEOUT":           "10s",
+		"READ_HEADER_TIMEOUT":    "500ms",
+		"IDLE_TIMEOUT":           "2m",
+		"MAX_REQUEST_BODY_BYTES": "4194304",
+		"MAX_HEADER_BYTES":       "16384",
+	}
+	cfg, err := loadConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.RequestTimeout != 25*time.Second || cfg.WriteTimeout != 30*time.Second ||
+		cfg.ShutdownTimeout != 25*time.Second || cfg.ReadTimeout != 10*time.Second ||
+		cfg.ReadHeaderTimeout != 500*time.Millisecond || cfg.IdleTimeout != 2*time.Minute ||
+		cfg.MaxBodyBytes != 4<<20 || cfg.MaxHeaderBytes != 16<<10 {
+		t.Fatalf("unexpected limits: %#v", cfg)
+	}
+}
+
+func TestRouterEnforcesConfiguredBodyLimit(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxBodyBytes = 1 << 10
+	router := testRouter(t, cfg)
+	for size, want := range map[int]int{100: http.StatusOK, 2 << 10: http.StatusRequestEntityTooLarge} {
+		body := `{"name":"` + strings.Repeat("a", size) + `"}`
+		request := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/hello", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		if response.Code != want {
+			t.Fatalf("%d-byte name: expected %d, got %d: %s", size, want, response.Code, response.Body.String())
+		}
+	}
+}
+
+func TestLoadConfigProtocols(t *testing.T) {
+	cfg := testConfig(t)
+	if cfg.H2C || cfg.HTTP3 || cfg.TLSCertFile != "" {
+		t.Fatalf("expected HTTP/1.1 without TLS by default: %#v", cfg)
+	}
+	cfg, err := loadConfig(func(key string) string { return map[string]string{"H2C_ENABLED": "true"}[key] })
+	if err != nil || !cfg.H2C {
+		t.Fatalf("expected h2c to be enabled, got %v %#v", err, cfg)
+	}
+	values := map[string]string{"TLS_CERT_FILE": "tls.crt", "TLS_KEY_FILE": "tls.key", "HTTP3_ENABLED": "1"}
+	cfg, err = loadConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.TLSCertFile != "tls.crt" || cfg.TLSKeyFile != "tls.key" || !cfg.HTTP3 {
+		t.Fatalf("unexpected TLS configuration: %#v", cfg)
+	}
+}
+
+// testCursorKeys is a valid CURSOR_SIGNING_KEYS value with a single key.
+var testCursorKeys = "k1:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("k"), 32))
+
+func TestLoadConfigCursors(t *testing.T) {
+	cfg := testConfig(t)
+	if len(cfg.Cursors.Keys) != 1 || cfg.Cursors.TTL != 24*time.Hour || cfg.Cursors.AcceptUnsigned {
+		t.Fatalf("expected an ephemeral development key and a 24h TTL: %#v", cfg.Cursors)
+	}
+	retired := "k0:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("0"), 32))
+	values := map[string]string{
+		"CURSOR_SIGNING_KEYS":    testCursorKeys + ", " + retired,
+		"CURSOR_TTL":             "0",
+		"CURSOR_ACCEPT_UNSIGNED": "true",
+	}
+	cfg, err := loadConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(cfg.Cursors.Keys) != 2 || cfg.Cursors.Keys[0].ID != "k1" || cfg.Cursors.Keys[1].ID != "k0" {
+		t.Fatalf("expected keys in configured order: %#v", cfg.Cursors.Keys)
+	}
+	if cfg.Cursors.TTL != 0 || !cfg.Cursors.AcceptUnsigned {
+		t.Fatalf("unexpected cursor configuration: %#v", cfg.Cursors)
+	}
 }
 
 func TestLoadConfigRejectsUnsafeCombinations(t *testing.T) {
@@ -84,6 +264,49 @@ func TestLoadConfigRejectsUnsafeCombinations(t *testing.T) {
 	}{
 		{name: "invalid port", env: map[string]string{"PORT": "70000"}},
 		{name: "invalid host", env: map[string]string{"HOST": "not a host"}},
+		{name: "invalid metrics port", env: map[string]string{"METRICS_PORT": "metrics"}},
+		{name: "metrics port reuses API port", env: map[string]string{"PORT": "9090", "METRICS_PORT": "9090"}},
+		{name: "invalid trusted proxy", env: map[string]string{"TRUSTED_PROXIES": "10.0.0.0/33"}},
+		{name: "empty trusted proxy", env: map[string]string{"TRUSTED_PROXIES": "10.0.0.1,"}},
+		{name: "too many trusted hops", env: map[string]string{"TRUSTED_PROXY_HOPS": "11"}},
+		{name: "unknown trusted proxy header", env: map[string]string{"TRUSTED_PROXY_HEADER": "x-real-ip"}},
+		{name: "invalid rate limit", env: map[string]string{"RATE_LIMIT_PER_MINUTE": "-1"}},
+		{name: "invalid request timeout", env: map[string]string{"REQUEST_TIMEOUT": "8"}},
+		{name: "request timeout too short", env: map[string]string{"REQUEST_TIMEOUT": "10ms"}},
+		{name: "idle timeout too long", env: map[string]string{"IDLE_TIMEOUT": "2h"}},
+		{name: "request timeout reaches write timeout", env: map[string]string{"REQUEST_TIMEOUT": "10s"}},
+		{name: "header timeout exceeds read timeout", env: map[string]string{"READ_HEADER_TIMEOUT": "6s"}},
+		{name: "shutdown shorter than request", env: map[string]string{"SHUTDOWN_TIMEOUT": "5s"}},
+		{name: "body limit too small", env: map[string]string{"MAX_REQUEST_BODY_BYTES": "512"}},
+		{name: "invalid body limit", env: map[string]string{"MAX_REQUEST_BODY_BYTES": "1MiB"}},
+		{name: "header limit too large", env: map[string]string{"MAX_HEADER_BYTES": "2097152"}},
+		{name: "invalid h2c flag", env: map[string]string{"H2C_ENABLED": "yes"}},
+		{name: "TLS certificate without key", env: map[string]string{"TLS_CERT_FILE": "tls.crt"}},
+		{name: "TLS key without certificate", env: map[string]string{"TLS_KEY_FILE": "tls.key"}},
+		{
+			name: "h2c with TLS",
+			env:  map[string]string{"H2C_ENABLED": "true", "TLS_CERT_FILE": "tls.crt", "TLS_KEY_FILE": "tls.key"},
+		},
+		{name: "HTTP/3 without TLS", env: map[string]string{"HTTP3_ENABLED": "true"}},
+		{name: "production without cursor keys", env: map[string]string{
+			"APP_ENVIRONMENT":     "production",
+			"FIREBASE_MODE":       "live",
+			"FIREBASE_PROJECT_ID": "real-project",
+		}},
+		{
+			name: "cursor key without ID",
+			env:  map[string]string{"CURSOR_SIGNING_KEYS": strings.TrimPrefix(testCursorKeys, "k1:")},
+		},
+		{name: "cursor key not Base64", env: map[string]string{"CURSOR_SIGNING_KEYS": "k1:not base64"}},
+		{name: "short cursor key", env: map[string]string{"CURSOR_SIGNING_KEYS": "k1:c2hvcnQ="}},
+		{
+			name: "duplicate cursor key ID",
+			env:  map[string]string{"CURSOR_SIGNING_KEYS": testCursorKeys + "," + testCursorKeys},
+		},
+		{name: "short cursor TTL", env: map[string]string{"CURSOR_TTL": "30s"}},
+		{name: "long cursor TTL", env: map[string]string{"CURSOR_TTL": "2161h"}},
+		{name: "invalid unsigned cursor flag", env: map[string]string{"CURSOR_ACCEPT_UNSIGNED": "maybe"}},
+		{name: "invalid traces exporter", env: map[string]string{"TRACES_EXPORTER": "jaeger"}},
 		{name: "invalid environment", env: map[string]string{"APP_ENVIRONMENT": "prod"}},
 		{name: "unsafe log level", env: map[string]string{"LOG_LEVEL": "fatal"}},
 		{name: "undocumented log level alias", env: map[string]string{"LOG_LEVEL": "warning"}},
@@ -134,6 +357,7 @@ func TestLoadConfigRejectsUnsafeCombinations(t *testing.T) {
 			name: "production emulators",
 			env: map[string]string{
 				"APP_ENVIRONMENT":             "production",
+				"CURSOR_SIGNING_KEYS":         testCursorKeys,
 				"FIREBASE_MODE":               "emulator",
 				"FIREBASE_PROJECT_ID":         "demo-test",
 				"FIREBASE_AUTH_EMULATOR_HOST": "localhost:7110",
@@ -144,6 +368,7 @@ func TestLoadConfigRejectsUnsafeCombinations(t *testing.T) {
 			name: "production wildcard CORS",
 			env: map[string]string{
 				"APP_ENVIRONMENT":      "production",
+				"CURSOR_SIGNING_KEYS":  testCursorKeys,
 				"FIREBASE_MODE":        "live",
 				"FIREBASE_PROJECT_ID":  "real-project",
 				"CORS_ALLOWED_ORIGINS": "*",
@@ -163,6 +388,7 @@ func TestLoadConfigRejectsUnsafeCombinations(t *testing.T) {
 func TestLoadConfigProduction(t *testing.T) {
 	values := map[string]string{
 		"APP_ENVIRONMENT":      "production",
+		"CURSOR_SIGNING_KEYS":  testCursorKeys,
 		"FIREBASE_MODE":        "live",
 		"FIREBASE_PROJECT_ID":  "real-project",
 		"CORS_ALLOWED_ORIGINS": "https://example.com, https://admin.example.com",
@@ -197,6 +423,8 @@ func TestRouterServesHealthDocsAndOpenAPI(t *testing.T) {
 		want int
 	}{
 		{path: "/health", want: http.StatusOK},
+		{path: "/livez", want: http.StatusOK},
+		{path: "/readyz", want: http.StatusOK},
 		{path: "/v1/api-docs", want: http.StatusOK},
 		{path: "/v1/openapi.json", want: http.StatusOK},
 		{path: "/v1/schemas/ErrorModel.json", want: http.StatusOK},
@@ -408,13 +636,133 @@ func TestRouterDoesNotTrustForwardedHostForSchemaLinks(t *testing.T) {
 	}
 }
 
-func TestRouterRejectsUnknownQuery(t *testing.T) {
+func TestRouterUsesTrustedProxyForSchemaLinks(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.TrustedProxies = appmiddleware.TrustedProxies{Hops: 1}
+	router := testRouter(t, cfg)
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "http://10.0.0.5:8080/v1/hello", nil)
+	request.Header.Set("X-Forwarded-For", "203.0.113.9")
+	request.Header.Set("X-Forwarded-Proto", "https")
+	request.Header.Set("X-Forwarded-Host", "api.example.com")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", response.Code, response.Body.String())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	schema, _ := body["$schema"].(string)
+	if !strings.HasPrefix(schema, "https://api.example.com/v1/schemas/") {
+		t.Fatalf("unexpected schema URL %q", schema)
+	}
+}
+
+func TestRouterDoesNotReplayUnavailableProfileCreate(t *testing.T) {
+	router := testRouter(t, testConfig(t))
+	for attempt := range 2 {
+		request := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/profile",
+			strings.NewReader(`{"firstName":"Ada","lastName":"Lovelace","contactEmail":"ada@example.com",`+
+				`"phoneNumber":"+358401234567","marketing":false}`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer token")
+		request.Header.Set(idempotency.HeaderName, "create-profile-1")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		if response.Code != http.StatusServiceUnavailable {
+			t.Fatalf("attempt %d: expected 503, got %d: %s", attempt, response.Code, response.Body.String())
+		}
+		if response.Header().Get(idempotency.ReplayedHeader) != "" {
+			t.Fatalf("attempt %d: expected a transient failure not to be replayed", attempt)
+		}
+	}
+}
+
+func TestRouterCompressesLargeResponses(t *testing.T) {
 	router := testRouter(t, testConfig(t))
-	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/hello?typo=true", nil)
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/openapi.json", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
 	response := httptest.NewRecorder()
 	router.ServeHTTP(response, request)
-	if response.Code != http.StatusUnprocessableEntity {
-		t.Fatalf("expected 422, got %d: %s", response.Code, response.Body.String())
+	if response.Code != http.StatusOK || response.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip-encoded 200, got %d %q", response.Code, response.Header().Get("Content-Encoding"))
+	}
+	if vary := strings.Join(response.Header().Values("Vary"), ","); !strings.Contains(vary, "Accept-Encoding") {
+		t.Fatalf("expected Vary to include Accept-Encoding, got %q", vary)
+	}
+	reader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	var document map[string]any
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		t.Fatalf("decode compressed OpenAPI: %v", err)
+	}
+
+	request = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	if response.Header().Get("Content-Encoding") != "" {
+		t.Fatal("expected small health response to stay uncompressed")
+	}
+}
+
+func TestRouterDecompressesRequestBodies(t *testing.T) {
+	cfg := testConfig(t)
+	router := testRouter(t, cfg)
+	post := func(encoding string, body []byte) *httptest.ResponseRecorder {
+		request := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/hello", bytes.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Content-Encoding", encoding)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+	gzipped := func(data []byte) []byte {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, _ = writer.Write(data)
+		_ = writer.Close()
+		return buf.Bytes()
+	}
+
+	if response := post("gzip", gzipped([]byte(`{"name":"Ada"}`))); response.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", response.Code, response.Body.String())
+	}
+
+	response := post("deflate", []byte(`{"name":"Ada"}`))
+	if response.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415, got %d: %s", response.Code, response.Body.String())
+	}
+	if response.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("expected Problem Details, got %q", response.Header().Get("Content-Type"))
+	}
+	if response.Header().Get("Accept-Encoding") == "" {
+		t.Fatal("expected Accept-Encoding to list supported codings")
+	}
+
+	bomb := gzipped(bytes.Repeat([]byte(" "), int(2*cfg.MaxBodyBytes)))
+	if response := post("gzip", bomb); response.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for oversized decompressed body, got %d", response.Code)
+	}
+}
+
+func TestRouterRejectsUnknownQuery(t *testing.T) {
+	router := testRouter(t, testConfig(t))
+	for _, target := range []string{
+		"/v1/hello?typo=true",
+		"/v1/github/repos/octocat/hello-world/pulls?labels=bug",
+	} {
+		t.Run(target, func(t *testing.T) {
+			request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, target, nil)
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+			if response.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", response.Code, response.Body.String())
+			}
+		})
 	}
 }
 
@@ -425,6 +773,7 @@ func TestRouterMethodNotAllowedIncludesAllow(t *testing.T) {
 		allow string
 	}{
 		{path: "/health", allow: "GET"},
+		{path: "/readyz", allow: "GET"},
 		{path: "/v1/hello", allow: "GET, POST"},
 		{path: "/v1/profile", allow: "GET, POST, PATCH, DELETE"},
 	}
@@ -443,9 +792,84 @@ func TestRouterMethodNotAllowedIncludesAllow(t *testing.T) {
 	}
 }
 
+func TestRouterRateLimitsPerPolicy(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.RateLimitPerMin = 4
+	router := testRouter(t, cfg)
+	get := func(path string) *httptest.ResponseRecorder {
+		request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	for range 4 {
+		if response := get("/v1/hello"); response.Code != http.StatusOK {
+			t.Fatalf("expected 200 within the limit, got %d", response.Code)
+		}
+	}
+	response := get("/v1/hello")
+	if response.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", response.Code, response.Body.String())
+	}
+	if got := response.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Fatalf("expected Problem Details, got %q", got)
+	}
+	if response.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After")
+	}
+	if got := response.Header().Get("RateLimit-Policy"); got != `"default";q=4;w=60` {
+		t.Fatalf("unexpected RateLimit-Policy %q", got)
+	}
+
+	// Operations under the same policy share one bucket; health probes are never limited.
+	if response := get("/v1/items?limit=1"); response.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected default policy to be shared by /v1/items, got %d", response.Code)
+	}
+	if response := get("/health"); response.Code != http.StatusOK {
+		t.Fatalf("expected health to bypass rate limiting, got %d", response.Code)
+	}
+}
+
+func TestRouterRateLimitingCanBeDisabled(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.RateLimitPerMin = 0
+	router := testRouter(t, cfg)
+	for range 3 {
+		request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/hello", nil)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		if response.Code != http.StatusOK || response.Header().Get("RateLimit") != "" {
+			t.Fatalf("expected unlimited response, got %d with RateLimit %q",
+				response.Code, response.Header().Get("RateLimit"))
+		}
+	}
+}
+
+func TestRateLimitPolicySelection(t *testing.T) {
+	policy := rateLimitPolicy(120)
+	tests := []struct {
+		op   huma.Operation
+		want string
+	}{
+		{huma.Operation{Method: http.MethodGet, Tags: []string{"Hello"}}, "default"},
+		{huma.Operation{Method: http.MethodGet, Tags: []string{"GitHub"}}, "github"},
+		{huma.Operation{Method: http.MethodGet, Tags: []string{"Profile"}}, "default"},
+		{huma.Operation{Method: http.MethodPatch, Tags: []string{"Profile"}}, "profile-write"},
+	}
+	for _, tt := range tests {
+		if got := policy(&tt.op); got.Name != tt.want {
+			t.Errorf("%s %v: expected %q, got %q", tt.op.Method, tt.op.Tags, tt.want, got.Name)
+		}
+	}
+	if got := policy(&huma.Operation{Tags: []string{"GitHub"}}).Limit; got != 30 {
+		t.Errorf("expected GitHub limit 30, got %d", got)
+	}
+}
+
 func TestOfflineModeFailsProtectedRoutesClosed(t *testing.T) {
 	cfg := testConfig(t)
-	clients, err := newApplicationClients(t.Context(), cfg, zap.NewNop())
+	clients, err := newApplicationClients(t.Context(), cfg, noop.NewTracerProvider(), zap.NewNop())
 	if err != nil {
 		t.Fatalf("new clients: %v", err)
 	}
@@ -454,7 +878,11 @@ func TestOfflineModeFailsProtectedRoutesClosed(t *testing.T) {
 			t.Errorf("close Firebase clients: %v", closeErr)
 		}
 	})
-	router := newRouter(cfg, clients.dependencies, zap.NewNop())
+	if len(clients.checks) != 1 || clients.checks[0].Name != "github" || clients.checks[0].Critical {
+		t.Fatalf("expected only the non-critical GitHub check offline, got %#v", clients.checks)
+	}
+	router := newRouter(cfg, clients.dependencies, testActivityFeed(t, clients.github), testReadiness(),
+		testTelemetry(), zap.NewNop())
 	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/profile", nil)
 	request.Header.Set("Authorization", "Bearer local-token")
 	response := httptest.NewRecorder()
@@ -479,21 +907,6 @@ func TestRouterUsesConfiguredPrefix(t *testing.T) {
 	}
 }
 
-func TestRequestContextTimeout(t *testing.T) {
-	handler := requestContextTimeout(time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		<-r.Context().Done()
-		if !errors.Is(r.Context().Err(), context.DeadlineExceeded) {
-			t.Errorf("unexpected context error: %v", r.Context().Err())
-		}
-		w.WriteHeader(http.StatusNoContent)
-	}))
-	response := httptest.NewRecorder()
-	handler.ServeHTTP(response, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil))
-	if response.Code != http.StatusNoContent {
-		t.Fatalf("expected 204, got %d", response.Code)
-	}
-}
-
 func TestServerConfiguration(t *testing.T) {
 	cfg := testConfig(t)
 	server := newServer(cfg, http.NotFoundHandler())
@@ -505,6 +918,215 @@ func TestServerConfiguration(t *testing.T) {
 	}
 }
 
+func TestServerProtocols(t *testing.T) {
+	cfg := testConfig(t)
+	if server := newServer(cfg, http.NotFoundHandler()); server.Protocols != nil {
+		t.Fatalf("expected default protocols, got %v", server.Protocols)
+	}
+	cfg.H2C = true
+	server := newServer(cfg, http.NotFoundHandler())
+	if server.Protocols == nil || !server.Protocols.HTTP1() || !server.Protocols.UnencryptedHTTP2() {
+		t.Fatalf("expected HTTP/1.1 and h2c, got %v", server.Protocols)
+	}
+}
+
+func TestRouterRecordsOperationMetrics(t *testing.T) {
+	cfg := testConfig(t)
+	m := metrics.New()
+	githubClient, err := githubsvc.NewClient(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("create GitHub client: %v", err)
+	}
+	router := newRouter(cfg, dependencies{
+		verifier: &stubVerifier{User: testUser()},
+		items:    itemsvc.NewMemoryStore(itemsvc.SampleItems()...),
+		profiles: unavailableProfileStore{},
+		github:   githubClient,
+		cursors:  testutil.Cursors(),
+	}, testActivityFeed(t, githubClient), testReadiness(), telemetry{metrics: m, tracerProvider: noop.NewTracerProvider()},
+		zap.NewNop())
+	for _, path := range []string{"/v1/items?limit=1", "/v1/items?limit=0"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil))
+	}
+
+	metricsServer := newMetricsServer(cfg, m)
+	response := httptest.NewRecorder()
+	metricsServer.Handler.ServeHTTP(response,
+		httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil))
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", response.Code)
+	}
+	body := response.Body.String()
+	for _, want := range []string{
+		`http_server_requests_total{operation="list-items",route="/items",status_class="2xx"} 1`,
+		`http_server_requests_total{operation="list-items",route="/items",status_class="4xx"} 1`,
+		`http_server_requests_in_flight{operation="list-items",route="/items"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics output:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsServerServesOnlyMetrics(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MetricsAddress = "127.0.0.1:9090"
+	server := newMetricsServer(cfg, metrics.New())
+	if server.Addr != cfg.MetricsAddress || server.ReadHeaderTimeout != cfg.ReadHeaderTimeout {
+		t.Fatalf("unexpected metrics server: %#v", server)
+	}
+	for _, test := range []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{method: http.MethodGet, path: "/metrics", want: http.StatusOK},
+		{method: http.MethodPost, path: "/metrics", want: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/health", want: http.StatusNotFound},
+	} {
+		response := httptest.NewRecorder()
+		server.Handler.ServeHTTP(response, httptest.NewRequestWithContext(t.Context(), test.method, test.path, nil))
+		if response.Code != test.want {
+			t.Errorf("%s %s: expected %d, got %d", test.method, test.path, test.want, response.Code)
+		}
+	}
+}
+
+func TestRouterRecordsOperationSpans(t *testing.T) {
+	cfg := testConfig(t)
+	exporter := tracetest.NewInMemoryExporter()
+	githubClient, err := githubsvc.NewClient(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("create GitHub client: %v", err)
+	}
+	router := newRouter(cfg, dependencies{
+		verifier: &stubVerifier{User: testUser()},
+		items:    itemsvc.NewMemoryStore(itemsvc.SampleItems()...),
+		profiles: unavailableProfileStore{},
+		github:   githubClient,
+		cursors:  testutil.Cursors(),
+	}, testActivityFeed(t, githubClient), testReadiness(), telemetry{
+		metrics:        metrics.New(),
+		tracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	}, zap.NewNop())
+
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/items?limit=1", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /items" || span.SpanKind != trace.SpanKindServer {
+		t.Fatalf("unexpected span %q of kind %v", span.Name, span.SpanKind)
+	}
+	if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected incoming trace to be continued, got %s", span.SpanContext.TraceID())
+	}
+}
+
+func TestInstrumentedDependenciesRecordSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	deps := instrumentDependencies(dependencies{
+		verifier: &stubVerifier{User: testUser()},
+		profiles: unavailableProfileStore{},
+		github:   githubsvc.Service(nil),
+	}, telemetry{
+		metrics:        metrics.New(),
+		tracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	})
+
+	if _, err := deps.verifier.Verify(t.Context(), "token"); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if _, err := deps.profiles.Get(t.Context(), "user"); !errors.Is(err, profilesvc.ErrUnavailable) {
+		t.Fatalf("expected store error to pass through, got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	verify, get := spans[0], spans[1]
+	if verify.Name != "firebase_auth verify_id_token" || verify.SpanKind != trace.SpanKindClient ||
+		verify.Status.Code != codes.Unset {
+		t.Fatalf("unexpected verifier span %q: kind %v, status %v", verify.Name, verify.SpanKind, verify.Status)
+	}
+	if get.Name != "firestore get_profile" || get.Status.Code != codes.Error ||
+		get.Status.Description != "unavailable" {
+		t.Fatalf("unexpected store span %q: status %v", get.Name, get.Status)
+	}
+}
+
+func TestInstrumentedDependenciesRecordOutcomes(t *testing.T) {
+	m := metrics.New()
+	deps := instrumentDependencies(dependencies{
+		verifier: &stubVerifier{Error: errors.Join(auth.ErrTokenExpired, errors.New("expired"))},
+		profiles: unavailableProfileStore{},
+		github:   githubsvc.Service(nil),
+	}, telemetry{metrics: m, tracerProvider: noop.NewTracerProvider()})
+
+	if _, err := deps.verifier.Verify(t.Context(), "token"); !errors.Is(err, auth.ErrTokenExpired) {
+		t.Fatalf("expected verifier error to pass through, got %v", err)
+	}
+	if err := deps.profiles.Delete(t.Context(), "user"); !errors.Is(err, profilesvc.ErrUnavailable) {
+		t.Fatalf("expected store error to pass through, got %v", err)
+	}
+
+	response := httptest.NewRecorder()
+	m.Handler().ServeHTTP(response, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil))
+	body := response.Body.String()
+	for _, want := range []string{
+		`dependency_requests_total{dependency="firebase_auth",operation="verify_id_token",outcome="rejected"} 1`,
+		`dependency_requests_total{dependency="firestore",operation="delete_profile",outcome="unavailable"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics output", want)
+		}
+	}
+}
+
+func TestClassifyDependencyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		classify func(error) string
+		err      error
+		want     string
+	}{
+		{
+			"verifier timeout",
+			classifyVerifierError,
+			errors.Join(auth.ErrAuthUnavailable, context.DeadlineExceeded),
+			"timeout",
+		},
+		{
+			"verifier unavailable",
+			classifyVerifierError,
+			errors.Join(auth.ErrCertificateFetch, auth.ErrAuthUnavailable),
+			"unavailable",
+		},
+		{"verifier revoked", classifyVerifierError, auth.ErrTokenRevoked, "rejected"},
+		{"profile canceled", classifyProfileError, context.Canceled, "canceled"},
+		{"profile exists", classifyProfileError, profilesvc.ErrAlreadyExists, "already_exists"},
+		{"profile not found", classifyProfileError, profilesvc.ErrNotFound, "not_found"},
+		{"github rate limited", classifyGitHubError, &githubsvc.UpstreamError{
+			Kind: githubsvc.UpstreamErrorKindRateLimited,
+		}, "rate_limited"},
+		{"github rate limit sentinel", classifyGitHubError, githubsvc.ErrRateLimited, "rate_limited"},
+		{"github too large", classifyGitHubError, githubsvc.ErrContentTooLarge, "rejected"},
+		{"github upstream", classifyGitHubError, githubsvc.ErrUpstream, "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.classify(tt.err); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestServeReturnsListenError(t *testing.T) {
 	var listenConfig net.ListenConfig
 	listener, err := listenConfig.Listen(t.Context(), "tcp", "127.0.0.1:0")
@@ -521,7 +1143,7 @@ func TestServeReturnsListenError(t *testing.T) {
 		Handler:           http.NotFoundHandler(),
 		ReadHeaderTimeout: time.Second,
 	}
-	err = serve(t.Context(), server, time.Second, zap.NewNop())
+	err = serve(t.Context(), server, time.Second, nil, zap.NewNop())
 	if err == nil || !strings.Contains(err.Error(), "address already in use") {
 		t.Fatalf("expected address-in-use error, got %v", err)
 	}
@@ -535,11 +1157,242 @@ func TestServeDoesNotStartWithCancelledContext(t *testing.T) {
 		Handler:           http.NotFoundHandler(),
 		ReadHeaderTimeout: time.Second,
 	}
-	if err := serve(ctx, server, time.Second, zap.NewNop()); err != nil {
+	if err := serve(ctx, server, time.Second, nil, zap.NewNop()); err != nil {
 		t.Fatalf("expected canceled startup to be a clean no-op, got %v", err)
 	}
 }
 
+func TestServeFailsReadinessWhenShutdownBegins(t *testing.T) {
+	var listenConfig net.ListenConfig
+	listener, err := listenConfig.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("reserve port: %v", err)
+	}
+	addr := listener.Addr().String()
+	if err := listener.Close(); err != nil {
+		t.Fatalf("release port: %v", err)
+	}
+
+	shutdown := &health.Shutdown{}
+	readiness := health.NewReadiness(time.Second, health.Check{Name: "shutdown", Critical: true, Checker: shutdown})
+	server := &http.Server{Addr: addr, Handler: http.HandlerFunc(readiness.Handler), ReadHeaderTimeout: time.Second}
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, server, time.Second, shutdown.Begin, zap.NewNop())
+	}()
+
+	var dialer net.Dialer
+	for {
+		conn, dialErr := dialer.DialContext(t.Context(), "tcp", addr)
+		if dialErr == nil {
+			_ = conn.Close()
+			break
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("serve exited before listening: %v", err)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	if response := readiness.Evaluate(t.Context()); response.Status != health.StatusReady {
+		t.Fatalf("expected ready while serving, got %q", response.Status)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	if response := readiness.Evaluate(t.Context()); response.Status != health.StatusNotReady {
+		t.Fatalf("expected not ready after shutdown began, got %q", response.Status)
+	}
+}
+
+// reserveAddress returns a loopback address that was free for network when it was checked.
+func reserveAddress(t *testing.T, network string) string {
+	t.Helper()
+	var listenConfig net.ListenConfig
+	if network == "udp" {
+		conn, err := listenConfig.ListenPacket(t.Context(), network, "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("reserve port: %v", err)
+		}
+		addr := conn.LocalAddr().String()
+		if err := conn.Close(); err != nil {
+			t.Fatalf("release port: %v", err)
+		}
+		return addr
+	}
+	listener, err := listenConfig.Listen(t.Context(), network, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("reserve port: %v", err)
+	}
+	addr := listener.Addr().String()
+	if err := listener.Close(); err != nil {
+		t.Fatalf("release port: %v", err)
+	}
+	return addr
+}
+
+// getUntilServing retries GET url until the server answers, failing if serve exits first.
+func getUntilServing(t *testing.T, client *http.Client, url string, done <-chan error) *http.Response {
+	t.Helper()
+	for {
+		request, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		response, err := client.Do(request)
+		if err == nil {
+			t.Cleanup(func() { _ = response.Body.Close() })
+			return response
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("serve exited before answering: %v", err)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// protocolHandler answers with the protocol the request arrived over.
+var protocolHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(r.Proto))
+})
+
+func TestServeH2C(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Address = reserveAddress(t, "tcp")
+	cfg.H2C = true
+	server := newServer(cfg, protocolHandler)
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, server, time.Second, nil, zap.NewNop())
+	}()
+
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	t.Cleanup(transport.CloseIdleConnections)
+	response := getUntilServing(t, &http.Client{Transport: transport}, "http://"+cfg.Address+"/", done)
+	if response.ProtoMajor != 2 {
+		t.Fatalf("expected HTTP/2 without TLS, got %s", response.Proto)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+}
+
+// testCertificates writes a self-signed localhost certificate and returns a reloader for it and a
+// pool that trusts it.
+func testCertificates(t *testing.T) (*tlscert.Reloader, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	for path, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	certificates, err := tlscert.NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("load certificates: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return certificates, pool
+}
+
+func TestServeTLSNegotiatesHTTP2(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Address = reserveAddress(t, "tcp")
+	certificates, pool := testCertificates(t)
+	server := newServer(cfg, protocolHandler)
+	server.TLSConfig = certificates.TLSConfig()
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, server, time.Second, nil, zap.NewNop())
+	}()
+
+	transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}
+	transport.Protocols = new(http.Protocols)
+	transport.Protocols.SetHTTP2(true)
+	t.Cleanup(transport.CloseIdleConnections)
+	response := getUntilServing(t, &http.Client{Transport: transport}, "https://"+cfg.Address+"/", done)
+	if response.ProtoMajor != 2 {
+		t.Fatalf("expected HTTP/2 over TLS, got %s", response.Proto)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+}
+
+func TestServeHTTP3(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Address = reserveAddress(t, "udp")
+	certificates, pool := testCertificates(t)
+	server := newHTTP3Server(cfg, protocolHandler, certificates.TLSConfig())
+	shutdown := &health.Shutdown{}
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- serveHTTP3(ctx, server, time.Second, shutdown.Begin, zap.NewNop())
+	}()
+
+	transport := &http3.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS13}}
+	t.Cleanup(func() { _ = transport.Close() })
+	client := &http.Client{Transport: transport, Timeout: time.Second}
+	response := getUntilServing(t, client, "https://"+cfg.Address+"/", done)
+	if response.ProtoMajor != 3 {
+		t.Fatalf("expected HTTP/3, got %s", response.Proto)
+	}
+
+	advertised := httptest.NewRecorder()
+	advertiseHTTP3(server, http.NotFoundHandler()).ServeHTTP(advertised,
+		httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil))
+	_, port, _ := net.SplitHostPort(cfg.Address)
+	if got := advertised.Header().Get("Alt-Svc"); !strings.Contains(got, `h3=":`+port+`"`) {
+		t.Fatalf("expected Alt-Svc to advertise port %s, got %q", port, got)
+	}
+
+	_ = transport.Close()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serveHTTP3: %v", err)
+	}
+	if shutdown.Check(t.Context()) == nil {
+		t.Fatal("expected shutdown to begin before the HTTP/3 server stopped")
+	}
+}
+
 func TestOpenAPIMediaTypesMatchRuntime(t *testing.T) {
 	router := testRouter(t, testConfig(t))
 	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/openapi.json", nil)
@@ -563,6 +1416,7 @@ func TestOpenAPIMediaTypesMatchRuntime(t *testing.T) {
 	}
 
 	problemResponses := 0
+	streamedResponses := 0
 	for path, methods := range document.Paths {
 		for method, operation := range methods {
 			if operation.RequestBody != nil {
@@ -586,12 +1440,77 @@ func TestOpenAPIMediaTypesMatchRuntime(t *testing.T) {
 				if hasProblemJSON {
 					problemResponses++
 				}
+				_, hasNDJSON := response.Content[stream.NDJSON]
+				_, hasCBORSeq := response.Content[stream.CBORSeq]
+				if hasNDJSON != hasCBORSeq {
+					t.Errorf("%s %s response %s NDJSON/CBOR sequence mismatch", method, path, status)
+				}
+				if hasNDJSON {
+					streamedResponses++
+				}
 			}
 		}
 	}
 	if problemResponses == 0 {
 		t.Fatal("OpenAPI contains no Problem Details responses")
 	}
+	// Items plus GitHub activity, releases, pulls, issues, and commits.
+	if streamedResponses != 6 {
+		t.Fatalf("expected 6 streamable list responses, got %d", streamedResponses)
+	}
+}
+
+func TestFieldSelectionPassesUnknownQueryRejection(t *testing.T) {
+	router := testRouter(t, testConfig(t))
+	get := func(path string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil))
+		return response
+	}
+
+	response := get("/v1/items?limit=1&fields=id,name")
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", response.Code, response.Body.String())
+	}
+	if got := strings.TrimSpace(response.Body.String()); got != `{"items":[{"id":"item-001","name":"Alpha Widget"}]}` {
+		t.Fatalf("expected pruned items, got %s", got)
+	}
+	if response := get("/v1/items?limit=1&field=id"); response.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected unknown query parameters to stay rejected, got %d", response.Code)
+	}
+
+	var document struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name   string `json:"name"`
+				Schema struct {
+					Items struct {
+						Enum []string `json:"enum"`
+					} `json:"items"`
+				} `json:"schema"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(get("/v1/openapi.json").Body.Bytes(), &document); err != nil {
+		t.Fatalf("decode OpenAPI: %v", err)
+	}
+	for path, want := range map[string][]string{
+		"/items":                 {"id", "name", "price"},
+		"/items/{id}":            {"id", "priceDisplay"},
+		"/github/owners/{owner}": {"avatarUrl", "login"},
+	} {
+		var enum []string
+		for _, param := range document.Paths[path]["get"].Parameters {
+			if param.Name == "fields" {
+				enum = param.Schema.Items.Enum
+			}
+		}
+		for _, name := range want {
+			if !slices.Contains(enum, name) {
+				t.Errorf("GET %s: expected fields to document %q, got %v", path, name, enum)
+			}
+		}
+	}
 }
 
 func TestOpenAPIResponseStatusesAndSecurityMatchRuntime(t *testing.T) {
@@ -617,24 +1536,53 @@ func TestOpenAPIResponseStatusesAndSecurityMatchRuntime(t *testing.T) {
 	githubStatuses := []string{"200", "403", "404", "422", "429", "500", "502", "503"}
 	expected := map[string]map[string][]string{
 		"/hello": {
-			"get":  {"200", "422", "500"},
-			"post": {"200", "400", "408", "413", "415", "422", "500"},
+			"get":  {"200", "422", "429", "500"},
+			"post": {"200", "400", "408", "413", "415", "422", "429", "500"},
+		},
+		"/items": {
+			"get":  {"200", "400", "422", "429", "500", "503"},
+			"post": {"201", "400", "401", "403", "408", "409", "413", "415", "422", "429", "500", "503"},
+		},
+		"/items/{id}": {
+			"delete": {"204", "401", "403", "404", "422", "429", "500", "503"},
+			"get":    {"200", "404", "422", "429", "500", "503"},
+			"patch":  {"200", "400", "401", "403", "404", "408", "413", "415", "422", "429", "500", "503"},
 		},
-		"/items": {"get": {"200", "400", "422", "500"}},
 		"/profile": {
-			"delete": {"204", "401", "404", "422", "500", "503"},
-			"get":    {"200", "401", "404", "422", "500", "503"},
-			"patch":  {"200", "400", "401", "404", "408", "413", "415", "422", "500", "503"},
-			"post":   {"201", "400", "401", "408", "409", "413", "415", "422", "500", "503"},
+			"delete": {"204", "401", "404", "422", "429", "500", "503"},
+			"get":    {"200", "401", "404", "422", "429", "500", "503"},
+			"patch":  {"200", "400", "401", "404", "408", "413", "415", "422", "429", "500", "503"},
+			"post":   {"201", "400", "401", "408", "409", "413", "415", "422", "429", "500", "503"},
 		},
+		"/batch":                       {"post": {"200", "400", "408", "413", "415", "422", "429", "500"}},
 		"/github/owners/{owner}":       {"get": githubStatuses},
 		"/github/owners/{owner}/repos": {"get": githubStatuses},
 		"/github/repos/{owner}/{repo}": {"get": githubStatuses},
 		"/github/repos/{owner}/{repo}/activity": {
 			"get": {"200", "400", "403", "404", "422", "429", "500", "502", "503"},
 		},
-		"/github/repos/{owner}/{repo}/languages": {"get": githubStatuses},
-		"/github/repos/{owner}/{repo}/tags":      {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/activity/events": {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/languages":       {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/tags":            {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/insights":        {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/releases": {
+			"get": {"200", "400", "403", "404", "422", "429", "500", "502", "503"},
+		},
+		"/github/repos/{owner}/{repo}/releases/latest":     {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/releases/tags/{tag}": {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/pulls": {
+			"get": {"200", "400", "403", "404", "422", "429", "500", "502", "503"},
+		},
+		"/github/repos/{owner}/{repo}/issues": {
+			"get": {"200", "400", "403", "404", "422", "429", "500", "502", "503"},
+		},
+		"/github/repos/{owner}/{repo}/commits": {
+			"get": {"200", "400", "403", "404", "422", "429", "500", "502", "503"},
+		},
+		"/github/repos/{owner}/{repo}/compare/{basehead}": {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/readme":             {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/contents":           {"get": githubStatuses},
+		"/github/repos/{owner}/{repo}/contents/{path}":    {"get": githubStatuses},
 	}
 	operationIDs := make(map[string]string)
 	operationCount := 0
@@ -657,14 +1605,19 @@ func TestOpenAPIResponseStatusesAndSecurityMatchRuntime(t *testing.T) {
 				operationIDs[operation.OperationID] = method + " " + path
 			}
 			hasBearer := false
+			var roles []string
 			for _, requirement := range operation.Security {
-				if _, ok := requirement[auth.BearerAuthScheme]; ok {
-					hasBearer = true
+				if scopes, ok := requirement[auth.BearerAuthScheme]; ok {
+					hasBearer, roles = true, scopes
 				}
 			}
-			if wantBearer := path == "/profile"; hasBearer != wantBearer {
+			itemWrite := strings.HasPrefix(path, "/items") && method != "get"
+			if wantBearer := path == "/profile" || itemWrite; hasBearer != wantBearer {
 				t.Errorf("%s %s bearer security = %t, want %t", method, path, hasBearer, wantBearer)
 			}
+			if itemWrite && !slices.Equal(roles, []string{auth.RoleAdmin}) {
+				t.Errorf("%s %s roles = %v, want admin", method, path, roles)
+			}
 		}
 	}
 	actualOperationCount := 0
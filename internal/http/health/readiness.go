@@ -0,0 +1,199 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
+)
+
+// Readiness statuses. A failing non-critical check degrades readiness without failing it.
+const (
+	StatusReady    = "ready"
+	StatusDegraded = "degraded"
+	StatusNotReady = "not_ready"
+)
+
+// Check statuses.
+const (
+	CheckPass = "pass"
+	CheckFail = "fail"
+)
+
+// ErrShuttingDown is reported by Shutdown once graceful shutdown has begun.
+var ErrShuttingDown = errors.New("shutting down")
+
+// Checker probes one dependency. A nil error means the dependency can serve traffic.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Check registers a Checker with Readiness.
+type Check struct {
+	Name string
+	// Critical checks fail readiness when they fail; other checks only degrade it.
+	Critical bool
+	// CacheTTL reuses a result for this long so frequent probes do not load the dependency.
+	// Zero runs the check on every request.
+	CacheTTL time.Duration
+	Checker  Checker
+}
+
+// CheckResult is the reported outcome of one check. Error is a bounded category; the underlying
+// error is logged, never returned, because probes are unauthenticated.
+type CheckResult struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMS float64   `json:"latencyMs"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Cached    bool      `json:"cached"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ReadinessResponse is the payload for the readiness endpoint.
+type ReadinessResponse struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Readiness runs registered checks concurrently and caches their results.
+type Readiness struct {
+	timeout time.Duration
+	checks  []*cachedCheck
+}
+
+type cachedCheck struct {
+	Check
+
+	mu     sync.Mutex
+	result CheckResult
+	err    error
+	valid  bool
+}
+
+// NewReadiness creates a readiness evaluator. Each check run is bounded by timeout.
+func NewReadiness(timeout time.Duration, checks ...Check) *Readiness {
+	r := &Readiness{timeout: timeout, checks: make([]*cachedCheck, len(checks))}
+	for i, check := range checks {
+		r.checks[i] = &cachedCheck{Check: check}
+	}
+	return r
+}
+
+// Evaluate returns the current readiness, running checks whose cached result has expired.
+func (r *Readiness) Evaluate(ctx context.Context) ReadinessResponse {
+	response := ReadinessResponse{Status: StatusReady, Checks: make([]CheckResult, len(r.checks))}
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Go(func() {
+			response.Checks[i] = check.run(ctx, r.timeout)
+		})
+	}
+	wg.Wait()
+
+	for _, result := range response.Checks {
+		if result.Status == CheckPass {
+			continue
+		}
+		if result.Critical {
+			response.Status = StatusNotReady
+			break
+		}
+		response.Status = StatusDegraded
+	}
+	return response
+}
+
+// run serializes refreshes so concurrent probes share one dependency call. The check runs detached
+// from the caller's cancellation so an abandoned probe cannot cache a spurious failure.
+func (c *cachedCheck) run(ctx context.Context, timeout time.Duration) CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.valid && c.CacheTTL > 0 && now.Sub(c.result.CheckedAt) < c.CacheTTL {
+		result := c.result
+		result.Cached = true
+		return result
+	}
+
+	checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	err := c.Checker.Check(checkCtx)
+	c.result = CheckResult{
+		Name:      c.Name,
+		Status:    CheckPass,
+		Critical:  c.Critical,
+		LatencyMS: float64(time.Since(now).Microseconds()) / 1000,
+		CheckedAt: now.UTC(),
+	}
+	if err != nil {
+		c.result.Status = CheckFail
+		c.result.Error = errorCategory(err)
+		// Log transitions into failure only, so frequent probes do not flood the log.
+		if !errors.Is(err, ErrShuttingDown) && (!c.valid || c.err == nil) {
+			obs.Logger(ctx).Warn("readiness check failed", zap.String("check", c.Name), zap.Error(err))
+		}
+	}
+	c.err = err
+	c.valid = true
+	return c.result
+}
+
+func errorCategory(err error) string {
+	switch {
+	case errors.Is(err, ErrShuttingDown):
+		return "shutting_down"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "unavailable"
+	}
+}
+
+// Handler serves the readiness report, with 503 when a critical check fails.
+func (r *Readiness) Handler(w http.ResponseWriter, req *http.Request) {
+	response := r.Evaluate(req.Context())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if response.Status == StatusNotReady {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		return
+	}
+}
+
+// Shutdown is a critical Checker that fails once graceful shutdown begins, so load balancers stop
+// routing new requests while in-flight ones drain.
+type Shutdown struct {
+	draining atomic.Bool
+}
+
+// Begin marks the process as shutting down. It is safe to call more than once.
+func (s *Shutdown) Begin() {
+	s.draining.Store(true)
+}
+
+// Check reports ErrShuttingDown after Begin.
+func (s *Shutdown) Check(context.Context) error {
+	if s.draining.Load() {
+		return ErrShuttingDown
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func serveReadiness(t *testing.T, r *Readiness) (int, ReadinessResponse) {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/readyz", nil)
+	resp := httptest.NewRecorder()
+	r.Handler(resp, req)
+
+	if ct := resp.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %s", ct)
+	}
+	var body ReadinessResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp.Code, body
+}
+
+func fixedCheck(err error) CheckerFunc {
+	return func(context.Context) error { return err }
+}
+
+func TestReadinessReportsEachCheck(t *testing.T) {
+	r := NewReadiness(time.Second,
+		Check{Name: "firestore", Critical: true, Checker: fixedCheck(nil)},
+		Check{Name: "github", Checker: fixedCheck(errors.New("dial tcp: connection refused"))},
+	)
+
+	code, body := serveReadiness(t, r)
+	if code != http.StatusOK || body.Status != StatusDegraded {
+		t.Fatalf("expected degraded 200, got %d %q", code, body.Status)
+	}
+	if len(body.Checks) != 2 {
+		t.Fatalf("expected 2 checks, got %d", len(body.Checks))
+	}
+	firestore, github := body.Checks[0], body.Checks[1]
+	if firestore.Name != "firestore" || firestore.Status != CheckPass || !firestore.Critical {
+		t.Errorf("unexpected firestore result: %#v", firestore)
+	}
+	if github.Status != CheckFail || github.Error != "unavailable" {
+		t.Errorf("expected bounded github failure, got %#v", github)
+	}
+	if firestore.CheckedAt.IsZero() || firestore.LatencyMS < 0 {
+		t.Errorf("expected timing details, got %#v", firestore)
+	}
+}
+
+func TestReadinessFailsOnCriticalCheck(t *testing.T) {
+	r := NewReadiness(time.Second,
+		Check{Name: "firestore", Critical: true, Checker: fixedCheck(errors.New("unavailable"))},
+	)
+
+	code, body := serveReadiness(t, r)
+	if code != http.StatusServiceUnavailable || body.Status != StatusNotReady {
+		t.Fatalf("expected not ready 503, got %d %q", code, body.Status)
+	}
+}
+
+func TestReadinessTimesOutSlowChecks(t *testing.T) {
+	r := NewReadiness(10*time.Millisecond, Check{
+		Name:     "firestore",
+		Critical: true,
+		Checker: CheckerFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	})
+
+	_, body := serveReadiness(t, r)
+	if got := body.Checks[0].Error; got != "timeout" {
+		t.Fatalf("expected timeout, got %q", got)
+	}
+}
+
+func TestReadinessCachesResults(t *testing.T) {
+	var calls atomic.Int32
+	r := NewReadiness(time.Second, Check{
+		Name:     "firestore",
+		Critical: true,
+		CacheTTL: time.Minute,
+		Checker: CheckerFunc(func(context.Context) error {
+			calls.Add(1)
+			return nil
+		}),
+	})
+
+	_, first := serveReadiness(t, r)
+	_, second := serveReadiness(t, r)
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 check call, got %d", got)
+	}
+	if first.Checks[0].Cached || !second.Checks[0].Cached {
+		t.Fatalf("expected only the second result to be cached: %#v, %#v", first.Checks[0], second.Checks[0])
+	}
+	if !first.Checks[0].CheckedAt.Equal(second.Checks[0].CheckedAt) {
+		t.Fatal("expected cached result to keep its check time")
+	}
+}
+
+func TestReadinessFailsOnceShutdownBegins(t *testing.T) {
+	shutdown := &Shutdown{}
+	r := NewReadiness(time.Second, Check{Name: "shutdown", Critical: true, Checker: shutdown})
+
+	if code, _ := serveReadiness(t, r); code != http.StatusOK {
+		t.Fatalf("expected 200 before shutdown, got %d", code)
+	}
+	shutdown.Begin()
+	code, body := serveReadiness(t, r)
+	if code != http.StatusServiceUnavailable || body.Checks[0].Error != "shutting_down" {
+		t.Fatalf("expected shutting down 503, got %d %#v", code, body.Checks)
+	}
+}
@@ -0,0 +1,262 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5"
+	obs "github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/janisto/huma-playground/internal/platform/limits"
+)
+
+const (
+	// maxBodyBytes bounds the batch request, and with it the requests inside it.
+	maxBodyBytes = 256 << 10
+	// maxResponseBytes bounds the response bodies of a batch together.
+	maxResponseBytes = 4 << 20
+	// concurrency is how many requests of a batch run at once.
+	concurrency = 4
+)
+
+// Register wires the batch route into the provided API router. Requests in a batch are served by the
+// same API, so they pass through its authentication, rate limiting, and validation individually.
+func Register(api huma.API, prefix string) {
+	huma.Register(api, huma.Operation{
+		OperationID: "execute-batch",
+		Method:      http.MethodPost,
+		Path:        "/batch",
+		Summary:     "Run several API requests at once",
+		Description: fmt.Sprintf("Runs up to 20 requests concurrently, %d at a time, as the caller, and returns "+
+			"each one's status, headers, and body in request order. The batch succeeds even when requests in "+
+			"it fail; their responses carry Problem Details. Response bodies share a %d MiB budget, and a "+
+			"response that exceeds it is replaced with a 502 problem.", concurrency, maxResponseBytes>>20),
+		Tags:     []string{"Batch"},
+		Metadata: map[string]any{limits.MaxBodyBytesKey: int64(maxBodyBytes)},
+		Errors: []int{
+			http.StatusBadRequest,
+			http.StatusRequestTimeout,
+			http.StatusRequestEntityTooLarge,
+			http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity,
+		},
+	}, func(ctx context.Context, input *BatchInput) (*BatchOutput, error) {
+		b := &batch{api: api, prefix: prefix, input: input}
+		b.budget.Store(maxResponseBytes)
+
+		responses := make([]SubResponse, len(input.Body.Requests))
+		var g errgroup.Group
+		g.SetLimit(concurrency)
+		for n := range input.Body.Requests {
+			g.Go(func() error {
+				responses[n] = b.run(ctx, n)
+				return nil
+			})
+		}
+		_ = g.Wait()
+
+		obs.Logger(ctx).Info("batch executed", zap.Int("requests", len(responses)))
+		return &BatchOutput{Body: Result{Responses: responses}}, nil
+	})
+}
+
+type batch struct {
+	api    huma.API
+	prefix string
+	input  *BatchInput
+	budget atomic.Int64
+}
+
+// run serves request n of the batch and converts its recorded response.
+func (b *batch) run(ctx context.Context, n int) SubResponse {
+	request := b.input.Body.Requests[n]
+	rec := &recorder{header: http.Header{}}
+	req, status, detail := b.newRequest(ctx, n)
+	if req == nil {
+		req, _ = http.NewRequestWithContext(ctx, request.Method, "/", nil)
+		b.writeProblem(rec, req, status, detail)
+	} else {
+		b.serve(rec, req)
+	}
+
+	size := int64(rec.body.Len())
+	if rec.overflow || b.budget.Add(-size) < 0 {
+		b.budget.Add(size)
+		rec.reset()
+		b.writeProblem(rec, req, http.StatusBadGateway, "response exceeds the batch limit; request it directly")
+	}
+	response := rec.response()
+	response.ID = request.ID
+	return response
+}
+
+// newRequest builds request n as the API router sees it, or returns the status and detail of the
+// problem that replaces it.
+func (b *batch) newRequest(ctx context.Context, n int) (*http.Request, int, string) {
+	request := b.input.Body.Requests[n]
+	target, ok := strings.CutPrefix(request.Path, b.prefix)
+	if !ok || !strings.HasPrefix(target, "/") {
+		return nil, http.StatusNotFound, "path must be under " + b.prefix + "/"
+	}
+	route, _, _ := strings.Cut(target, "?")
+	if path.Clean(route) == "/batch" {
+		return nil, http.StatusBadRequest, "batch requests cannot be nested"
+	}
+
+	// The batch's own route context belongs to the outer router; the API router starts a fresh one.
+	req, err := http.NewRequestWithContext(
+		context.WithValue(ctx, chi.RouteCtxKey, nil), request.Method, target, bytes.NewReader(b.input.bodies[n]),
+	)
+	if err != nil {
+		return nil, http.StatusBadRequest, "invalid path"
+	}
+	for name, value := range request.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, values := range b.input.caller {
+		req.Header[name] = values
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if b.input.bodies[n] != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.RemoteAddr = b.input.remoteAddr
+	return req, 0, ""
+}
+
+// serve runs req through the API. A panic fails only its own request, as it would outside a batch.
+func (b *batch) serve(rec *recorder, req *http.Request) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		if err, ok := recovered.(error); !ok || !errors.Is(err, http.ErrAbortHandler) {
+			obs.Logger(req.Context()).Error("batch request panicked",
+				zap.String("method", req.Method), zap.String("path", req.URL.Path),
+				zap.Error(fmt.Errorf("%v", recovered)))
+		}
+		rec.reset()
+		b.writeProblem(rec, req, http.StatusInternalServerError, "request failed")
+	}()
+	b.api.Adapter().ServeHTTP(rec, req)
+}
+
+func (b *batch) writeProblem(rec *recorder, req *http.Request, status int, detail string) {
+	ctx := humachi.NewContext(&huma.Operation{}, req, rec)
+	if err := huma.WriteErr(b.api, ctx, status, detail); err != nil {
+		obs.Logger(req.Context()).Warn("batch problem not written", zap.Error(err))
+	}
+}
+
+// recorder captures one response, keeping no more of its body than a whole batch may return.
+type recorder struct {
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if r.overflow || r.body.Len()+len(p) > maxResponseBytes {
+		r.overflow = true
+		return len(p), nil
+	}
+	return r.body.Write(p)
+}
+
+func (r *recorder) reset() {
+	r.header = http.Header{}
+	r.status = 0
+	r.body.Reset()
+	r.overflow = false
+}
+
+// response converts the recorded response. JSON bodies are embedded as values; other bodies are
+// embedded as text, or base64 when they are not valid UTF-8.
+func (r *recorder) response() SubResponse {
+	response := SubResponse{Status: r.status, Headers: make(map[string]string, len(r.header))}
+	if response.Status == 0 {
+		response.Status = http.StatusOK
+	}
+	for name, values := range r.header {
+		response.Headers[name] = strings.Join(values, ", ")
+	}
+	if r.body.Len() == 0 {
+		return response
+	}
+	if isJSON(r.header.Get("Content-Type")) {
+		dec := json.NewDecoder(bytes.NewReader(r.body.Bytes()))
+		dec.UseNumber()
+		var body any
+		if dec.Decode(&body) == nil && !dec.More() {
+			response.Body = numbers(body)
+			return response
+		}
+	}
+	if utf8.Valid(r.body.Bytes()) {
+		response.Body = r.body.String()
+	} else {
+		response.Body = base64.StdEncoding.EncodeToString(r.body.Bytes())
+		response.BodyEncoding = "base64"
+	}
+	return response
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// numbers replaces the json.Number values of a decoded body with int64 or float64, so they encode
+// as numbers in CBOR as well as JSON.
+func numbers(v any) any {
+	switch value := v.(type) {
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+		return value.String()
+	case map[string]any:
+		for key, item := range value {
+			value[key] = numbers(item)
+		}
+	case []any:
+		for n, item := range value {
+			value[n] = numbers(item)
+		}
+	}
+	return v
+}
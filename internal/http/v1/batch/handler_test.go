@@ -0,0 +1,312 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	_ "github.com/danielgtaylor/huma/v2/formats/cbor"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/janisto/huma-observability/v2"
+
+	"github.com/janisto/huma-playground/internal/platform/auth"
+	"github.com/janisto/huma-playground/internal/platform/respond"
+)
+
+// tokenVerifier accepts the token "good" for test-user and rejects every other token.
+type tokenVerifier struct{}
+
+func (tokenVerifier) Verify(_ context.Context, token string) (*auth.FirebaseUser, error) {
+	if token != "good" {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.FirebaseUser{UID: "test-user"}, nil
+}
+
+type echoInput struct {
+	Greeting string `header:"X-Greeting"`
+	Body     struct {
+		Name  string `json:"name"  maxLength:"20"`
+		Count int    `json:"count"`
+	}
+}
+
+type echoOutput struct {
+	Body struct {
+		Greeting string `json:"greeting"`
+		Name     string `json:"name"`
+		Count    int    `json:"count"`
+	}
+}
+
+type whoamiOutput struct {
+	Body struct {
+		UID string `json:"uid"`
+	}
+}
+
+// newTestRouter mounts an API under /v1 the way the server does, with a few operations to batch.
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	apiRouter := chi.NewRouter()
+	api := humachi.New(apiRouter, huma.DefaultConfig("BatchTest", "test"))
+	api.UseMiddleware(obs.RequestContext(obs.RequestContextConfig{}))
+	api.UseMiddleware(auth.NewAuthMiddleware(api, tokenVerifier{}))
+	huma.Register(api, huma.Operation{
+		OperationID: "echo",
+		Method:      http.MethodPost,
+		Path:        "/echo",
+	}, func(_ context.Context, input *echoInput) (*echoOutput, error) {
+		out := &echoOutput{}
+		out.Body.Greeting, out.Body.Name, out.Body.Count = input.Greeting, input.Body.Name, input.Body.Count
+		return out, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "whoami",
+		Method:      http.MethodGet,
+		Path:        "/whoami",
+		Security:    auth.RequireAuth(),
+	}, func(ctx context.Context, _ *struct{}) (*whoamiOutput, error) {
+		out := &whoamiOutput{}
+		out.Body.UID = auth.UserFromContext(ctx).UID
+		return out, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "boom",
+		Method:      http.MethodGet,
+		Path:        "/boom",
+	}, func(context.Context, *struct{}) (*struct{}, error) {
+		panic("boom")
+	})
+	Register(api, "/v1")
+	apiRouter.NotFound(respond.NotFoundHandler(api))
+	apiRouter.MethodNotAllowed(respond.MethodNotAllowedHandler(api))
+
+	router := chi.NewRouter()
+	router.Use(chimiddleware.ClientIPFromRemoteAddr)
+	router.Mount("/v1", apiRouter)
+	return router
+}
+
+func postBatch(t *testing.T, handler http.Handler, body, authorization string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
+
+func decodeResult(t *testing.T, resp *httptest.ResponseRecorder) Result {
+	t.Helper()
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var result Result
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	return result
+}
+
+func TestBatchReturnsResponsesInRequestOrder(t *testing.T) {
+	body := `{"requests":[
+		{"id":"echo","method":"POST","path":"/v1/echo","headers":{"X-Greeting":"hi"},"body":{"name":"Ada","count":3}},
+		{"id":"invalid","method":"POST","path":"/v1/echo","body":{"name":"a name that is far too long"}},
+		{"id":"missing","method":"GET","path":"/v1/nope"},
+		{"id":"method","method":"DELETE","path":"/v1/echo"},
+		{"id":"outside","method":"GET","path":"/health"},
+		{"id":"panic","method":"GET","path":"/v1/boom"}
+	]}`
+	result := decodeResult(t, postBatch(t, newTestRouter(t), body, ""))
+
+	want := []struct {
+		id     string
+		status int
+	}{
+		{"echo", http.StatusOK},
+		{"invalid", http.StatusUnprocessableEntity},
+		{"missing", http.StatusNotFound},
+		{"method", http.StatusMethodNotAllowed},
+		{"outside", http.StatusNotFound},
+		{"panic", http.StatusInternalServerError},
+	}
+	if len(result.Responses) != len(want) {
+		t.Fatalf("expected %d responses, got %+v", len(want), result.Responses)
+	}
+	for n, w := range want {
+		got := result.Responses[n]
+		if got.ID != w.id || got.Status != w.status {
+			t.Errorf("response %d: expected %s %d, got %s %d: %v", n, w.id, w.status, got.ID, got.Status, got.Body)
+		}
+	}
+
+	echo, ok := result.Responses[0].Body.(map[string]any)
+	if !ok || echo["greeting"] != "hi" || echo["name"] != "Ada" || echo["count"] != float64(3) {
+		t.Fatalf("expected the echoed request, got %v", result.Responses[0].Body)
+	}
+	for _, failed := range result.Responses[1:] {
+		if got := failed.Headers["Content-Type"]; got != "application/problem+json" {
+			t.Errorf("%s: expected a Problem Details body, got Content-Type %q", failed.ID, got)
+		}
+	}
+}
+
+func TestBatchRunsAsCaller(t *testing.T) {
+	handler := newTestRouter(t)
+	body := `{"requests":[{"method":"GET","path":"/v1/whoami"}]}`
+
+	result := decodeResult(t, postBatch(t, handler, body, "Bearer good"))
+	whoami, _ := result.Responses[0].Body.(map[string]any)
+	if result.Responses[0].Status != http.StatusOK || whoami["uid"] != "test-user" {
+		t.Fatalf("expected the caller's identity, got %d %v", result.Responses[0].Status, result.Responses[0].Body)
+	}
+
+	for _, authorization := range []string{"", "Bearer bad"} {
+		result = decodeResult(t, postBatch(t, handler, body, authorization))
+		if result.Responses[0].Status != http.StatusUnauthorized {
+			t.Fatalf("Authorization %q: expected 401, got %d", authorization, result.Responses[0].Status)
+		}
+	}
+}
+
+func TestBatchRejectsNestedBatches(t *testing.T) {
+	body := `{"requests":[{"method":"POST","path":"/v1/batch","body":{"requests":[]}},
+		{"method":"POST","path":"/v1/./batch/"}]}`
+	result := decodeResult(t, postBatch(t, newTestRouter(t), body, ""))
+
+	for _, response := range result.Responses {
+		if response.Status != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %v", response.Status, response.Body)
+		}
+	}
+}
+
+func TestBatchValidation(t *testing.T) {
+	requests := make([]string, 21)
+	for n := range requests {
+		requests[n] = `{"method":"GET","path":"/v1/whoami"}`
+	}
+	tests := []struct {
+		name     string
+		body     string
+		location string
+	}{
+		{"empty", `{"requests":[]}`, "body.requests"},
+		{"too many", `{"requests":[` + strings.Join(requests, ",") + `]}`, "body.requests"},
+		{
+			"caller header",
+			`{"requests":[{"method":"GET","path":"/v1/whoami","headers":{"authorization":"Bearer good"}}]}`,
+			"body.requests[0].headers.authorization",
+		},
+		{
+			"framing header",
+			`{"requests":[{"method":"GET","path":"/v1/whoami","headers":{"Content-Length":"1"}}]}`,
+			"body.requests[0].headers.Content-Length",
+		},
+		{
+			"invalid header",
+			`{"requests":[{"method":"GET","path":"/v1/whoami","headers":{"Bad Name":"x"}}]}`,
+			"body.requests[0].headers.Bad Name",
+		},
+		{"method", `{"requests":[{"method":"TRACE","path":"/v1/whoami"}]}`, "body.requests[0].method"},
+		{"relative path", `{"requests":[{"method":"GET","path":"v1/whoami"}]}`, "body.requests[0].path"},
+	}
+	handler := newTestRouter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postBatch(t, handler, tt.body, "")
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+			var problem huma.ErrorModel
+			if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("json unmarshal: %v", err)
+			}
+			if len(problem.Errors) != 1 || problem.Errors[0].Location != tt.location {
+				t.Fatalf("expected one error at %s, got %+v", tt.location, problem.Errors)
+			}
+		})
+	}
+}
+
+func TestBatchCBOR(t *testing.T) {
+	body, err := cbor.Marshal(map[string]any{"requests": []any{
+		map[string]any{"method": "POST", "path": "/v1/echo", "body": map[string]any{"name": "Ada", "count": 2}},
+		map[string]any{
+			"method": "POST", "path": "/v1/echo", "headers": map[string]any{"Accept": "application/cbor"},
+			"body": map[string]any{"name": "Bob", "count": 1},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("cbor marshal: %v", err)
+	}
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/cbor")
+	req.Header.Set("Accept", "application/cbor")
+	resp := httptest.NewRecorder()
+	newTestRouter(t).ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var result struct {
+		Responses []struct {
+			Status       int            `cbor:"status"`
+			Body         any            `cbor:"body"`
+			BodyEncoding string         `cbor:"bodyEncoding"`
+			Headers      map[string]any `cbor:"headers"`
+		} `cbor:"responses"`
+	}
+	if err := cbor.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("cbor unmarshal: %v", err)
+	}
+	echo, _ := result.Responses[0].Body.(map[any]any)
+	if fmt.Sprintf("%v %v", echo["name"], echo["count"]) != "Ada 2" {
+		t.Fatalf("expected the echoed CBOR request as a map, got %#v", result.Responses[0].Body)
+	}
+
+	second := result.Responses[1]
+	if second.BodyEncoding != "base64" {
+		t.Fatalf("expected a base64 CBOR body, got %q: %v", second.BodyEncoding, second.Body)
+	}
+	raw, err := base64.StdEncoding.DecodeString(second.Body.(string))
+	if err != nil {
+		t.Fatalf("base64 decode: %v", err)
+	}
+	var decoded map[string]any
+	if err := cbor.Unmarshal(raw, &decoded); err != nil || decoded["name"] != "Bob" {
+		t.Fatalf("expected a CBOR body naming Bob, got %v (%v)", decoded, err)
+	}
+}
+
+func TestRecorderLimitsResponses(t *testing.T) {
+	rec := &recorder{header: http.Header{}}
+	if _, err := rec.Write(make([]byte, maxResponseBytes+1)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !rec.overflow || rec.body.Len() != 0 {
+		t.Fatalf("expected an overflowing recorder to keep nothing, got %d bytes", rec.body.Len())
+	}
+
+	rec.reset()
+	rec.header.Set("Content-Type", "application/json")
+	_, _ = rec.Write([]byte(`{"n":12345678901234,"f":1.5}`))
+	body, _ := rec.response().Body.(map[string]any)
+	if body["n"] != int64(12345678901234) || body["f"] != 1.5 {
+		t.Fatalf("expected int64 and float64 numbers, got %#v", body)
+	}
+}
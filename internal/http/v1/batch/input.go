@@ -0,0 +1,131 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// callerHeaders are taken from the batch request for every sub-request, so each one runs as the caller.
+var callerHeaders = []string{"Authorization", "Cookie"}
+
+// reservedHeaders describe the message framing, which the batch handler sets itself.
+var reservedHeaders = []string{
+	"Connection", "Content-Encoding", "Content-Length", "Host", "Te", "Trailer",
+	"Transfer-Encoding", "Upgrade",
+}
+
+// BatchInput is the request body for POST /batch.
+type BatchInput struct {
+	Body struct {
+		Requests []SubRequest `json:"requests" doc:"Requests to run; they run concurrently, so their order is not their execution order" minItems:"1" maxItems:"20"`
+	}
+
+	caller     http.Header
+	remoteAddr string
+	bodies     [][]byte
+}
+
+// Resolve captures the caller's credentials, rejects headers a sub-request may not set, and encodes
+// each sub-request body as JSON.
+func (i *BatchInput) Resolve(ctx huma.Context) []error {
+	i.caller = http.Header{}
+	for _, name := range callerHeaders {
+		if value := ctx.Header(name); value != "" {
+			i.caller.Set(name, value)
+		}
+	}
+	i.remoteAddr = ctx.RemoteAddr()
+
+	var errs []error
+	i.bodies = make([][]byte, len(i.Body.Requests))
+	for n, request := range i.Body.Requests {
+		for name := range request.Headers {
+			canonical := http.CanonicalHeaderKey(name)
+			var message string
+			switch {
+			case !validHeaderName(name):
+				message = "invalid header name"
+			case containsFold(callerHeaders, canonical):
+				message = canonical + " is taken from the batch request"
+			case containsFold(reservedHeaders, canonical):
+				message = canonical + " is set by the server"
+			default:
+				continue
+			}
+			errs = append(errs, &huma.ErrorDetail{
+				Location: fmt.Sprintf("body.requests[%d].headers.%s", n, name),
+				Message:  message,
+				Value:    name,
+			})
+		}
+		if request.Body == nil {
+			continue
+		}
+		body, err := json.Marshal(jsonValue(request.Body))
+		if err != nil {
+			errs = append(errs, &huma.ErrorDetail{
+				Location: fmt.Sprintf("body.requests[%d].body", n),
+				Message:  "body cannot be sent as JSON",
+			})
+			continue
+		}
+		i.bodies[n] = body
+	}
+	return errs
+}
+
+// jsonValue converts CBOR maps, which decode with arbitrary keys, into JSON objects. Maps with keys
+// that are not strings are left as they are and fail to encode.
+func jsonValue(v any) any {
+	switch value := v.(type) {
+	case map[any]any:
+		object := make(map[string]any, len(value))
+		for key, item := range value {
+			name, ok := key.(string)
+			if !ok {
+				return value
+			}
+			object[name] = jsonValue(item)
+		}
+		return object
+	case map[string]any:
+		for key, item := range value {
+			value[key] = jsonValue(item)
+		}
+		return value
+	case []any:
+		for n, item := range value {
+			value[n] = jsonValue(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// validHeaderName reports whether name is an RFC 9110 token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 0x7f || !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' ||
+			strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
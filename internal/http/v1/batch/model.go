@@ -0,0 +1,24 @@
+package batch
+
+// SubRequest is one API request inside a batch.
+type SubRequest struct {
+	ID      string            `json:"id,omitempty"      doc:"Client-chosen identifier echoed in the matching response"                   example:"catalog"           maxLength:"64"`
+	Method  string            `json:"method"            doc:"HTTP method"                                                                example:"GET"                                enum:"GET,POST,PUT,PATCH,DELETE"`
+	Path    string            `json:"path"              doc:"Path and query under the API, as in Link headers"                           example:"/v1/items?limit=5" maxLength:"2048"                                  pattern:"^/"`
+	Headers map[string]string `json:"headers,omitempty" doc:"Request headers; Authorization and Cookie are taken from the batch request"                                                                                            maxProperties:"20"`
+	Body    any               `json:"body,omitempty"    doc:"Request body, sent as JSON"`
+}
+
+// SubResponse is the outcome of one request in a batch. Failures carry Problem Details bodies.
+type SubResponse struct {
+	ID           string            `json:"id,omitempty"           doc:"Identifier of the matching request"                         example:"catalog"`
+	Status       int               `json:"status"                 doc:"HTTP status code"                                           example:"200"`
+	Headers      map[string]string `json:"headers"                doc:"Response headers, with repeated values joined by commas"`
+	Body         any               `json:"body,omitempty"         doc:"Decoded JSON body, or the body as text when it is not JSON"`
+	BodyEncoding string            `json:"bodyEncoding,omitempty" doc:"base64 when a non-JSON body is not valid UTF-8"                               enum:"base64"`
+}
+
+// Result is the response body of a batch.
+type Result struct {
+	Responses []SubResponse `json:"responses" doc:"One response per request, in request order"`
+}
@@ -0,0 +1,6 @@
+package batch
+
+// BatchOutput is the response wrapper for POST /batch.
+type BatchOutput struct {
+	Body Result
+}
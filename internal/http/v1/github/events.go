@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	obs "github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
+
+	"github.com/janisto/huma-playground/internal/platform/limits"
+	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+)
+
+const (
+	// activityStreamTimeout bounds one activity stream; clients reconnect and resume after it.
+	activityStreamTimeout = 30 * time.Minute
+	// activityHeartbeat is how often an idle stream sends a comment, so proxies keep it open.
+	activityHeartbeat = 15 * time.Second
+	// activityRetry is the reconnection delay sent to clients.
+	activityRetry = 5 * time.Second
+)
+
+// RegisterActivityEvents wires the repository activity stream, served from feed, into the provided
+// API router.
+func RegisterActivityEvents(api huma.API, feed *githubsvc.ActivityFeed) {
+	huma.Register(api, huma.Operation{
+		OperationID: "stream-github-repo-activity",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/activity/events",
+		Summary:     "Stream repository activity",
+		Description: "Sends new activity events for the specified GitHub repository as server-sent events. " +
+			"The server polls GitHub once per repository for all subscribers, so a stream only sees " +
+			"activities that appear after it starts, unless Last-Event-ID resumes it. Idle streams " +
+			fmt.Sprintf(
+				"send a comment every %s, and each stream ends after %s.",
+				activityHeartbeat,
+				activityStreamTimeout,
+			),
+		Tags:     []string{"GitHub"},
+		Metadata: map[string]any{limits.TimeoutKey: activityStreamTimeout},
+		Errors:   githubErrors,
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Stream of activity events, each with the activity ID as its id and the activity " +
+					"as JSON data",
+				Content: map[string]*huma.MediaType{
+					"text/event-stream": {Schema: &huma.Schema{Type: huma.TypeString}},
+				},
+			},
+		},
+	}, func(ctx context.Context, input *RepoActivityEventsInput) (*huma.StreamResponse, error) {
+		sub, err := feed.Subscribe(ctx, input.Owner, input.Repo, input.LastEventID)
+		if errors.Is(err, githubsvc.ErrFeedClosed) {
+			return nil, huma.Error503ServiceUnavailable("server is shutting down")
+		}
+		if err != nil {
+			return nil, mapServiceError(ctx, "poll_repository_activity", err)
+		}
+		return &huma.StreamResponse{Body: func(ctx huma.Context) {
+			defer sub.Close()
+			writeActivityEvents(ctx, sub)
+		}}, nil
+	})
+}
+
+// writeActivityEvents sends the subscription's activities until it ends, the client leaves, or the
+// operation times out.
+func writeActivityEvents(ctx huma.Context, sub *githubsvc.Subscription) {
+	ctx.SetHeader("Content-Type", "text/event-stream")
+	ctx.SetHeader("Cache-Control", "no-cache")
+	ctx.SetStatus(http.StatusOK)
+	w := ctx.BodyWriter()
+	send := func(event string) bool {
+		if _, err := io.WriteString(w, event); err != nil {
+			return false
+		}
+		if rw, ok := w.(http.ResponseWriter); ok {
+			_ = http.NewResponseController(rw).Flush()
+		}
+		return true
+	}
+
+	heartbeat := time.NewTicker(activityHeartbeat)
+	defer heartbeat.Stop()
+	event := fmt.Sprintf("retry: %d\n\n", activityRetry.Milliseconds())
+	for send(event) {
+		select {
+		case <-ctx.Context().Done():
+			return
+		case <-heartbeat.C:
+			event = ": heartbeat\n\n"
+		case activity, ok := <-sub.Events():
+			if !ok {
+				if errors.Is(sub.Err(), githubsvc.ErrSlowSubscriber) {
+					obs.Logger(ctx.Context()).Warn("activity stream fell behind; client must resume")
+				}
+				return
+			}
+			data, err := json.Marshal(toHTTPActivity(&activity))
+			if err != nil {
+				obs.Logger(ctx.Context()).Error("encode activity event", zap.Error(err))
+				return
+			}
+			event = fmt.Sprintf("id: %d\nevent: activity\ndata: %s\n\n", activity.ID, data)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	humachi "github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5"
+	obs "github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
+
+	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+)
+
+// queuedActivityService answers activity polls with the pages queued by the test, in order.
+type queuedActivityService struct {
+	mockGitHubService
+	pages chan []githubsvc.Activity
+}
+
+func (s *queuedActivityService) PollActivity(ctx context.Context, _, _, _ string) (*githubsvc.ActivityPoll, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	select {
+	case activities := <-s.pages:
+		return &githubsvc.ActivityPoll{Activities: activities}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newActivityEventsServer(t *testing.T, svc githubsvc.Service) (*httptest.Server, *githubsvc.ActivityFeed) {
+	t.Helper()
+	feed := githubsvc.NewActivityFeed(svc, time.Millisecond, zap.NewNop())
+	t.Cleanup(feed.Close)
+
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("GitHubTest", "test"))
+	api.UseMiddleware(obs.RequestContext(obs.RequestContextConfig{Logger: zap.NewNop()}))
+	RegisterActivityEvents(api, feed)
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv, feed
+}
+
+func activities(ids ...int64) []githubsvc.Activity {
+	result := make([]githubsvc.Activity, len(ids))
+	for i, id := range ids {
+		result[i] = activityWithID(id)
+	}
+	return result
+}
+
+func openActivityEvents(t *testing.T, srv *httptest.Server, lastEventID string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		srv.URL+"/github/repos/octocat/hello-world/activity/events",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+// readEvent returns the fields of the next event in the stream, or nil when it ends.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+	event := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF && line == "" && len(event) == 0 {
+			return nil
+		}
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return event
+		}
+		name, value, _ := strings.Cut(line, ":")
+		event[name] = strings.TrimPrefix(value, " ")
+	}
+}
+
+func TestActivityEventsStreamsNewActivities(t *testing.T) {
+	svc := &queuedActivityService{pages: make(chan []githubsvc.Activity, 4)}
+	srv, _ := newActivityEventsServer(t, svc)
+	svc.pages <- activities(1)
+
+	resp := openActivityEvents(t, srv, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	if event := readEvent(t, r); event["retry"] != "5000" {
+		t.Fatalf("expected a retry interval first, got %v", event)
+	}
+
+	svc.pages <- activities(2, 1)
+	event := readEvent(t, r)
+	if event["id"] != "2" || event["event"] != "activity" {
+		t.Fatalf("expected activity 2, got %v", event)
+	}
+	var activity Activity
+	if err := json.Unmarshal([]byte(event["data"]), &activity); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	if activity.ID != 2 || activity.ActivityType != "push" {
+		t.Fatalf("unexpected activity: %+v", activity)
+	}
+}
+
+func TestActivityEventsResumesAfterLastEventID(t *testing.T) {
+	svc := &queuedActivityService{pages: make(chan []githubsvc.Activity, 4)}
+	srv, _ := newActivityEventsServer(t, svc)
+	svc.pages <- activities(3, 2, 1)
+
+	r := bufio.NewReader(openActivityEvents(t, srv, "2").Body)
+	readEvent(t, r)
+	if event := readEvent(t, r); event["id"] != "3" {
+		t.Fatalf("expected the stream to resume with activity 3, got %v", event)
+	}
+}
+
+func TestActivityEventsRejectsInvalidLastEventID(t *testing.T) {
+	svc := &queuedActivityService{pages: make(chan []githubsvc.Activity, 4)}
+	srv, _ := newActivityEventsServer(t, svc)
+
+	resp := openActivityEvents(t, srv, "abc")
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+}
+
+func TestActivityEventsNotFound(t *testing.T) {
+	svc := &queuedActivityService{mockGitHubService: mockGitHubService{err: githubsvc.ErrNotFound}}
+	srv, _ := newActivityEventsServer(t, svc)
+
+	resp := openActivityEvents(t, srv, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("expected problem details, got %q", ct)
+	}
+}
+
+func TestActivityEventsEndWhenFeedCloses(t *testing.T) {
+	svc := &queuedActivityService{pages: make(chan []githubsvc.Activity, 4)}
+	srv, feed := newActivityEventsServer(t, svc)
+	svc.pages <- activities(1)
+
+	r := bufio.NewReader(openActivityEvents(t, srv, "").Body)
+	readEvent(t, r)
+	feed.Close()
+	if event := readEvent(t, r); event != nil {
+		t.Fatalf("expected the stream to end, got %v", event)
+	}
+
+	resp := openActivityEvents(t, srv, "")
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 once the feed is closed, got %d", resp.StatusCode)
+	}
+}
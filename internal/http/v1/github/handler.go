@@ -4,21 +4,40 @@ import (
 	"cmp"
 	"context"
 	"errors"
+	"maps"
+	"mime"
 	"net/http"
 	"net/url"
+	"path"
+	"reflect"
 	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	obs "github.com/janisto/huma-observability/v2"
 	"go.uber.org/zap"
 
+	"github.com/janisto/huma-playground/internal/platform/fields"
 	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/stream"
 	"github.com/janisto/huma-playground/internal/platform/timeutil"
 	githubsvc "github.com/janisto/huma-playground/internal/service/github"
 )
 
-const activityCursorType = "gh-activity"
+const (
+	activityCursorType = "gh-activity"
+	releaseCursorType  = "gh-release"
+	pullCursorType     = "gh-pull"
+	issueCursorType    = "gh-issue"
+	commitCursorType   = "gh-commit"
+
+	contentFormatHTML = "html"
+
+	// streamPageSize is the upstream page size a streamed list requests, GitHub's maximum.
+	streamPageSize = 100
+)
 
 var githubErrors = []int{
 	http.StatusForbidden,
@@ -29,10 +48,10 @@ var githubErrors = []int{
 	http.StatusServiceUnavailable,
 }
 
-var githubActivityErrors = append([]int{http.StatusBadRequest}, githubErrors...)
+var githubPaginatedErrors = append([]int{http.StatusBadRequest}, githubErrors...)
 
 // Register wires GitHub routes into the provided API router.
-func Register(api huma.API, svc githubsvc.Service, prefix string) {
+func Register(api huma.API, svc githubsvc.Service, prefix string, cursors *pagination.Codec) {
 	huma.Register(api, huma.Operation{
 		OperationID: "get-github-owner",
 		Method:      http.MethodGet,
@@ -40,6 +59,7 @@ func Register(api huma.API, svc githubsvc.Service, prefix string) {
 		Summary:     "Get a GitHub user or organization",
 		Description: "Returns public profile information for the specified GitHub user or organization.",
 		Tags:        []string{"GitHub"},
+		Metadata:    map[string]any{fields.PathKey: ""},
 		Errors:      githubErrors,
 	}, func(ctx context.Context, input *OwnerGetInput) (*OwnerGetOutput, error) {
 		owner, err := svc.GetOwner(ctx, input.Owner)
@@ -57,6 +77,7 @@ func Register(api huma.API, svc githubsvc.Service, prefix string) {
 		Summary:     "List repositories for a GitHub user",
 		Description: "Returns up to 30 repositories for the specified GitHub user or organization.",
 		Tags:        []string{"GitHub"},
+		Metadata:    map[string]any{fields.PathKey: "repos"},
 		Errors:      githubErrors,
 	}, func(ctx context.Context, input *OwnerGetInput) (*OwnerReposListOutput, error) {
 		repos, err := svc.ListRepos(ctx, input.Owner)
@@ -94,15 +115,28 @@ func Register(api huma.API, svc githubsvc.Service, prefix string) {
 		Summary:     "List repository activity",
 		Description: "Returns paginated activity events for the specified GitHub repository.",
 		Tags:        []string{"GitHub"},
-		Errors:      githubActivityErrors,
+		Metadata:    map[string]any{stream.RecordKey: reflect.TypeFor[Activity]()},
+		Errors:      githubPaginatedErrors,
 	}, func(ctx context.Context, input *RepoActivityListInput) (*RepoActivityListOutput, error) {
-		cursor, err := pagination.DecodeCursor(input.Cursor)
+		cursor, err := decodeCursor(
+			cursors,
+			input.Cursor,
+			activityCursorType,
+			cursorFilter(input.Owner, input.Repo, nil),
+		)
 		if err != nil {
-			return nil, huma.Error400BadRequest("invalid cursor format")
+			return nil, err
 		}
 
-		if input.Cursor != "" && cursor.Type != activityCursorType {
-			return nil, huma.Error400BadRequest("cursor type mismatch")
+		if w, ok := stream.FromContext(ctx); ok {
+			return nil, stream.Pages(ctx, w, cursor.Value,
+				func(ctx context.Context, cursor string) ([]Activity, string, error) {
+					page, err := svc.ListActivity(ctx, input.Owner, input.Repo, streamPageSize, cursor)
+					if err != nil {
+						return nil, "", mapServiceError(ctx, "list_repository_activity", err)
+					}
+					return toHTTPActivities(page.Activities), page.NextCursor, nil
+				})
 		}
 
 		page, err := svc.ListActivity(ctx, input.Owner, input.Repo, input.DefaultLimit(), cursor.Value)
@@ -110,23 +144,10 @@ func Register(api huma.API, svc githubsvc.Service, prefix string) {
 			return nil, mapServiceError(ctx, "list_repository_activity", err)
 		}
 
-		var linkHeader string
-		if page.NextCursor != "" {
-			nextEncoded := pagination.Cursor{
-				Type:  activityCursorType,
-				Value: page.NextCursor,
-			}.Encode()
-			linkHeader = pagination.BuildLinkHeader(
-				prefix+"/github/repos/"+url.PathEscape(input.Owner)+"/"+url.PathEscape(input.Repo)+"/activity",
-				url.Values{"limit": {strconv.Itoa(input.DefaultLimit())}},
-				nextEncoded,
-				"",
-			)
-		}
-
 		httpActivities := toHTTPActivities(page.Activities)
 		return &RepoActivityListOutput{
-			Link: linkHeader,
+			Link: repoLinkHeader(cursors, prefix, input.Owner, input.Repo, "activity", activityCursorType,
+				repoLinks{current: cursor.Value, next: page.NextCursor}, input.DefaultLimit(), nil),
 			Body: RepoActivityListData{
 				Activities: httpActivities,
 				Count:      len(httpActivities),
@@ -171,6 +192,587 @@ func Register(api huma.API, svc githubsvc.Service, prefix string) {
 			Count: len(httpTags),
 		}}, nil
 	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-github-repo-insights",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/insights",
+		Summary:     "Get repository insights",
+		Description: "Returns repository details, language percentages, the latest tag, a recent activity summary, " +
+			"and health indicators in one response. Sections are loaded concurrently; a section that fails is " +
+			"omitted and described in errors, and the request fails only when every section fails.",
+		Tags:   []string{"GitHub"},
+		Errors: githubErrors,
+	}, func(ctx context.Context, input *RepoGetInput) (*RepoInsightsGetOutput, error) {
+		insights, err := collectInsights(ctx, svc, input.Owner, input.Repo)
+		if err != nil {
+			return nil, err
+		}
+		return &RepoInsightsGetOutput{Body: *insights}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-github-repo-releases",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/releases",
+		Summary:     "List repository releases",
+		Description: "Returns paginated releases, including their assets, for the specified GitHub repository.",
+		Tags:        []string{"GitHub"},
+		Metadata:    map[string]any{stream.RecordKey: reflect.TypeFor[Release]()},
+		Errors:      githubPaginatedErrors,
+	}, func(ctx context.Context, input *RepoReleaseListInput) (*RepoReleaseListOutput, error) {
+		pageCursor, err := decodePageCursor(cursors, input.Cursor, releaseCursorType,
+			cursorFilter(input.Owner, input.Repo, nil), input.DefaultLimit())
+		if err != nil {
+			return nil, err
+		}
+
+		if w, ok := stream.FromContext(ctx); ok {
+			limit := streamPageLimit(pageCursor, input.DefaultLimit())
+			return nil, stream.Pages(ctx, w, pageCursor,
+				func(ctx context.Context, cursor string) ([]Release, string, error) {
+					page, err := svc.ListReleases(ctx, input.Owner, input.Repo, limit, cursor)
+					if err != nil {
+						return nil, "", mapServiceError(ctx, "list_repository_releases", err)
+					}
+					return toHTTPReleases(page.Releases), page.NextCursor, nil
+				})
+		}
+
+		page, err := svc.ListReleases(ctx, input.Owner, input.Repo, input.DefaultLimit(), pageCursor)
+		if err != nil {
+			return nil, mapServiceError(ctx, "list_repository_releases", err)
+		}
+
+		httpReleases := toHTTPReleases(page.Releases)
+		return &RepoReleaseListOutput{
+			Link: repoLinkHeader(
+				cursors,
+				prefix,
+				input.Owner,
+				input.Repo,
+				"releases",
+				releaseCursorType,
+				pageNumberLinks(
+					pageCursor,
+					page.NextCursor,
+					page.LastCursor,
+					input.DefaultLimit(),
+				),
+				input.DefaultLimit(),
+				nil,
+			),
+			Body: RepoReleaseListData{
+				Releases: httpReleases,
+				Count:    len(httpReleases),
+			},
+		}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-github-repo-latest-release",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/releases/latest",
+		Summary:     "Get the latest repository release",
+		Description: "Returns the most recent published, non-prerelease release for the specified GitHub repository.",
+		Tags:        []string{"GitHub"},
+		Errors:      githubErrors,
+	}, func(ctx context.Context, input *RepoGetInput) (*RepoReleaseGetOutput, error) {
+		release, err := svc.GetLatestRelease(ctx, input.Owner, input.Repo)
+		if err != nil {
+			return nil, mapServiceError(ctx, "get_repository_latest_release", err)
+		}
+		return &RepoReleaseGetOutput{Body: toHTTPRelease(release)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-github-repo-release-by-tag",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/releases/tags/{tag}",
+		Summary:     "Get a repository release by tag",
+		Description: "Returns the release associated with the specified tag in the GitHub repository.",
+		Tags:        []string{"GitHub"},
+		Errors:      githubErrors,
+	}, func(ctx context.Context, input *RepoReleaseGetInput) (*RepoReleaseGetOutput, error) {
+		release, err := svc.GetReleaseByTag(ctx, input.Owner, input.Repo, input.Tag)
+		if err != nil {
+			return nil, mapServiceError(ctx, "get_repository_release_by_tag", err)
+		}
+		return &RepoReleaseGetOutput{Body: toHTTPRelease(release)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-github-repo-pulls",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/pulls",
+		Summary:     "List repository pull requests",
+		Description: "Returns paginated pull requests for the specified GitHub repository, " +
+			"optionally filtered by state and base branch. GitHub's pull request API cannot filter by label " +
+			"or assignee, so unlike the issue listing this operation has no labels or assignee parameters.",
+		Tags:     []string{"GitHub"},
+		Metadata: map[string]any{stream.RecordKey: reflect.TypeFor[PullRequest]()},
+		Errors:   githubPaginatedErrors,
+	}, func(ctx context.Context, input *RepoPullRequestListInput) (*RepoPullRequestListOutput, error) {
+		query := url.Values{}
+		setIfNotEmpty(query, "state", input.State)
+		setIfNotEmpty(query, "base", input.Base)
+		setIfNotEmpty(query, "sort", input.Sort)
+		setIfNotEmpty(query, "direction", input.Direction)
+
+		pageCursor, err := decodePageCursor(cursors, input.Cursor, pullCursorType,
+			cursorFilter(input.Owner, input.Repo, query), input.DefaultLimit())
+		if err != nil {
+			return nil, err
+		}
+
+		params := githubsvc.PullRequestListParams{
+			State:      input.State,
+			Base:       input.Base,
+			Sort:       input.Sort,
+			Direction:  input.Direction,
+			Limit:      input.DefaultLimit(),
+			PageCursor: pageCursor,
+		}
+		if w, ok := stream.FromContext(ctx); ok {
+			params.Limit = streamPageLimit(pageCursor, input.DefaultLimit())
+			return nil, stream.Pages(ctx, w, pageCursor,
+				func(ctx context.Context, cursor string) ([]PullRequest, string, error) {
+					params.PageCursor = cursor
+					page, err := svc.ListPullRequests(ctx, input.Owner, input.Repo, params)
+					if err != nil {
+						return nil, "", mapServiceError(ctx, "list_repository_pull_requests", err)
+					}
+					return toHTTPPullRequests(page.PullRequests), page.NextCursor, nil
+				})
+		}
+
+		page, err := svc.ListPullRequests(ctx, input.Owner, input.Repo, params)
+		if err != nil {
+			return nil, mapServiceError(ctx, "list_repository_pull_requests", err)
+		}
+
+		httpPulls := toHTTPPullRequests(page.PullRequests)
+		return &RepoPullRequestListOutput{
+			Link: repoLinkHeader(
+				cursors,
+				prefix,
+				input.Owner,
+				input.Repo,
+				"pulls",
+				pullCursorType,
+				pageNumberLinks(
+					pageCursor,
+					page.NextCursor,
+					page.LastCursor,
+					input.DefaultLimit(),
+				),
+				input.DefaultLimit(),
+				query,
+			),
+			Body: RepoPullRequestListData{
+				PullRequests: httpPulls,
+				Count:        len(httpPulls),
+			},
+		}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-github-repo-issues",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/issues",
+		Summary:     "List repository issues",
+		Description: "Returns paginated issues for the specified GitHub repository, optionally filtered by state, " +
+			"labels, and assignee. Pull requests are excluded, so a page may contain fewer items than the limit.",
+		Tags:     []string{"GitHub"},
+		Metadata: map[string]any{stream.RecordKey: reflect.TypeFor[Issue]()},
+		Errors:   githubPaginatedErrors,
+	}, func(ctx context.Context, input *RepoIssueListInput) (*RepoIssueListOutput, error) {
+		query := url.Values{}
+		setIfNotEmpty(query, "state", input.State)
+		setIfNotEmpty(query, "labels", strings.Join(input.Labels, ","))
+		setIfNotEmpty(query, "assignee", input.Assignee)
+		setIfNotEmpty(query, "sort", input.Sort)
+		setIfNotEmpty(query, "direction", input.Direction)
+
+		pageCursor, err := decodePageCursor(cursors, input.Cursor, issueCursorType,
+			cursorFilter(input.Owner, input.Repo, query), input.DefaultLimit())
+		if err != nil {
+			return nil, err
+		}
+
+		params := githubsvc.IssueListParams{
+			State:      input.State,
+			Labels:     input.Labels,
+			Assignee:   input.Assignee,
+			Sort:       input.Sort,
+			Direction:  input.Direction,
+			Limit:      input.DefaultLimit(),
+			PageCursor: pageCursor,
+		}
+		if w, ok := stream.FromContext(ctx); ok {
+			params.Limit = streamPageLimit(pageCursor, input.DefaultLimit())
+			return nil, stream.Pages(ctx, w, pageCursor,
+				func(ctx context.Context, cursor string) ([]Issue, string, error) {
+					params.PageCursor = cursor
+					page, err := svc.ListIssues(ctx, input.Owner, input.Repo, params)
+					if err != nil {
+						return nil, "", mapServiceError(ctx, "list_repository_issues", err)
+					}
+					return toHTTPIssues(page.Issues), page.NextCursor, nil
+				})
+		}
+
+		page, err := svc.ListIssues(ctx, input.Owner, input.Repo, params)
+		if err != nil {
+			return nil, mapServiceError(ctx, "list_repository_issues", err)
+		}
+
+		httpIssues := toHTTPIssues(page.Issues)
+		return &RepoIssueListOutput{
+			Link: repoLinkHeader(
+				cursors,
+				prefix,
+				input.Owner,
+				input.Repo,
+				"issues",
+				issueCursorType,
+				pageNumberLinks(
+					pageCursor,
+					page.NextCursor,
+					page.LastCursor,
+					input.DefaultLimit(),
+				),
+				input.DefaultLimit(),
+				query,
+			),
+			Body: RepoIssueListData{
+				Issues: httpIssues,
+				Count:  len(httpIssues),
+			},
+		}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-github-repo-commits",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/commits",
+		Summary:     "List repository commits",
+		Description: "Returns paginated commit history for the specified GitHub repository, " +
+			"optionally filtered by branch, file path, author, and time window.",
+		Tags:     []string{"GitHub"},
+		Metadata: map[string]any{stream.RecordKey: reflect.TypeFor[Commit]()},
+		Errors:   githubPaginatedErrors,
+	}, func(ctx context.Context, input *RepoCommitListInput) (*RepoCommitListOutput, error) {
+		query := url.Values{}
+		setIfNotEmpty(query, "branch", input.Branch)
+		setIfNotEmpty(query, "path", input.Path)
+		setIfNotEmpty(query, "author", input.Author)
+		if !input.Since.IsZero() {
+			query.Set("since", input.Since.UTC().Format(time.RFC3339Nano))
+		}
+		if !input.Until.IsZero() {
+			query.Set("until", input.Until.UTC().Format(time.RFC3339Nano))
+		}
+
+		pageCursor, err := decodePageCursor(cursors, input.Cursor, commitCursorType,
+			cursorFilter(input.Owner, input.Repo, query), input.DefaultLimit())
+		if err != nil {
+			return nil, err
+		}
+
+		params := githubsvc.CommitListParams{
+			Branch:     input.Branch,
+			Path:       input.Path,
+			Author:     input.Author,
+			Since:      input.Since,
+			Until:      input.Until,
+			Limit:      input.DefaultLimit(),
+			PageCursor: pageCursor,
+		}
+		if w, ok := stream.FromContext(ctx); ok {
+			params.Limit = streamPageLimit(pageCursor, input.DefaultLimit())
+			return nil, stream.Pages(ctx, w, pageCursor,
+				func(ctx context.Context, cursor string) ([]Commit, string, error) {
+					params.PageCursor = cursor
+					page, err := svc.ListCommits(ctx, input.Owner, input.Repo, params)
+					if err != nil {
+						return nil, "", mapServiceError(ctx, "list_repository_commits", err)
+					}
+					return toHTTPCommits(page.Commits), page.NextCursor, nil
+				})
+		}
+
+		page, err := svc.ListCommits(ctx, input.Owner, input.Repo, params)
+		if err != nil {
+			return nil, mapServiceError(ctx, "list_repository_commits", err)
+		}
+
+		httpCommits := toHTTPCommits(page.Commits)
+		return &RepoCommitListOutput{
+			Link: repoLinkHeader(
+				cursors,
+				prefix,
+				input.Owner,
+				input.Repo,
+				"commits",
+				commitCursorType,
+				pageNumberLinks(
+					pageCursor,
+					page.NextCursor,
+					page.LastCursor,
+					input.DefaultLimit(),
+				),
+				input.DefaultLimit(),
+				query,
+			),
+			Body: RepoCommitListData{
+				Commits: httpCommits,
+				Count:   len(httpCommits),
+			},
+		}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "compare-github-repo-refs",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/compare/{basehead}",
+		Summary:     "Compare two repository refs",
+		Description: "Returns ahead/behind counts, commits, and changed file statistics between two refs " +
+			"given as base...head.",
+		Tags:   []string{"GitHub"},
+		Errors: githubErrors,
+	}, func(ctx context.Context, input *RepoCompareInput) (*RepoCompareGetOutput, error) {
+		// The path pattern forbids ".." inside refs, so the first "..." is the separator.
+		base, head, _ := strings.Cut(input.BaseHead, "...")
+		comparison, err := svc.CompareCommits(ctx, input.Owner, input.Repo, base, head)
+		if err != nil {
+			return nil, mapServiceError(ctx, "compare_repository_refs", err)
+		}
+		return &RepoCompareGetOutput{Body: toHTTPComparison(comparison)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-github-repo-readme",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/readme",
+		Summary:     "Get the repository README",
+		Description: "Returns the repository's README as raw bytes, or as sanitized HTML when format=html " +
+			"and the README is Markdown.",
+		Tags:      []string{"GitHub"},
+		Errors:    githubErrors,
+		Responses: contentResponses(api, false),
+	}, func(ctx context.Context, input *RepoReadmeGetInput) (*RepoContentsGetOutput, error) {
+		content, err := svc.GetReadme(ctx, input.Owner, input.Repo, input.Ref)
+		if err != nil {
+			return nil, mapServiceError(ctx, "get_repository_readme", err)
+		}
+		return contentOutput(content, input.Format)
+	})
+
+	getContents := func(ctx context.Context, owner, repo, path string, query ContentQuery) (*RepoContentsGetOutput, error) {
+		content, err := svc.GetContents(ctx, owner, repo, path, query.Ref)
+		if err != nil {
+			return nil, mapServiceError(ctx, "get_repository_contents", err)
+		}
+		return contentOutput(content, query.Format)
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-github-repo-root-contents",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/contents",
+		Summary:     "List repository root contents",
+		Description: "Returns the directory listing at the root of the specified GitHub repository.",
+		Tags:        []string{"GitHub"},
+		Errors:      githubErrors,
+		Responses:   contentResponses(api, true),
+	}, func(ctx context.Context, input *RepoRootContentsGetInput) (*RepoContentsGetOutput, error) {
+		return getContents(ctx, input.Owner, input.Repo, "", input.ContentQuery)
+	})
+
+	huma.Register(wildcardAPI{API: api, param: "path"}, huma.Operation{
+		OperationID: "get-github-repo-contents",
+		Method:      http.MethodGet,
+		Path:        "/github/repos/{owner}/{repo}/contents/{path}",
+		Summary:     "Get repository contents",
+		Description: "Returns a file's raw bytes with its media type, or a JSON listing when the path is a directory. " +
+			"The path may contain slashes. Markdown files can be rendered to sanitized HTML with format=html.",
+		Tags:      []string{"GitHub"},
+		Errors:    githubErrors,
+		Responses: contentResponses(api, true),
+	}, func(ctx context.Context, input *RepoContentsGetInput) (*RepoContentsGetOutput, error) {
+		return getContents(ctx, input.Owner, input.Repo, input.Path, input.ContentQuery)
+	})
+}
+
+// contentResponses documents the 200 response of content endpoints, whose body is
+// either raw file bytes, rendered HTML, or (for directories) a JSON listing.
+func contentResponses(api huma.API, listing bool) map[string]*huma.Response {
+	content := map[string]*huma.MediaType{
+		"application/octet-stream": {Schema: &huma.Schema{Type: huma.TypeString, Format: "binary"}},
+		"text/html":                {Schema: &huma.Schema{Type: huma.TypeString}},
+	}
+	description := "File bytes or rendered Markdown"
+	if listing {
+		content["application/json"] = &huma.MediaType{
+			Schema: api.OpenAPI().Components.Schemas.Schema(reflect.TypeFor[ContentsListData](), true, ""),
+		}
+		description = "File bytes, rendered Markdown, or a directory listing"
+	}
+	return map[string]*huma.Response{
+		"200": {Description: description, Content: content},
+	}
+}
+
+func contentOutput(content *githubsvc.Content, format string) (*RepoContentsGetOutput, error) {
+	if content.Type == githubsvc.ContentTypeDir {
+		if format == contentFormatHTML {
+			return nil, huma.Error422UnprocessableEntity("only Markdown files can be rendered as HTML")
+		}
+		return &RepoContentsGetOutput{Body: toHTTPContentsList(content.Entries)}, nil
+	}
+
+	if format == contentFormatHTML {
+		if !isMarkdownFile(content.Name) {
+			return nil, huma.Error422UnprocessableEntity("only Markdown files can be rendered as HTML")
+		}
+		rendered, err := renderMarkdown(content.Data)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to render markdown")
+		}
+		return &RepoContentsGetOutput{ContentType: "text/html; charset=utf-8", Body: rendered}, nil
+	}
+	return &RepoContentsGetOutput{ContentType: fileContentType(content.Name, content.Data), Body: content.Data}, nil
+}
+
+// fileContentType prefers the file extension and falls back to content sniffing.
+func fileContentType(name string, data []byte) string {
+	if isMarkdownFile(name) {
+		return "text/markdown; charset=utf-8"
+	}
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(data)
+}
+
+// decodeCursor verifies an optional pagination cursor and checks that it was issued for cursorType and
+// filter.
+func decodeCursor(cursors *pagination.Codec, raw, cursorType string, filter url.Values) (pagination.Cursor, error) {
+	cursor, err := cursors.Decode(raw, filter)
+	if err != nil {
+		return pagination.Cursor{}, huma.Error400BadRequest(err.Error())
+	}
+	if raw != "" && cursor.Type != cursorType {
+		return pagination.Cursor{}, huma.Error400BadRequest("cursor type mismatch")
+	}
+	return cursor, nil
+}
+
+// decodePageCursor decodes a cursor wrapping an upstream page number, counted in pages of limit
+// items, and returns that number.
+func decodePageCursor(cursors *pagination.Codec, raw, cursorType string, filter url.Values, limit int) (string, error) {
+	cursor, err := decodeCursor(cursors, raw, cursorType, pageCursorFilter(filter, limit))
+	if err != nil {
+		return "", err
+	}
+	if cursor.Value != "" {
+		if page, convErr := strconv.Atoi(cursor.Value); convErr != nil || page < 1 {
+			return "", huma.Error400BadRequest("invalid cursor format")
+		}
+	}
+	return cursor.Value, nil
+}
+
+// cursorFilter binds cursors to the repository as well as the query filters, so a cursor cannot be
+// replayed against another repository.
+func cursorFilter(owner, repo string, query url.Values) url.Values {
+	filter := url.Values{"owner": {owner}, "repo": {repo}}
+	maps.Copy(filter, query)
+	return filter
+}
+
+// streamPageLimit returns the upstream page size of a stream of a page-number listing. A stream from
+// the first page reads streamPageSize items at a time, but one that starts at a page cursor keeps the
+// page size the cursor was issued for, which its filter binds to limit, so it resumes at the right
+// item.
+func streamPageLimit(pageCursor string, limit int) int {
+	if pageCursor == "" {
+		return streamPageSize
+	}
+	return limit
+}
+
+// pageCursorFilter binds page-number cursors to the page size as well as filter. A page number only
+// locates the same items at the size it was counted in, so unlike keyset cursors these cannot
+// survive a change of limit.
+func pageCursorFilter(filter url.Values, limit int) url.Values {
+	bound := maps.Clone(filter)
+	bound.Set("limit", strconv.Itoa(limit))
+	return bound
+}
+
+// repoLinks holds the upstream cursors of a page of a repository sub-resource and the pages around
+// it. Empty cursors are unknown, except current, whose empty value is the first page. A nonzero
+// pageSize marks the cursors as page numbers counted in pages of that size.
+type repoLinks struct {
+	current, prev, next, last string
+	pageSize                  int
+}
+
+// pageNumberLinks returns the links of a page-number paginated resource with pages of pageSize items.
+// Page numbers make the previous page exact, and GitHub reports the last page number when it is
+// known.
+func pageNumberLinks(current, next, last string, pageSize int) repoLinks {
+	links := repoLinks{current: current, next: next, last: last, pageSize: pageSize}
+	if page, err := strconv.Atoi(current); err == nil && page > 1 {
+		links.prev = strconv.Itoa(page - 1)
+	}
+	return links
+}
+
+// repoLinkHeader builds the Link header for a repository sub-resource. The query carries active
+// filters so the linked pages apply them too.
+func repoLinkHeader(
+	cursors *pagination.Codec,
+	prefix, owner, repo, resource, cursorType string,
+	page repoLinks,
+	limit int,
+	query url.Values,
+) string {
+	filter := cursorFilter(owner, repo, query)
+	if page.pageSize > 0 {
+		filter = pageCursorFilter(filter, page.pageSize)
+	}
+	encode := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return cursors.Encode(pagination.Cursor{Type: cursorType, Value: value}, filter)
+	}
+	links := pagination.Links{
+		Self:  encode(page.current),
+		First: page.current != "",
+		Prev:  encode(page.prev),
+		Next:  encode(page.next),
+	}
+	if page.last != "" && page.last != page.current {
+		links.Last = encode(page.last)
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	return links.Header(
+		prefix+"/github/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/"+resource,
+		query,
+	)
+}
+
+func setIfNotEmpty(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
 }
 
 func mapServiceError(ctx context.Context, operation string, err error) error {
@@ -179,6 +781,12 @@ func mapServiceError(ctx context.Context, operation string, err error) error {
 			zap.String("operation", operation), zap.Error(err))
 		return huma.Error503ServiceUnavailable("upstream service temporarily unavailable")
 	}
+	if errors.Is(err, githubsvc.ErrContentTooLarge) {
+		return huma.Error422UnprocessableEntity("file exceeds the 1 MiB content size limit")
+	}
+	if errors.Is(err, githubsvc.ErrUnsupportedContent) {
+		return huma.Error422UnprocessableEntity("path is not a file or directory")
+	}
 	if upstreamErr, ok := errors.AsType[*githubsvc.UpstreamError](err); ok {
 		switch upstreamErr.Kind {
 		case githubsvc.UpstreamErrorKindNotFound:
@@ -307,6 +915,144 @@ func toHTTPTags(tags []githubsvc.Tag) []Tag {
 	return result
 }
 
+func toHTTPRelease(r *githubsvc.Release) Release {
+	assets := make([]ReleaseAsset, len(r.Assets))
+	for i, a := range r.Assets {
+		assets[i] = ReleaseAsset{
+			Name:          a.Name,
+			ContentType:   a.ContentType,
+			Size:          a.Size,
+			DownloadCount: a.DownloadCount,
+			DownloadURL:   a.DownloadURL,
+		}
+	}
+	return Release{
+		ID:          r.ID,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		HTMLURL:     r.HTMLURL,
+		Author:      r.Author,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		CreatedAt:   timeutil.Time{Time: r.CreatedAt},
+		PublishedAt: optionalTime(r.PublishedAt),
+		Assets:      assets,
+	}
+}
+
+func toHTTPReleases(releases []githubsvc.Release) []Release {
+	result := make([]Release, len(releases))
+	for i := range releases {
+		result[i] = toHTTPRelease(&releases[i])
+	}
+	return result
+}
+
+func toHTTPPullRequests(pulls []githubsvc.PullRequest) []PullRequest {
+	result := make([]PullRequest, len(pulls))
+	for i, p := range pulls {
+		result[i] = PullRequest{
+			Number:    p.Number,
+			Title:     p.Title,
+			State:     p.State,
+			Draft:     p.Draft,
+			Author:    p.Author,
+			HTMLURL:   p.HTMLURL,
+			BaseRef:   p.BaseRef,
+			HeadRef:   p.HeadRef,
+			Labels:    nonNilStrings(p.Labels),
+			Assignees: nonNilStrings(p.Assignees),
+			CreatedAt: timeutil.Time{Time: p.CreatedAt},
+			UpdatedAt: timeutil.Time{Time: p.UpdatedAt},
+			ClosedAt:  optionalTime(p.ClosedAt),
+			MergedAt:  optionalTime(p.MergedAt),
+		}
+	}
+	return result
+}
+
+func toHTTPIssues(issues []githubsvc.Issue) []Issue {
+	result := make([]Issue, len(issues))
+	for i, is := range issues {
+		result[i] = Issue{
+			Number:    is.Number,
+			Title:     is.Title,
+			State:     is.State,
+			Author:    is.Author,
+			HTMLURL:   is.HTMLURL,
+			Labels:    nonNilStrings(is.Labels),
+			Assignees: nonNilStrings(is.Assignees),
+			Comments:  is.Comments,
+			CreatedAt: timeutil.Time{Time: is.CreatedAt},
+			UpdatedAt: timeutil.Time{Time: is.UpdatedAt},
+			ClosedAt:  optionalTime(is.ClosedAt),
+		}
+	}
+	return result
+}
+
+func toHTTPCommits(commits []githubsvc.CommitSummary) []Commit {
+	result := make([]Commit, len(commits))
+	for i, c := range commits {
+		result[i] = Commit{
+			SHA:         c.SHA,
+			Message:     c.Message,
+			AuthorName:  c.AuthorName,
+			AuthorLogin: c.AuthorLogin,
+			AuthoredAt:  timeutil.Time{Time: c.AuthoredAt},
+			HTMLURL:     c.HTMLURL,
+		}
+	}
+	return result
+}
+
+func toHTTPComparison(c *githubsvc.Comparison) Comparison {
+	files := make([]FileChange, len(c.Files))
+	for i, f := range c.Files {
+		files[i] = FileChange{
+			Filename:         f.Filename,
+			PreviousFilename: f.PreviousFilename,
+			Status:           f.Status,
+			Additions:        f.Additions,
+			Deletions:        f.Deletions,
+			Changes:          f.Changes,
+		}
+	}
+	return Comparison{
+		Status:       c.Status,
+		AheadBy:      c.AheadBy,
+		BehindBy:     c.BehindBy,
+		TotalCommits: c.TotalCommits,
+		HTMLURL:      c.HTMLURL,
+		Commits:      toHTTPCommits(c.Commits),
+		Files:        files,
+	}
+}
+
+func toHTTPContentsList(entries []githubsvc.ContentEntry) ContentsListData {
+	result := make([]ContentEntry, len(entries))
+	for i, e := range entries {
+		result[i] = ContentEntry{Type: e.Type, Name: e.Name, Path: e.Path, SHA: e.SHA, Size: e.Size}
+	}
+	return ContentsListData{Entries: result, Count: len(result)}
+}
+
+// optionalTime returns nil for the zero time so optional timestamps are omitted from responses.
+func optionalTime(t time.Time) *timeutil.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &timeutil.Time{Time: t}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
 func toHTTPLanguages(languages map[string]int64) []Language {
 	result := make([]Language, 0, len(languages))
 	for name, bytes := range languages {
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -18,8 +22,11 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/janisto/huma-playground/internal/platform/fields"
 	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/stream"
 	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+	"github.com/janisto/huma-playground/internal/testutil"
 )
 
 type mockGitHubService struct {
@@ -29,7 +36,29 @@ type mockGitHubService struct {
 	activity  *githubsvc.ActivityPage
 	languages map[string]int64
 	tags      []githubsvc.Tag
+	releases  *githubsvc.ReleasePage
+	release   *githubsvc.Release
+	pulls     *githubsvc.PullRequestPage
+	issues    *githubsvc.IssuePage
+	commits   *githubsvc.CommitPage
+	compare   *githubsvc.Comparison
+	content   *githubsvc.Content
 	err       error
+
+	// Per-method errors take precedence over err for tests that need partial failures.
+	repoErr      error
+	languagesErr error
+	tagsErr      error
+	activityErr  error
+
+	releaseCursor string
+	releaseTag    string
+	pullParams    githubsvc.PullRequestListParams
+	issueParams   githubsvc.IssueListParams
+	commitParams  githubsvc.CommitListParams
+	compareRefs   [2]string
+	contentPath   string
+	contentRef    string
 }
 
 func (m *mockGitHubService) GetOwner(_ context.Context, _ string) (*githubsvc.Owner, error) {
@@ -47,6 +76,9 @@ func (m *mockGitHubService) ListRepos(_ context.Context, _ string) ([]githubsvc.
 }
 
 func (m *mockGitHubService) GetRepo(_ context.Context, _, _ string) (*githubsvc.Repo, error) {
+	if m.repoErr != nil {
+		return nil, m.repoErr
+	}
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -59,13 +91,33 @@ func (m *mockGitHubService) ListActivity(
 	_ int,
 	_ string,
 ) (*githubsvc.ActivityPage, error) {
+	if m.activityErr != nil {
+		return nil, m.activityErr
+	}
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.activity, nil
 }
 
+func (m *mockGitHubService) PollActivity(_ context.Context, _, _, _ string) (*githubsvc.ActivityPoll, error) {
+	if m.activityErr != nil {
+		return nil, m.activityErr
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	poll := &githubsvc.ActivityPoll{}
+	if m.activity != nil {
+		poll.Activities = m.activity.Activities
+	}
+	return poll, nil
+}
+
 func (m *mockGitHubService) ListLanguages(_ context.Context, _, _ string) (map[string]int64, error) {
+	if m.languagesErr != nil {
+		return nil, m.languagesErr
+	}
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -73,14 +125,109 @@ func (m *mockGitHubService) ListLanguages(_ context.Context, _, _ string) (map[s
 }
 
 func (m *mockGitHubService) ListTags(_ context.Context, _, _ string) ([]githubsvc.Tag, error) {
+	if m.tagsErr != nil {
+		return nil, m.tagsErr
+	}
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.tags, nil
 }
 
+func (m *mockGitHubService) ListReleases(
+	_ context.Context,
+	_, _ string,
+	_ int,
+	pageCursor string,
+) (*githubsvc.ReleasePage, error) {
+	m.releaseCursor = pageCursor
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.releases, nil
+}
+
+func (m *mockGitHubService) GetLatestRelease(_ context.Context, _, _ string) (*githubsvc.Release, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.release, nil
+}
+
+func (m *mockGitHubService) GetReleaseByTag(_ context.Context, _, _, tag string) (*githubsvc.Release, error) {
+	m.releaseTag = tag
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.release, nil
+}
+
+func (m *mockGitHubService) ListPullRequests(
+	_ context.Context,
+	_, _ string,
+	params githubsvc.PullRequestListParams,
+) (*githubsvc.PullRequestPage, error) {
+	m.pullParams = params
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.pulls, nil
+}
+
+func (m *mockGitHubService) ListIssues(
+	_ context.Context,
+	_, _ string,
+	params githubsvc.IssueListParams,
+) (*githubsvc.IssuePage, error) {
+	m.issueParams = params
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.issues, nil
+}
+
+func (m *mockGitHubService) ListCommits(
+	_ context.Context,
+	_, _ string,
+	params githubsvc.CommitListParams,
+) (*githubsvc.CommitPage, error) {
+	m.commitParams = params
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.commits, nil
+}
+
+func (m *mockGitHubService) CompareCommits(_ context.Context, _, _, base, head string) (*githubsvc.Comparison, error) {
+	m.compareRefs = [2]string{base, head}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.compare, nil
+}
+
+func (m *mockGitHubService) GetContents(_ context.Context, _, _, path, ref string) (*githubsvc.Content, error) {
+	m.contentPath = path
+	m.contentRef = ref
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.content, nil
+}
+
+func (m *mockGitHubService) GetReadme(_ context.Context, _, _, ref string) (*githubsvc.Content, error) {
+	m.contentRef = ref
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.content, nil
+}
+
 var _ githubsvc.Service = (*mockGitHubService)(nil)
 
+// testCursors signs the cursors the test router accepts.
+var testCursors = testutil.Cursors()
+
 func newTestRouter(svc githubsvc.Service) chi.Router {
 	return newTestRouterWithLogger(svc, zap.NewNop())
 }
@@ -90,13 +237,64 @@ func newTestRouterWithLogger(svc githubsvc.Service, logger *zap.Logger) chi.Rout
 	router.Use(
 		chimiddleware.ClientIPFromRemoteAddr,
 	)
-	api := humachi.New(router, huma.DefaultConfig("GitHubTest", "test"))
+	config := huma.DefaultConfig("GitHubTest", "test")
+	config.Transformers = append(config.Transformers, fields.Transform)
+	api := humachi.New(router, config)
+	fields.Register(api)
 	api.UseMiddleware(obs.RequestContext(obs.RequestContextConfig{Logger: logger}))
 	api.UseMiddleware(obs.AccessLogger(obs.AccessLoggerConfig{Logger: logger}))
-	Register(api, svc, "")
+	api.UseMiddleware(stream.Middleware())
+	Register(api, svc, "", testCursors)
 	return router
 }
 
+// repoCursor mints a cursor for the unfiltered octocat/git-consortium listings.
+func repoCursor(cursorType, value string) string {
+	return testCursors.Encode(
+		pagination.Cursor{Type: cursorType, Value: value},
+		cursorFilter("octocat", "git-consortium", nil),
+	)
+}
+
+// repoPageCursor mints a page-number cursor for the unfiltered octocat/git-consortium listings at
+// the default page size.
+func repoPageCursor(cursorType, page string) string {
+	return testCursors.Encode(
+		pagination.Cursor{Type: cursorType, Value: page},
+		pageCursorFilter(cursorFilter("octocat", "git-consortium", nil), 20),
+	)
+}
+
+// nextLink parses the rel="next" target of a page-number listing's Link header and verifies its cursor
+// against the octocat/git-consortium listing filtered by query, at the page size the link requests.
+func nextLink(t *testing.T, header string, query url.Values) (*url.URL, pagination.Cursor) {
+	t.Helper()
+	var part string
+	for link := range strings.SplitSeq(header, ", ") {
+		if strings.HasSuffix(link, `rel="next"`) {
+			part = link
+		}
+	}
+	start, end := strings.Index(part, "<"), strings.Index(part, ">")
+	if start < 0 || end < start {
+		t.Fatalf("expected a next link, got %q", header)
+	}
+	next, err := url.Parse(part[start+1 : end])
+	if err != nil {
+		t.Fatalf("parse next link: %v", err)
+	}
+	limit, err := strconv.Atoi(next.Query().Get("limit"))
+	if err != nil {
+		t.Fatalf("expected the next link to carry the page size, got %q", next)
+	}
+	filter := pageCursorFilter(cursorFilter("octocat", "git-consortium", query), limit)
+	cursor, err := testCursors.Decode(next.Query().Get("cursor"), filter)
+	if err != nil {
+		t.Fatalf("decode next cursor: %v", err)
+	}
+	return next, cursor
+}
+
 func testOwner() *githubsvc.Owner {
 	return &githubsvc.Owner{
 		Login:     "octocat",
@@ -149,6 +347,26 @@ func testActivity() githubsvc.Activity {
 	}
 }
 
+func testRelease() githubsvc.Release {
+	return githubsvc.Release{
+		ID:          1,
+		TagName:     "v1.0.0",
+		Name:        "v1.0.0",
+		Body:        "Initial release",
+		HTMLURL:     "https://github.com/octocat/git-consortium/releases/tag/v1.0.0",
+		Author:      "octocat",
+		CreatedAt:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		PublishedAt: time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+		Assets: []githubsvc.ReleaseAsset{{
+			Name:          "app-linux-amd64.tar.gz",
+			ContentType:   "application/gzip",
+			Size:          1048576,
+			DownloadCount: 42,
+			DownloadURL:   "https://github.com/octocat/git-consortium/releases/download/v1.0.0/app-linux-amd64.tar.gz",
+		}},
+	}
+}
+
 // --- GetOwner ---
 
 func TestGetOwnerSuccess(t *testing.T) {
@@ -184,6 +402,40 @@ func TestGetOwnerSuccess(t *testing.T) {
 	}
 }
 
+func TestGetOwnerFieldSelection(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{owner: testOwner(), repos: []githubsvc.RepoSummary{testRepoSummary()}})
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/owners/octocat?fields=login,avatarUrl",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	want := `{"avatarUrl":"https://avatars.githubusercontent.com/u/583231","login":"octocat"}`
+	if got := strings.TrimSpace(resp.Body.String()); resp.Code != http.StatusOK || got != want {
+		t.Fatalf("expected 200 %s, got %d %s", want, resp.Code, got)
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/github/owners/octocat/repos?fields=name", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	want = `{"count":1,"repos":[{"name":"git-consortium"}]}`
+	if got := strings.TrimSpace(resp.Body.String()); resp.Code != http.StatusOK || got != want {
+		t.Fatalf("expected 200 %s, got %d %s", want, resp.Code, got)
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/github/owners/octocat/repos?fields=count", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a field outside the records, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
 func TestGetOwnerNotFound(t *testing.T) {
 	svc := &mockGitHubService{err: githubsvc.ErrNotFound}
 	router := newTestRouter(svc)
@@ -524,6 +776,136 @@ func TestListActivityWithPagination(t *testing.T) {
 	}
 }
 
+// pagedActivityService serves activity pages keyed by upstream cursor, failing on unknown cursors.
+type pagedActivityService struct {
+	*mockGitHubService
+	pages   map[string]*githubsvc.ActivityPage
+	limits  []int
+	cursors []string
+}
+
+func (m *pagedActivityService) ListActivity(
+	_ context.Context,
+	_, _ string,
+	limit int,
+	cursor string,
+) (*githubsvc.ActivityPage, error) {
+	m.limits = append(m.limits, limit)
+	m.cursors = append(m.cursors, cursor)
+	page, ok := m.pages[cursor]
+	if !ok {
+		return nil, githubsvc.ErrUpstream
+	}
+	return page, nil
+}
+
+func activityWithID(id int64) githubsvc.Activity {
+	activity := testActivity()
+	activity.ID = id
+	return activity
+}
+
+func TestListActivityStreamsEveryPage(t *testing.T) {
+	svc := &pagedActivityService{mockGitHubService: &mockGitHubService{}, pages: map[string]*githubsvc.ActivityPage{
+		"":   {Activities: []githubsvc.Activity{activityWithID(1), activityWithID(2)}, NextCursor: "c2"},
+		"c2": {Activities: []githubsvc.Activity{activityWithID(3)}, NextCursor: "c3"},
+		"c3": {Activities: []githubsvc.Activity{activityWithID(4)}},
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/github/repos/octocat/git-consortium/activity?limit=1", nil)
+	req.Header.Set("Accept", stream.NDJSON)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Type"); got != stream.NDJSON {
+		t.Fatalf("expected Content-Type %q, got %q", stream.NDJSON, got)
+	}
+	if resp.Header().Get("Link") != "" {
+		t.Fatalf("expected no Link header on a stream, got %q", resp.Header().Get("Link"))
+	}
+	var ids []int64
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var activity Activity
+		if err := dec.Decode(&activity); err != nil {
+			t.Fatalf("decode record: %v", err)
+		}
+		ids = append(ids, activity.ID)
+	}
+	if want := []int64{1, 2, 3, 4}; !slices.Equal(ids, want) {
+		t.Fatalf("expected activity %v, got %v", want, ids)
+	}
+	if want := []string{"", "c2", "c3"}; !slices.Equal(svc.cursors, want) {
+		t.Fatalf("expected upstream cursors %v, got %v", want, svc.cursors)
+	}
+	if want := []int{100, 100, 100}; !slices.Equal(svc.limits, want) {
+		t.Fatalf("expected upstream page size 100, got %v", svc.limits)
+	}
+}
+
+func TestListActivityStreamFailsBeforeFirstRecord(t *testing.T) {
+	svc := &pagedActivityService{mockGitHubService: &mockGitHubService{}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/github/repos/octocat/git-consortium/activity", nil)
+	req.Header.Set("Accept", stream.NDJSON)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Fatalf("expected Problem Details, got %q", got)
+	}
+}
+
+func TestListCommitsStreamStartsAtCursor(t *testing.T) {
+	svc := &mockGitHubService{commits: &githubsvc.CommitPage{
+		Commits:    []githubsvc.CommitSummary{{SHA: "abc123"}},
+		NextCursor: "3",
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/github/repos/octocat/git-consortium/commits?branch=main&limit=10", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	next, _ := nextLink(t, resp.Header().Get("Link"), url.Values{"branch": {"main"}})
+
+	// Page 3 of 10 starts at item 21, so the stream keeps reading pages of 10 rather than 100.
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, next.String(), nil)
+	req.Header.Set("Accept", stream.NDJSON)
+	svc.commits.NextCursor = ""
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if svc.commitParams.PageCursor != "3" || svc.commitParams.Limit != 10 || svc.commitParams.Branch != "main" {
+		t.Fatalf("expected the stream to start at page 3 of main with 10 per page, got %+v", svc.commitParams)
+	}
+	if got := strings.Count(resp.Body.String(), "\n"); got != 1 {
+		t.Fatalf("expected one record, got %q", resp.Body.String())
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/github/repos/octocat/git-consortium/commits?limit=10", nil)
+	req.Header.Set("Accept", stream.NDJSON)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if svc.commitParams.PageCursor != "" || svc.commitParams.Limit != streamPageSize {
+		t.Fatalf("expected a stream from the first page to read %d per page, got %+v", streamPageSize, svc.commitParams)
+	}
+}
+
 func TestListActivityInvalidCursor(t *testing.T) {
 	svc := &mockGitHubService{}
 	router := newTestRouter(svc)
@@ -553,7 +935,7 @@ func TestListActivityCursorTypeMismatch(t *testing.T) {
 	svc := &mockGitHubService{}
 	router := newTestRouter(svc)
 
-	cursor := pagination.Cursor{Type: "wrong-type", Value: "some-value"}.Encode()
+	cursor := repoCursor("wrong-type", "some-value")
 	req := httptest.NewRequestWithContext(
 		t.Context(),
 		http.MethodGet,
@@ -579,7 +961,7 @@ func TestListActivityCursorTypeMismatch(t *testing.T) {
 func TestListActivityCursorRequiresType(t *testing.T) {
 	svc := &mockGitHubService{}
 	router := newTestRouter(svc)
-	cursor := pagination.Cursor{Value: "some-value"}.Encode()
+	cursor := repoCursor("", "some-value")
 	req := httptest.NewRequestWithContext(
 		t.Context(),
 		http.MethodGet,
@@ -773,6 +1155,1096 @@ func TestListTagsUpstreamError(t *testing.T) {
 	}
 }
 
+// --- ListReleases ---
+
+func TestListReleasesSuccess(t *testing.T) {
+	svc := &mockGitHubService{releases: &githubsvc.ReleasePage{
+		Releases:   []githubsvc.Release{testRelease()},
+		NextCursor: "3",
+	}}
+	router := newTestRouter(svc)
+
+	cursor := testCursors.Encode(
+		pagination.Cursor{Type: releaseCursorType, Value: "2"},
+		pageCursorFilter(cursorFilter("octocat", "git-consortium", nil), 5),
+	)
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases?limit=5&cursor="+cursor,
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if svc.releaseCursor != "2" {
+		t.Errorf("expected page cursor 2, got %q", svc.releaseCursor)
+	}
+
+	var data RepoReleaseListData
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if data.Count != 1 {
+		t.Fatalf("expected count 1, got %d", data.Count)
+	}
+	release := data.Releases[0]
+	if release.TagName != "v1.0.0" || release.PublishedAt == nil {
+		t.Errorf("unexpected release: %+v", release)
+	}
+	if len(release.Assets) != 1 || release.Assets[0].DownloadCount != 42 ||
+		release.Assets[0].ContentType != "application/gzip" {
+		t.Errorf("unexpected assets: %+v", release.Assets)
+	}
+
+	next, nextCursor := nextLink(t, resp.Header().Get("Link"), nil)
+	if next.Path != "/github/repos/octocat/git-consortium/releases" || next.Query().Get("limit") != "5" {
+		t.Errorf("unexpected next link: %s", next)
+	}
+	if nextCursor != (pagination.Cursor{Type: releaseCursorType, Value: "3"}) {
+		t.Errorf("unexpected next cursor: %+v", nextCursor)
+	}
+}
+
+func TestListReleasesRejectsCursorAtAnotherPageSize(t *testing.T) {
+	svc := &mockGitHubService{releases: &githubsvc.ReleasePage{Releases: []githubsvc.Release{testRelease()}}}
+	router := newTestRouter(svc)
+	cursor := testCursors.Encode(
+		pagination.Cursor{Type: releaseCursorType, Value: "3"},
+		pageCursorFilter(cursorFilter("octocat", "git-consortium", nil), 10),
+	)
+
+	// Page 3 of 10 holds items 21-30; at 50 per page it would be items 101-150.
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases?limit=50&cursor="+cursor, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a page cursor at another page size, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if svc.releaseCursor != "" {
+		t.Fatalf("expected no upstream request, got page %q", svc.releaseCursor)
+	}
+}
+
+func TestListReleasesLinksEveryKnownPage(t *testing.T) {
+	svc := &mockGitHubService{releases: &githubsvc.ReleasePage{
+		Releases:   []githubsvc.Release{testRelease()},
+		NextCursor: "4",
+		LastCursor: "9",
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases?cursor="+repoPageCursor(releaseCursorType, "3"),
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	links := map[string]string{}
+	for part := range strings.SplitSeq(resp.Header().Get("Link"), ", ") {
+		target, rel, _ := strings.Cut(part, ">; rel=")
+		link, err := url.Parse(strings.TrimPrefix(target, "<"))
+		if err != nil {
+			t.Fatalf("parse link %q: %v", part, err)
+		}
+		page := ""
+		if raw := link.Query().Get("cursor"); raw != "" {
+			cursor, err := testCursors.Decode(raw, pageCursorFilter(cursorFilter("octocat", "git-consortium", nil), 20))
+			if err != nil {
+				t.Fatalf("decode %s cursor: %v", rel, err)
+			}
+			page = cursor.Value
+		}
+		links[strings.Trim(rel, `"`)] = page
+	}
+	want := map[string]string{"self": "3", "first": "", "prev": "2", "next": "4", "last": "9"}
+	if !maps.Equal(links, want) {
+		t.Fatalf("expected page links %v, got %v", want, links)
+	}
+}
+
+func TestListReleasesDraftOmitsPublishedAt(t *testing.T) {
+	draft := testRelease()
+	draft.Draft = true
+	draft.PublishedAt = time.Time{}
+	svc := &mockGitHubService{releases: &githubsvc.ReleasePage{Releases: []githubsvc.Release{draft}}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if strings.Contains(resp.Body.String(), "publishedAt") {
+		t.Errorf("expected publishedAt to be omitted for drafts: %s", resp.Body.String())
+	}
+	if strings.Contains(resp.Header().Get("Link"), `rel="next"`) {
+		t.Errorf("expected no rel=next in Link header, got %s", resp.Header().Get("Link"))
+	}
+}
+
+func TestListReleasesRejectsInvalidCursors(t *testing.T) {
+	tests := map[string]string{
+		"malformed":     "not-valid-base64!",
+		"wrong type":    repoCursor(activityCursorType, "2"),
+		"non-numeric":   repoPageCursor(releaseCursorType, "abc"),
+		"zero page":     repoPageCursor(releaseCursorType, "0"),
+		"negative page": repoPageCursor(releaseCursorType, "-1"),
+		"legacy format": pagination.Cursor{Type: releaseCursorType, Value: "2"}.Encode(),
+		"other repository": testCursors.Encode(
+			pagination.Cursor{Type: releaseCursorType, Value: "2"},
+			pageCursorFilter(cursorFilter("octocat", "hello-world", nil), 20),
+		),
+	}
+	for name, cursor := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := &mockGitHubService{}
+			router := newTestRouter(svc)
+
+			req := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"/github/repos/octocat/git-consortium/releases?cursor="+cursor,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestListReleasesRateLimited(t *testing.T) {
+	svc := &mockGitHubService{err: &githubsvc.UpstreamError{
+		Kind:       githubsvc.UpstreamErrorKindRateLimited,
+		Status:     http.StatusForbidden,
+		RetryAfter: "60",
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp.Header().Get("Retry-After") != "60" {
+		t.Errorf("expected Retry-After 60, got %q", resp.Header().Get("Retry-After"))
+	}
+}
+
+// --- GetRelease ---
+
+func TestGetLatestReleaseSuccess(t *testing.T) {
+	release := testRelease()
+	svc := &mockGitHubService{release: &release}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases/latest",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var data Release
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if data.TagName != "v1.0.0" || data.Author != "octocat" {
+		t.Errorf("unexpected release: %+v", data)
+	}
+}
+
+func TestGetLatestReleaseNotFound(t *testing.T) {
+	svc := &mockGitHubService{err: githubsvc.ErrNotFound}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases/latest",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestGetReleaseByTagSuccess(t *testing.T) {
+	release := testRelease()
+	svc := &mockGitHubService{release: &release}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases/tags/v1.0.0",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if svc.releaseTag != "v1.0.0" {
+		t.Errorf("expected tag v1.0.0, got %q", svc.releaseTag)
+	}
+}
+
+func TestGetReleaseByTagRejectsInvalidTag(t *testing.T) {
+	svc := &mockGitHubService{}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases/tags/..",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestGetReleaseByTagUpstreamError(t *testing.T) {
+	svc := &mockGitHubService{err: githubsvc.ErrUpstream}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/releases/tags/v1.0.0",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+// --- ListPullRequests ---
+
+func TestListPullRequestsSuccess(t *testing.T) {
+	svc := &mockGitHubService{pulls: &githubsvc.PullRequestPage{
+		PullRequests: []githubsvc.PullRequest{{
+			Number:    1347,
+			Title:     "Amazing new feature",
+			State:     "open",
+			Author:    "octocat",
+			BaseRef:   "main",
+			HeadRef:   "new-topic",
+			CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2024, 1, 16, 10, 30, 0, 0, time.UTC),
+		}},
+		NextCursor: "2",
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/pulls?state=all&base=release/v1&sort=updated&direction=asc&limit=5",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	want := githubsvc.PullRequestListParams{
+		State:     "all",
+		Base:      "release/v1",
+		Sort:      "updated",
+		Direction: "asc",
+		Limit:     5,
+	}
+	if svc.pullParams != want {
+		t.Errorf("expected params %+v, got %+v", want, svc.pullParams)
+	}
+
+	var data RepoPullRequestListData
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if data.Count != 1 || data.PullRequests[0].Number != 1347 {
+		t.Fatalf("unexpected pull requests: %+v", data)
+	}
+	if data.PullRequests[0].Labels == nil || data.PullRequests[0].ClosedAt != nil {
+		t.Errorf("expected empty labels and no closedAt: %+v", data.PullRequests[0])
+	}
+
+	filters := url.Values{"base": {"release/v1"}, "direction": {"asc"}, "sort": {"updated"}, "state": {"all"}}
+	next, cursor := nextLink(t, resp.Header().Get("Link"), filters)
+	query := next.Query()
+	query.Del("cursor")
+	if want := "base=release%2Fv1&direction=asc&limit=5&sort=updated&state=all"; query.Encode() != want {
+		t.Errorf("expected Link header to preserve filters, got %s", next)
+	}
+	if cursor != (pagination.Cursor{Type: pullCursorType, Value: "2"}) {
+		t.Errorf("unexpected next cursor: %+v", cursor)
+	}
+}
+
+func TestListPullRequestsRejectsInvalidFilters(t *testing.T) {
+	tests := map[string]string{
+		"unknown state":     "state=merged",
+		"unknown sort":      "sort=comments",
+		"unknown direction": "direction=up",
+		"invalid base":      "base=feature..x%20y",
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := newTestRouter(&mockGitHubService{})
+
+			req := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"/github/repos/octocat/git-consortium/pulls?"+query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestListPullRequestsCursorTypeMismatch(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{})
+
+	cursor := repoPageCursor(issueCursorType, "2")
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/pulls?cursor="+cursor,
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestListPullRequestsNotFound(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{err: githubsvc.ErrNotFound})
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/unknown/pulls",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+// --- ListIssues ---
+
+func TestListIssuesSuccess(t *testing.T) {
+	svc := &mockGitHubService{issues: &githubsvc.IssuePage{
+		Issues: []githubsvc.Issue{{
+			Number:    1,
+			Title:     "Found a bug",
+			State:     "closed",
+			Labels:    []string{"bug"},
+			Comments:  3,
+			CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2024, 1, 16, 10, 30, 0, 0, time.UTC),
+			ClosedAt:  time.Date(2024, 1, 17, 10, 30, 0, 0, time.UTC),
+		}},
+		NextCursor: "4",
+	}}
+	router := newTestRouter(svc)
+
+	filter := cursorFilter("octocat", "git-consortium", url.Values{
+		"state": {"closed"}, "labels": {"bug,ui"}, "assignee": {"*"},
+	})
+	cursor := testCursors.Encode(pagination.Cursor{Type: issueCursorType, Value: "3"}, pageCursorFilter(filter, 20))
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/issues?labels=bug,ui&assignee=*&state=closed&cursor="+cursor,
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := svc.issueParams; got.State != "closed" || got.Assignee != "*" || got.PageCursor != "3" ||
+		strings.Join(got.Labels, ",") != "bug,ui" {
+		t.Errorf("unexpected params: %+v", got)
+	}
+
+	var data RepoIssueListData
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if data.Count != 1 || data.Issues[0].Comments != 3 || data.Issues[0].ClosedAt == nil {
+		t.Fatalf("unexpected issues: %+v", data)
+	}
+
+	linkHeader := resp.Header().Get("Link")
+	if !strings.Contains(linkHeader, "labels=bug%2Cui") || !strings.Contains(linkHeader, "assignee=%2A") {
+		t.Errorf("expected Link header to preserve filters, got %s", linkHeader)
+	}
+}
+
+func TestListIssuesRejectsInvalidFilters(t *testing.T) {
+	tests := map[string]string{
+		"unknown sort":     "sort=popularity",
+		"invalid assignee": "assignee=-octocat",
+		"too many labels":  "labels=a,b,c,d,e,f,g,h,i,j,k",
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := newTestRouter(&mockGitHubService{})
+
+			req := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"/github/repos/octocat/git-consortium/issues?"+query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestListIssuesUpstreamError(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{err: githubsvc.ErrUpstream})
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/issues",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+// --- ListCommits ---
+
+func testCommit() githubsvc.CommitSummary {
+	return githubsvc.CommitSummary{
+		SHA:         "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		Message:     "Fix all the bugs",
+		AuthorName:  "Monalisa Octocat",
+		AuthorLogin: "octocat",
+		AuthoredAt:  time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		HTMLURL:     "https://github.com/octocat/git-consortium/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e",
+	}
+}
+
+func TestListCommitsSuccess(t *testing.T) {
+	svc := &mockGitHubService{commits: &githubsvc.CommitPage{
+		Commits:    []githubsvc.CommitSummary{testCommit()},
+		NextCursor: "2",
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/commits?branch=main&path=docs/README.md&author=octocat"+
+			"&since=2024-01-01T00:00:00Z&until=2024-02-01T00:00:00Z",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	got := svc.commitParams
+	if got.Branch != "main" || got.Path != "docs/README.md" || got.Author != "octocat" ||
+		!got.Since.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		!got.Until.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected params: %+v", got)
+	}
+
+	var data RepoCommitListData
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if data.Count != 1 || data.Commits[0].AuthorLogin != "octocat" {
+		t.Fatalf("unexpected commits: %+v", data)
+	}
+
+	linkHeader := resp.Header().Get("Link")
+	for _, want := range []string{"branch=main", "path=docs%2FREADME.md", "since=2024-01-01T00%3A00%3A00Z"} {
+		if !strings.Contains(linkHeader, want) {
+			t.Errorf("expected Link header to contain %s, got %s", want, linkHeader)
+		}
+	}
+}
+
+func TestListCommitsRejectsInvalidFilters(t *testing.T) {
+	tests := map[string]string{
+		"until before since": "since=2024-02-01T00:00:00Z&until=2024-01-01T00:00:00Z",
+		"malformed since":    "since=yesterday",
+		"invalid branch":     "branch=main%20branch",
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := newTestRouter(&mockGitHubService{})
+
+			req := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"/github/repos/octocat/git-consortium/commits?"+query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestListCommitsInvalidCursor(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{})
+
+	cursor := repoPageCursor(commitCursorType, "abc")
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/commits?cursor="+cursor,
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+// --- CompareCommits ---
+
+func TestCompareCommitsSuccess(t *testing.T) {
+	svc := &mockGitHubService{compare: &githubsvc.Comparison{
+		Status:       "ahead",
+		AheadBy:      1,
+		TotalCommits: 1,
+		Commits:      []githubsvc.CommitSummary{testCommit()},
+		Files: []githubsvc.FileChange{
+			{Filename: "main.go", Status: "modified", Additions: 3, Deletions: 1, Changes: 4},
+		},
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/compare/v1.0.0...feature%2Fnew-ui",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if svc.compareRefs != [2]string{"v1.0.0", "feature/new-ui"} {
+		t.Errorf("unexpected refs: %v", svc.compareRefs)
+	}
+
+	var data Comparison
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if data.AheadBy != 1 || len(data.Commits) != 1 || len(data.Files) != 1 || data.Files[0].Changes != 4 {
+		t.Errorf("unexpected comparison: %+v", data)
+	}
+	if strings.Contains(resp.Body.String(), "previousFilename") {
+		t.Errorf("expected previousFilename to be omitted: %s", resp.Body.String())
+	}
+}
+
+func TestCompareCommitsRejectsInvalidBaseHead(t *testing.T) {
+	for _, basehead := range []string{"main", "main..dev", "main...", "...dev", "main....dev", "a..b...dev"} {
+		t.Run(basehead, func(t *testing.T) {
+			router := newTestRouter(&mockGitHubService{})
+
+			req := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"/github/repos/octocat/git-consortium/compare/"+basehead,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompareCommitsNotFound(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{err: githubsvc.ErrNotFound})
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/compare/main...unknown",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+// --- Insights ---
+
+func TestGetRepoInsights(t *testing.T) {
+	repo := testRepo()
+	repo.UpdatedAt = time.Now().Add(-72 * time.Hour)
+	svc := &mockGitHubService{
+		repo:      repo,
+		languages: map[string]int64{"Go": 750, "Shell": 250},
+		tags:      []githubsvc.Tag{{Name: "v2.0.0"}, {Name: "v1.0.0"}},
+		activity: &githubsvc.ActivityPage{Activities: []githubsvc.Activity{
+			{ID: 1, Actor: "octocat", ActivityType: "push", Timestamp: time.Now().Add(-time.Hour)},
+			{ID: 2, Actor: "hubot", ActivityType: "push", Timestamp: time.Now().Add(-2 * time.Hour)},
+			{ID: 3, Actor: "octocat", ActivityType: "branch_creation", Timestamp: time.Now().Add(-3 * time.Hour)},
+		}},
+	}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/insights",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var insights RepoInsights
+	if err := json.Unmarshal(resp.Body.Bytes(), &insights); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if len(insights.Errors) != 0 {
+		t.Fatalf("expected no section errors, got %+v", insights.Errors)
+	}
+	if insights.Repo == nil || insights.Repo.Name != repo.Name {
+		t.Errorf("unexpected repo section: %+v", insights.Repo)
+	}
+	if insights.Health == nil || insights.Health.DaysSinceUpdate != 3 || !insights.Health.HasLicense {
+		t.Errorf("unexpected health section: %+v", insights.Health)
+	}
+	if insights.Languages == nil || insights.Languages.TotalBytes != 1000 {
+		t.Fatalf("unexpected languages section: %+v", insights.Languages)
+	}
+	if first := insights.Languages.Languages[0]; first.Name != "Go" || first.Percent != 75 {
+		t.Errorf("expected Go at 75%%, got %+v", first)
+	}
+	if insights.Tags == nil || insights.Tags.Count != 2 || insights.Tags.Latest.Name != "v2.0.0" {
+		t.Errorf("unexpected tags section: %+v", insights.Tags)
+	}
+	activity := insights.Activity
+	if activity == nil || activity.Events != 3 || activity.Contributors != 2 || !activity.Active {
+		t.Fatalf("unexpected activity section: %+v", activity)
+	}
+	if activity.Types["push"] != 2 || activity.Types["branch_creation"] != 1 {
+		t.Errorf("unexpected activity types: %v", activity.Types)
+	}
+}
+
+func TestGetRepoInsightsPartialFailure(t *testing.T) {
+	svc := &mockGitHubService{
+		repo:         testRepo(),
+		tags:         []githubsvc.Tag{},
+		activity:     &githubsvc.ActivityPage{},
+		languagesErr: errors.New("boom"),
+		activityErr:  &githubsvc.UpstreamError{Kind: githubsvc.UpstreamErrorKindRateLimited},
+	}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/insights",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var insights RepoInsights
+	if err := json.Unmarshal(resp.Body.Bytes(), &insights); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if insights.Repo == nil || insights.Tags == nil {
+		t.Errorf("expected repo and tags sections, got %+v", insights)
+	}
+	if insights.Tags != nil && insights.Tags.Latest != nil {
+		t.Errorf("expected no latest tag, got %+v", insights.Tags.Latest)
+	}
+	if insights.Languages != nil || insights.Activity != nil {
+		t.Errorf("expected failed sections to be omitted, got %+v", insights)
+	}
+	want := []InsightError{
+		{Section: "languages", Status: http.StatusBadGateway, Detail: "upstream error"},
+		{Section: "activity", Status: http.StatusTooManyRequests, Detail: "rate limit exceeded"},
+	}
+	if len(insights.Errors) != len(want) {
+		t.Fatalf("expected %d section errors, got %+v", len(want), insights.Errors)
+	}
+	for i := range want {
+		if insights.Errors[i] != want[i] {
+			t.Errorf("error %d: expected %+v, got %+v", i, want[i], insights.Errors[i])
+		}
+	}
+	if strings.Contains(resp.Body.String(), "boom") {
+		t.Errorf("expected upstream details to be hidden, got %s", resp.Body.String())
+	}
+}
+
+func TestGetRepoInsightsAllSectionsFail(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{err: githubsvc.ErrNotFound})
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/missing/insights",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+// --- Contents ---
+
+func testFileContent(name, data string) *githubsvc.Content {
+	return &githubsvc.Content{
+		Type: githubsvc.ContentTypeFile,
+		Name: name,
+		Path: name,
+		Size: int64(len(data)),
+		Data: []byte(data),
+	}
+}
+
+func TestGetReadmeRaw(t *testing.T) {
+	svc := &mockGitHubService{content: testFileContent("README.md", "# Hello\n")}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/readme?ref=v1.0.0",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "text/markdown; charset=utf-8" {
+		t.Errorf("expected markdown content type, got %q", ct)
+	}
+	if resp.Body.String() != "# Hello\n" {
+		t.Errorf("unexpected body: %q", resp.Body.String())
+	}
+	if svc.contentRef != "v1.0.0" {
+		t.Errorf("expected ref v1.0.0, got %q", svc.contentRef)
+	}
+}
+
+func TestGetReadmeRendersSanitizedHTML(t *testing.T) {
+	markdown := "# Title\n\n<script>alert(1)</script>\n\n[click](javascript:alert(1)) | a |\n|---|\n| b |\n"
+	svc := &mockGitHubService{content: testFileContent("README.md", markdown)}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/readme?format=html",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("expected HTML content type, got %q", ct)
+	}
+	body := resp.Body.String()
+	if !strings.Contains(body, "<h1>Title</h1>") {
+		t.Errorf("expected rendered heading, got %s", body)
+	}
+	if strings.Contains(body, "<script>") || strings.Contains(body, "javascript:") {
+		t.Errorf("expected unsafe HTML to be removed, got %s", body)
+	}
+}
+
+func TestGetReadmeHTMLRequiresMarkdown(t *testing.T) {
+	svc := &mockGitHubService{content: testFileContent("README.rst", "Title\n=====\n")}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/readme?format=html",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestGetContentsFileWithNestedPath(t *testing.T) {
+	svc := &mockGitHubService{content: testFileContent("logo.png", "\x89PNG\r\n\x1a\n")}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/contents/docs/images/logo.png",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if svc.contentPath != "docs/images/logo.png" {
+		t.Errorf("expected nested path, got %q", svc.contentPath)
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected image/png, got %q", ct)
+	}
+}
+
+func TestGetContentsSniffsUnknownExtensions(t *testing.T) {
+	svc := &mockGitHubService{content: testFileContent("Makefile", "all:\n\tgo build\n")}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/contents/Makefile",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if ct := resp.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("expected sniffed text/plain, got %q", ct)
+	}
+}
+
+func TestGetContentsDirectoryListing(t *testing.T) {
+	svc := &mockGitHubService{content: &githubsvc.Content{
+		Type: githubsvc.ContentTypeDir,
+		Entries: []githubsvc.ContentEntry{
+			{Type: "file", Name: "README.md", Path: "docs/README.md", SHA: "abc", Size: 12},
+		},
+	}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/contents/docs",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+	var data ContentsListData
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if data.Count != 1 || data.Entries[0].Path != "docs/README.md" {
+		t.Errorf("unexpected listing: %+v", data)
+	}
+}
+
+func TestGetContentsRoot(t *testing.T) {
+	svc := &mockGitHubService{content: &githubsvc.Content{Type: githubsvc.ContentTypeDir}}
+	router := newTestRouter(svc)
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/contents",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if svc.contentPath != "" {
+		t.Errorf("expected root path, got %q", svc.contentPath)
+	}
+	if !strings.Contains(resp.Body.String(), `"entries":[]`) {
+		t.Errorf("expected empty entries array, got %s", resp.Body.String())
+	}
+}
+
+func TestGetContentsRejectsDotSegments(t *testing.T) {
+	for _, path := range []string{"..", "docs/../secrets", "./README.md", "docs//README.md", "docs/"} {
+		t.Run(path, func(t *testing.T) {
+			router := newTestRouter(&mockGitHubService{})
+
+			req := httptest.NewRequestWithContext(
+				t.Context(),
+				http.MethodGet,
+				"/github/repos/octocat/git-consortium/contents/"+path,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetContentsTooLarge(t *testing.T) {
+	router := newTestRouter(&mockGitHubService{err: githubsvc.ErrContentTooLarge})
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/contents/big.bin",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if !strings.Contains(resp.Body.String(), "1 MiB") {
+		t.Errorf("expected size limit detail, got %s", resp.Body.String())
+	}
+}
+
+func TestGetContentsAccessLogUsesTemplate(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	svc := &mockGitHubService{content: testFileContent("a.txt", "a")}
+	router := newTestRouterWithLogger(svc, zap.New(core))
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/github/repos/octocat/git-consortium/contents/dir/a.txt",
+		nil,
+	)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.FilterMessage("request completed").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one access log entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["path_template"] != "/github/repos/{owner}/{repo}/contents/{path}" {
+		t.Errorf("unexpected path template: %v", fields["path_template"])
+	}
+}
+
 // --- Forbidden ---
 
 func TestGetOwnerForbidden(t *testing.T) {
@@ -1,10 +1,18 @@
 package github
 
-import "github.com/janisto/huma-playground/internal/platform/pagination"
+import (
+	"time"
 
-// OwnerGetInput defines path parameters for retrieving a GitHub owner.
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/janisto/huma-playground/internal/platform/fields"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+)
+
+// OwnerGetInput defines the path and field selection for retrieving a GitHub owner or its repositories.
 type OwnerGetInput struct {
 	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat" maxLength:"39" pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	fields.Selection
 }
 
 // RepoGetInput defines path parameters for retrieving a GitHub repository.
@@ -19,3 +27,122 @@ type RepoActivityListInput struct {
 	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
 	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
 }
+
+// RepoActivityEventsInput defines path parameters and the resume point for streaming repository activity.
+type RepoActivityEventsInput struct {
+	Owner       string `path:"owner" doc:"GitHub account or organization login"                                     example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo        string `path:"repo"  doc:"Repository name"                                                          example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+	LastEventID int64  `             doc:"ID of the last event received; recent activities after it are sent first" example:"1"                                                                                      header:"Last-Event-ID" minimum:"1"`
+}
+
+// RepoReleaseListInput defines path and query parameters for listing repository releases.
+type RepoReleaseListInput struct {
+	pagination.Params
+	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+}
+
+// RepoReleaseGetInput defines path parameters for retrieving a release by tag.
+type RepoReleaseGetInput struct {
+	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+	Tag   string `path:"tag"   doc:"Release tag name"                     example:"v1.0.0"         maxLength:"255" pattern:"^[a-zA-Z0-9_+-][a-zA-Z0-9_.+-]*$"`
+}
+
+// PullRequestFilters defines query parameters for filtering and sorting pull requests. GitHub's pulls API
+// has no label or assignee filters, so unlike IssueFilters these are not offered.
+type PullRequestFilters struct {
+	State     string `query:"state"     doc:"Filter by state"            example:"open"    enum:"open,closed,all"`
+	Base      string `query:"base"      doc:"Filter by base branch name" example:"main"                                                   maxLength:"255" pattern:"^[a-zA-Z0-9_.+-]+(/[a-zA-Z0-9_.+-]+)*$"`
+	Sort      string `query:"sort"      doc:"Sort field"                 example:"created" enum:"created,updated,popularity,long-running"`
+	Direction string `query:"direction" doc:"Sort direction"             example:"desc"    enum:"asc,desc"`
+}
+
+// RepoPullRequestListInput defines path and query parameters for listing repository pull requests.
+type RepoPullRequestListInput struct {
+	pagination.Params
+	PullRequestFilters
+	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+}
+
+// IssueFilters defines query parameters for filtering and sorting issues.
+type IssueFilters struct {
+	State     string   `query:"state"     doc:"Filter by state"                                   example:"open"    enum:"open,closed,all"`
+	Labels    []string `query:"labels"    doc:"Comma-separated label names; issues must have all" example:"bug,ui"                                  maxItems:"10"`
+	Assignee  string   `query:"assignee"  doc:"Assignee login, none for unassigned, or * for any" example:"octocat"                                               maxLength:"39" pattern:"^([*]|[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*)$"`
+	Sort      string   `query:"sort"      doc:"Sort field"                                        example:"created" enum:"created,updated,comments"`
+	Direction string   `query:"direction" doc:"Sort direction"                                    example:"desc"    enum:"asc,desc"`
+}
+
+// RepoIssueListInput defines path and query parameters for listing repository issues.
+type RepoIssueListInput struct {
+	pagination.Params
+	IssueFilters
+	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+}
+
+// CommitFilters defines query parameters for filtering commit history.
+type CommitFilters struct {
+	Branch string    `query:"branch" doc:"Branch name or commit SHA to list from"             example:"main"                 maxLength:"255"  pattern:"^[a-zA-Z0-9_.+-]+(/[a-zA-Z0-9_.+-]+)*$"`
+	Path   string    `query:"path"   doc:"Only commits touching this file path"               example:"README.md"            maxLength:"1024"`
+	Author string    `query:"author" doc:"GitHub login or email address of the commit author" example:"octocat"              maxLength:"254"`
+	Since  time.Time `query:"since"  doc:"Only commits authored at or after this time"        example:"2024-01-01T00:00:00Z"`
+	Until  time.Time `query:"until"  doc:"Only commits authored at or before this time"       example:"2024-12-31T23:59:59Z"`
+}
+
+// Resolve rejects time windows that end before they start.
+func (f *CommitFilters) Resolve(_ huma.Context) []error {
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return []error{&huma.ErrorDetail{
+			Location: "query.until",
+			Message:  "until must not be before since",
+			Value:    f.Until,
+		}}
+	}
+	return nil
+}
+
+// RepoCommitListInput defines path and query parameters for listing repository commits.
+type RepoCommitListInput struct {
+	pagination.Params
+	CommitFilters
+	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+}
+
+// RepoCompareInput defines path parameters for comparing two refs.
+type RepoCompareInput struct {
+	Owner    string `path:"owner"    doc:"GitHub account or organization login"                                           example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo     string `path:"repo"     doc:"Repository name"                                                                example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+	BaseHead string `path:"basehead" doc:"Base and head refs joined by three dots; encode slashes in branch names as %2F" example:"main...feature" maxLength:"511" pattern:"^[a-zA-Z0-9_/:+-]+([.][a-zA-Z0-9_/:+-]+)*[.]{3}[a-zA-Z0-9_/:+-]+([.][a-zA-Z0-9_/:+-]+)*$"`
+}
+
+// ContentQuery defines query parameters shared by repository content endpoints.
+type ContentQuery struct {
+	Ref    string `query:"ref"    doc:"Branch, tag, or commit SHA; defaults to the default branch"            example:"main" maxLength:"255" pattern:"^[a-zA-Z0-9_.+-]+(/[a-zA-Z0-9_.+-]+)*$"`
+	Format string `query:"format" doc:"raw returns file bytes; html renders Markdown files to sanitized HTML" example:"raw"                                                                   enum:"raw,html" default:"raw"`
+}
+
+// RepoReadmeGetInput defines path and query parameters for retrieving a repository README.
+type RepoReadmeGetInput struct {
+	ContentQuery
+	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+}
+
+// RepoRootContentsGetInput defines path and query parameters for listing the repository root.
+type RepoRootContentsGetInput struct {
+	ContentQuery
+	Owner string `path:"owner" doc:"GitHub account or organization login" example:"octocat"        maxLength:"39"  pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                      example:"git-consortium" maxLength:"100" pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+}
+
+// RepoContentsGetInput defines path and query parameters for retrieving a file or directory.
+type RepoContentsGetInput struct {
+	ContentQuery
+	Owner string `path:"owner" doc:"GitHub account or organization login"         example:"octocat"        maxLength:"39"   pattern:"^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$"`
+	Repo  string `path:"repo"  doc:"Repository name"                              example:"git-consortium" maxLength:"100"  pattern:"^[a-zA-Z0-9_.-]*[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$"`
+	Path  string `path:"path"  doc:"File or directory path within the repository" example:"docs/README.md" maxLength:"1024" pattern:"^[^/]*[^/.][^/]*(/[^/]*[^/.][^/]*)*$"`
+}
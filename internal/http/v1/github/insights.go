@@ -0,0 +1,156 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/janisto/huma-playground/internal/platform/timeutil"
+	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+)
+
+const (
+	// insightsConcurrency caps the upstream calls a single insights request may have in flight.
+	insightsConcurrency   = 4
+	insightsActivityLimit = 30
+	insightsActiveWindow  = 90 * 24 * time.Hour
+)
+
+// collectInsights loads the repository, languages, tags, and recent activity concurrently. A failed
+// section is reported in RepoInsights.Errors; the request fails only when every section fails.
+func collectInsights(ctx context.Context, svc githubsvc.Service, owner, repo string) (*RepoInsights, error) {
+	var (
+		repoResult *githubsvc.Repo
+		languages  map[string]int64
+		tags       []githubsvc.Tag
+		activity   *githubsvc.ActivityPage
+
+		repoErr, languagesErr, tagsErr, activityErr error
+	)
+
+	// Sections record their own errors and return nil so one failure does not cancel the others.
+	var g errgroup.Group
+	g.SetLimit(insightsConcurrency)
+	g.Go(func() error {
+		repoResult, repoErr = svc.GetRepo(ctx, owner, repo)
+		return nil
+	})
+	g.Go(func() error {
+		languages, languagesErr = svc.ListLanguages(ctx, owner, repo)
+		return nil
+	})
+	g.Go(func() error {
+		tags, tagsErr = svc.ListTags(ctx, owner, repo)
+		return nil
+	})
+	g.Go(func() error {
+		activity, activityErr = svc.ListActivity(ctx, owner, repo, insightsActivityLimit, "")
+		return nil
+	})
+	_ = g.Wait()
+
+	if repoErr != nil && languagesErr != nil && tagsErr != nil && activityErr != nil {
+		return nil, mapServiceError(ctx, "get_repository_insights", repoErr)
+	}
+
+	now := time.Now()
+	result := &RepoInsights{Errors: []InsightError{}}
+	if repoErr != nil {
+		result.Errors = append(result.Errors, insightError(ctx, "repo", "get_repository", repoErr))
+	} else {
+		httpRepo := toHTTPRepo(repoResult)
+		result.Repo = &httpRepo
+		result.Health = toRepoHealth(repoResult, now)
+	}
+	if languagesErr != nil {
+		result.Errors = append(result.Errors,
+			insightError(ctx, "languages", "get_repository_languages", languagesErr))
+	} else {
+		result.Languages = toLanguageBreakdown(languages)
+	}
+	if tagsErr != nil {
+		result.Errors = append(result.Errors, insightError(ctx, "tags", "list_repository_tags", tagsErr))
+	} else {
+		result.Tags = toTagSummary(tags)
+	}
+	if activityErr != nil {
+		result.Errors = append(result.Errors,
+			insightError(ctx, "activity", "list_repository_activity", activityErr))
+	} else {
+		result.Activity = toActivitySummary(activity.Activities, now)
+	}
+	return result, nil
+}
+
+// insightError maps a section failure exactly as the standalone endpoint would and reports the outcome.
+func insightError(ctx context.Context, section, operation string, err error) InsightError {
+	mapped := mapServiceError(ctx, operation, err)
+	insight := InsightError{Section: section, Status: http.StatusBadGateway, Detail: mapped.Error()}
+	if statusErr, ok := errors.AsType[huma.StatusError](mapped); ok {
+		insight.Status = statusErr.GetStatus()
+	}
+	return insight
+}
+
+func toRepoHealth(r *githubsvc.Repo, now time.Time) *RepoHealth {
+	return &RepoHealth{
+		Archived:        r.Archived,
+		Disabled:        r.Disabled,
+		HasLicense:      r.License != "",
+		HasDescription:  r.Description != "",
+		HasTopics:       len(r.Topics) > 0,
+		OpenIssues:      r.OpenIssues,
+		DaysSinceUpdate: max(int(now.Sub(r.UpdatedAt).Hours()/24), 0),
+	}
+}
+
+func toLanguageBreakdown(languages map[string]int64) *LanguageBreakdown {
+	var total int64
+	for _, bytes := range languages {
+		total += bytes
+	}
+	sorted := toHTTPLanguages(languages)
+	shares := make([]LanguageShare, len(sorted))
+	for i, l := range sorted {
+		shares[i] = LanguageShare{Name: l.Name, Bytes: l.Bytes}
+		if total > 0 {
+			shares[i].Percent = math.Round(float64(l.Bytes)*1000/float64(total)) / 10
+		}
+	}
+	return &LanguageBreakdown{TotalBytes: total, Languages: shares}
+}
+
+func toTagSummary(tags []githubsvc.Tag) *TagSummary {
+	summary := &TagSummary{Count: len(tags)}
+	if len(tags) > 0 {
+		latest := toHTTPTag(&tags[0])
+		summary.Latest = &latest
+	}
+	return summary
+}
+
+func toActivitySummary(activities []githubsvc.Activity, now time.Time) *ActivitySummary {
+	summary := &ActivitySummary{Events: len(activities), Types: map[string]int{}}
+	actors := make(map[string]struct{}, len(activities))
+	var last time.Time
+	for _, a := range activities {
+		summary.Types[a.ActivityType]++
+		if a.Actor != "" {
+			actors[a.Actor] = struct{}{}
+		}
+		if a.Timestamp.After(last) {
+			last = a.Timestamp
+		}
+	}
+	summary.Contributors = len(actors)
+	if !last.IsZero() {
+		summary.LastEventAt = &timeutil.Time{Time: last}
+		summary.Active = now.Sub(last) <= insightsActiveWindow
+	}
+	return summary
+}
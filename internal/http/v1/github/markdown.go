@@ -0,0 +1,32 @@
+package github
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdownRenderer renders GitHub Flavored Markdown. Goldmark's default renderer
+// omits raw HTML and drops unsafe link destinations such as javascript: URLs,
+// so the output is safe to embed without a separate sanitizer.
+var markdownRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+func renderMarkdown(source []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert(source, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return true
+	default:
+		return false
+	}
+}
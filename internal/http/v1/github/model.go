@@ -68,3 +68,157 @@ type Language struct {
 	Name  string `json:"name"  doc:"Language name" example:"Ruby"`
 	Bytes int64  `json:"bytes" doc:"Bytes of code" example:"6789"`
 }
+
+// Release represents a repository release.
+type Release struct {
+	ID          int64          `json:"id"                    doc:"Release ID"                               example:"1"`
+	TagName     string         `json:"tagName"               doc:"Tag the release points to"                example:"v1.0.0"`
+	Name        string         `json:"name"                  doc:"Release title"                            example:"v1.0.0"`
+	Body        string         `json:"body"                  doc:"Release notes in Markdown"`
+	HTMLURL     string         `json:"htmlUrl"               doc:"GitHub release URL"                       example:"https://github.com/octocat/git-consortium/releases/tag/v1.0.0"`
+	Author      string         `json:"author"                doc:"Release author username"                  example:"octocat"`
+	Draft       bool           `json:"draft"                 doc:"Whether the release is a draft"           example:"false"`
+	Prerelease  bool           `json:"prerelease"            doc:"Whether the release is a prerelease"      example:"false"`
+	CreatedAt   timeutil.Time  `json:"createdAt"             doc:"Creation timestamp"                       example:"2024-01-15T10:30:00.000Z"`
+	PublishedAt *timeutil.Time `json:"publishedAt,omitempty" doc:"Publication timestamp; absent for drafts" example:"2024-01-15T11:00:00.000Z"`
+	Assets      []ReleaseAsset `json:"assets"                doc:"Files attached to the release"`
+}
+
+// ReleaseAsset represents a downloadable file attached to a release.
+type ReleaseAsset struct {
+	Name          string `json:"name"          doc:"Asset file name"      example:"app-linux-amd64.tar.gz"`
+	ContentType   string `json:"contentType"   doc:"Asset media type"     example:"application/gzip"`
+	Size          int64  `json:"size"          doc:"Asset size in bytes"  example:"1048576"`
+	DownloadCount int64  `json:"downloadCount" doc:"Number of downloads"  example:"42"`
+	DownloadURL   string `json:"downloadUrl"   doc:"Browser download URL" example:"https://github.com/octocat/git-consortium/releases/download/v1.0.0/app-linux-amd64.tar.gz"`
+}
+
+// PullRequest represents a repository pull request.
+type PullRequest struct {
+	Number    int            `json:"number"             doc:"Pull request number"                 example:"1347"`
+	Title     string         `json:"title"              doc:"Pull request title"                  example:"Amazing new feature"`
+	State     string         `json:"state"              doc:"Pull request state"                  example:"open"                                                enum:"open,closed"`
+	Draft     bool           `json:"draft"              doc:"Whether the pull request is a draft" example:"false"`
+	Author    string         `json:"author"             doc:"Author username"                     example:"octocat"`
+	HTMLURL   string         `json:"htmlUrl"            doc:"GitHub pull request URL"             example:"https://github.com/octocat/git-consortium/pull/1347"`
+	BaseRef   string         `json:"baseRef"            doc:"Branch the changes merge into"       example:"main"`
+	HeadRef   string         `json:"headRef"            doc:"Branch containing the changes"       example:"new-topic"`
+	Labels    []string       `json:"labels"             doc:"Label names"`
+	Assignees []string       `json:"assignees"          doc:"Assignee usernames"`
+	CreatedAt timeutil.Time  `json:"createdAt"          doc:"Creation timestamp"                  example:"2024-01-15T10:30:00.000Z"`
+	UpdatedAt timeutil.Time  `json:"updatedAt"          doc:"Last update timestamp"               example:"2024-01-16T10:30:00.000Z"`
+	ClosedAt  *timeutil.Time `json:"closedAt,omitempty" doc:"Close timestamp; absent while open"  example:"2024-01-17T10:30:00.000Z"`
+	MergedAt  *timeutil.Time `json:"mergedAt,omitempty" doc:"Merge timestamp; absent if unmerged" example:"2024-01-17T10:30:00.000Z"`
+}
+
+// Issue represents a repository issue.
+type Issue struct {
+	Number    int            `json:"number"             doc:"Issue number"                       example:"1"`
+	Title     string         `json:"title"              doc:"Issue title"                        example:"Found a bug"`
+	State     string         `json:"state"              doc:"Issue state"                        example:"open"                                               enum:"open,closed"`
+	Author    string         `json:"author"             doc:"Author username"                    example:"octocat"`
+	HTMLURL   string         `json:"htmlUrl"            doc:"GitHub issue URL"                   example:"https://github.com/octocat/git-consortium/issues/1"`
+	Labels    []string       `json:"labels"             doc:"Label names"`
+	Assignees []string       `json:"assignees"          doc:"Assignee usernames"`
+	Comments  int            `json:"comments"           doc:"Comment count"                      example:"3"`
+	CreatedAt timeutil.Time  `json:"createdAt"          doc:"Creation timestamp"                 example:"2024-01-15T10:30:00.000Z"`
+	UpdatedAt timeutil.Time  `json:"updatedAt"          doc:"Last update timestamp"              example:"2024-01-16T10:30:00.000Z"`
+	ClosedAt  *timeutil.Time `json:"closedAt,omitempty" doc:"Close timestamp; absent while open" example:"2024-01-17T10:30:00.000Z"`
+}
+
+// Commit summarizes a repository commit.
+type Commit struct {
+	SHA         string        `json:"sha"         doc:"Commit SHA"                                  example:"6dcb09b5b57875f334f61aebed695e2e4193db5e"`
+	Message     string        `json:"message"     doc:"Commit message"                              example:"Fix all the bugs"`
+	AuthorName  string        `json:"authorName"  doc:"Author name recorded in the commit"          example:"Monalisa Octocat"`
+	AuthorLogin string        `json:"authorLogin" doc:"Author's GitHub username; empty if unlinked" example:"octocat"`
+	AuthoredAt  timeutil.Time `json:"authoredAt"  doc:"Authoring timestamp"                         example:"2024-01-15T10:30:00.000Z"`
+	HTMLURL     string        `json:"htmlUrl"     doc:"GitHub commit URL"                           example:"https://github.com/octocat/git-consortium/commit/6dcb09b5b57875f334f61aebed695e2e4193db5e"`
+}
+
+// FileChange summarizes the changes to one file in a comparison.
+type FileChange struct {
+	Filename         string `json:"filename"                   doc:"File path"                            example:"src/main.go"`
+	PreviousFilename string `json:"previousFilename,omitempty" doc:"Previous file path for renamed files" example:"src/app.go"`
+	Status           string `json:"status"                     doc:"Change status"                        example:"modified"`
+	Additions        int    `json:"additions"                  doc:"Lines added"                          example:"3"`
+	Deletions        int    `json:"deletions"                  doc:"Lines removed"                        example:"1"`
+	Changes          int    `json:"changes"                    doc:"Total lines changed"                  example:"4"`
+}
+
+// Comparison describes how a head ref differs from a base ref.
+type Comparison struct {
+	Status       string       `json:"status"       doc:"Relationship of head to base"             example:"ahead"                                                            enum:"diverged,ahead,behind,identical"`
+	AheadBy      int          `json:"aheadBy"      doc:"Commits head has that base lacks"         example:"2"`
+	BehindBy     int          `json:"behindBy"     doc:"Commits base has that head lacks"         example:"0"`
+	TotalCommits int          `json:"totalCommits" doc:"Total commits in the comparison"          example:"2"`
+	HTMLURL      string       `json:"htmlUrl"      doc:"GitHub comparison URL"                    example:"https://github.com/octocat/git-consortium/compare/main...feature"`
+	Commits      []Commit     `json:"commits"      doc:"Commits reachable from head but not base"`
+	Files        []FileChange `json:"files"        doc:"Changed files with line statistics"`
+}
+
+// ContentEntry describes one item in a repository directory listing.
+type ContentEntry struct {
+	Type string `json:"type" doc:"Entry type"                       example:"file"                                     enum:"file,dir,symlink,submodule"`
+	Name string `json:"name" doc:"Entry name"                       example:"README.md"`
+	Path string `json:"path" doc:"Path from the repository root"    example:"docs/README.md"`
+	SHA  string `json:"sha"  doc:"Git object SHA"                   example:"3d21ec53a331a6f037a91c368710b99387d012c1"`
+	Size int64  `json:"size" doc:"Size in bytes; 0 for directories" example:"5362"`
+}
+
+// RepoInsights aggregates several repository views into one response. Each section is absent when its
+// upstream call failed; the failure is then described in Errors.
+type RepoInsights struct {
+	Repo      *Repo              `json:"repo,omitempty"      doc:"Repository details"`
+	Health    *RepoHealth        `json:"health,omitempty"    doc:"Health indicators derived from repository details"`
+	Languages *LanguageBreakdown `json:"languages,omitempty" doc:"Language usage as share of total bytes"`
+	Tags      *TagSummary        `json:"tags,omitempty"      doc:"Tag summary"`
+	Activity  *ActivitySummary   `json:"activity,omitempty"  doc:"Summary of recent activity events"`
+	Errors    []InsightError     `json:"errors"              doc:"Sections that could not be loaded"`
+}
+
+// RepoHealth holds maintenance indicators for a repository.
+type RepoHealth struct {
+	Archived        bool `json:"archived"        doc:"Whether the repository is archived"               example:"false"`
+	Disabled        bool `json:"disabled"        doc:"Whether the repository is disabled"               example:"false"`
+	HasLicense      bool `json:"hasLicense"      doc:"Whether a license is detected"                    example:"true"`
+	HasDescription  bool `json:"hasDescription"  doc:"Whether the repository has a description"         example:"true"`
+	HasTopics       bool `json:"hasTopics"       doc:"Whether the repository has topics"                example:"true"`
+	OpenIssues      int  `json:"openIssues"      doc:"Open issue count"                                 example:"0"`
+	DaysSinceUpdate int  `json:"daysSinceUpdate" doc:"Whole days since the repository was last updated" example:"12"`
+}
+
+// LanguageBreakdown lists languages with their share of the repository's code.
+type LanguageBreakdown struct {
+	TotalBytes int64           `json:"totalBytes" doc:"Bytes of code across all languages"        example:"7890"`
+	Languages  []LanguageShare `json:"languages"  doc:"Languages ordered by bytes, largest first"`
+}
+
+// LanguageShare is a language's byte count and percentage of the total.
+type LanguageShare struct {
+	Name    string  `json:"name"    doc:"Language name"                                      example:"Ruby"`
+	Bytes   int64   `json:"bytes"   doc:"Bytes of code"                                      example:"6789"`
+	Percent float64 `json:"percent" doc:"Share of total bytes, rounded to one decimal place" example:"86.0"`
+}
+
+// TagSummary reports how many tags were found and which one is latest.
+type TagSummary struct {
+	Count  int  `json:"count"            doc:"Number of tags returned, up to 30"                                   example:"3"`
+	Latest *Tag `json:"latest,omitempty" doc:"Most recent tag as ordered by GitHub; absent when there are no tags"`
+}
+
+// ActivitySummary condenses the most recent repository activity events.
+type ActivitySummary struct {
+	Events       int            `json:"events"                doc:"Number of recent events sampled, up to 30"     example:"30"`
+	Contributors int            `json:"contributors"          doc:"Distinct actors among the sampled events"      example:"4"`
+	Active       bool           `json:"active"                doc:"Whether an event happened in the last 90 days" example:"true"`
+	LastEventAt  *timeutil.Time `json:"lastEventAt,omitempty" doc:"Timestamp of the newest event"                 example:"2024-01-15T10:30:00.000Z"`
+	Types        map[string]int `json:"types"                 doc:"Event counts by activity type"`
+}
+
+// InsightError describes why an insights section is missing.
+type InsightError struct {
+	Section string `json:"section" doc:"Section that failed"             example:"languages"      enum:"repo,languages,tags,activity"`
+	Status  int    `json:"status"  doc:"HTTP status the section maps to" example:"502"`
+	Detail  string `json:"detail"  doc:"Human-readable explanation"      example:"upstream error"`
+}
@@ -53,3 +53,79 @@ type RepoTagsListData struct {
 type RepoTagsListOutput struct {
 	Body RepoTagsListData
 }
+
+// RepoReleaseListData is the response body for listing repository releases.
+type RepoReleaseListData struct {
+	Releases []Release `json:"releases" doc:"List of releases"`
+	Count    int       `json:"count"    doc:"Number of releases returned" example:"1"`
+}
+
+// RepoReleaseListOutput is the response wrapper for GET /github/repos/{owner}/{repo}/releases.
+type RepoReleaseListOutput struct {
+	Link string `header:"Link" doc:"RFC 8288 pagination links"`
+	Body RepoReleaseListData
+}
+
+// RepoReleaseGetOutput is the response wrapper for single-release GitHub endpoints.
+type RepoReleaseGetOutput struct {
+	Body Release
+}
+
+// RepoPullRequestListData is the response body for listing repository pull requests.
+type RepoPullRequestListData struct {
+	PullRequests []PullRequest `json:"pullRequests" doc:"List of pull requests"`
+	Count        int           `json:"count"        doc:"Number of pull requests returned" example:"1"`
+}
+
+// RepoPullRequestListOutput is the response wrapper for GET /github/repos/{owner}/{repo}/pulls.
+type RepoPullRequestListOutput struct {
+	Link string `header:"Link" doc:"RFC 8288 pagination links"`
+	Body RepoPullRequestListData
+}
+
+// RepoIssueListData is the response body for listing repository issues.
+type RepoIssueListData struct {
+	Issues []Issue `json:"issues" doc:"List of issues"`
+	Count  int     `json:"count"  doc:"Number of issues returned" example:"1"`
+}
+
+// RepoIssueListOutput is the response wrapper for GET /github/repos/{owner}/{repo}/issues.
+type RepoIssueListOutput struct {
+	Link string `header:"Link" doc:"RFC 8288 pagination links"`
+	Body RepoIssueListData
+}
+
+// RepoCommitListData is the response body for listing repository commits.
+type RepoCommitListData struct {
+	Commits []Commit `json:"commits" doc:"List of commits"`
+	Count   int      `json:"count"   doc:"Number of commits returned" example:"1"`
+}
+
+// RepoCommitListOutput is the response wrapper for GET /github/repos/{owner}/{repo}/commits.
+type RepoCommitListOutput struct {
+	Link string `header:"Link" doc:"RFC 8288 pagination links"`
+	Body RepoCommitListData
+}
+
+// RepoCompareGetOutput is the response wrapper for GET /github/repos/{owner}/{repo}/compare/{basehead}.
+type RepoCompareGetOutput struct {
+	Body Comparison
+}
+
+// ContentsListData is the response body for a repository directory listing.
+type ContentsListData struct {
+	Entries []ContentEntry `json:"entries" doc:"Directory entries"`
+	Count   int            `json:"count"   doc:"Number of entries returned" example:"1"`
+}
+
+// RepoContentsGetOutput is the response wrapper for repository content endpoints. Body holds
+// raw file bytes, rendered HTML, or a ContentsListData directory listing.
+type RepoContentsGetOutput struct {
+	ContentType string `header:"Content-Type" doc:"Media type of the returned content"`
+	Body        any
+}
+
+// RepoInsightsGetOutput is the response wrapper for GET /github/repos/{owner}/{repo}/insights.
+type RepoInsightsGetOutput struct {
+	Body RepoInsights
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// wildcardAPI registers operations whose trailing path parameter spans multiple
+// segments. Chi has no {name...} syntax, so the route is mounted with a trailing
+// catch-all while the OpenAPI document keeps the named parameter.
+type wildcardAPI struct {
+	huma.API
+	param string
+}
+
+func (a wildcardAPI) Adapter() huma.Adapter {
+	return wildcardAdapter{Adapter: a.API.Adapter(), param: a.param}
+}
+
+type wildcardAdapter struct {
+	huma.Adapter
+	param string
+}
+
+func (a wildcardAdapter) Handle(op *huma.Operation, handler func(huma.Context)) {
+	route := *op
+	route.Path = strings.TrimSuffix(op.Path, "{"+a.param+"}") + "*"
+	a.Adapter.Handle(&route, func(ctx huma.Context) {
+		handler(wildcardContext{humaContext: ctx, op: op, param: a.param})
+	})
+}
+
+// humaContext lets wildcardContext embed huma.Context without the embedded
+// field name colliding with the interface's Context method.
+type humaContext = huma.Context
+
+type wildcardContext struct {
+	humaContext
+	op    *huma.Operation
+	param string
+}
+
+func (c wildcardContext) Operation() *huma.Operation {
+	return c.op
+}
+
+func (c wildcardContext) Param(name string) string {
+	if name == c.param {
+		return c.humaContext.Param("*")
+	}
+	return c.humaContext.Param(name)
+}
+
+// Unwrap lets adapter helpers such as humachi.Unwrap reach the router context.
+func (c wildcardContext) Unwrap() huma.Context {
+	return c.humaContext
+}
@@ -2,82 +2,357 @@ package items
 
 import (
 	"context"
+	"errors"
+	"maps"
 	"net/http"
 	"net/url"
+	"reflect"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
+	obs "github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
 
+	"github.com/janisto/huma-playground/internal/platform/auth"
+	"github.com/janisto/huma-playground/internal/platform/fields"
 	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/stream"
+	"github.com/janisto/huma-playground/internal/platform/timeutil"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
 )
 
-const cursorType = "item"
+const (
+	cursorType = "item"
 
-// Register wires item routes into the provided API router.
-func Register(api huma.API, prefix string) {
+	// streamBatchSize is how many items a streamed list reads from the store per page.
+	streamBatchSize = 100
+)
+
+// Register wires item routes into the provided API router. Anyone may read the catalog; changing it
+// requires the admin role.
+func Register(api huma.API, prefix string, store itemsvc.Store, cursors *pagination.Codec) {
 	huma.Register(api, huma.Operation{
 		OperationID: "list-items",
 		Method:      http.MethodGet,
 		Path:        "/items",
 		Summary:     "List items with cursor-based pagination",
-		Description: "Returns a paginated list of items. Use the cursor from the Link header to navigate between pages.",
-		Tags:        []string{"Items"},
+		Description: "Returns a filtered, sorted, paginated list of items. " +
+			"Use the cursor from the Link header to navigate between pages.",
+		Tags:     []string{"Items"},
+		Metadata: map[string]any{stream.RecordKey: reflect.TypeFor[Item](), fields.PathKey: "items"},
 		Errors: []int{
 			http.StatusBadRequest,
 			http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
 		},
-	}, func(_ context.Context, input *ItemsListInput) (*ItemsListOutput, error) {
-		cursor, err := pagination.DecodeCursor(input.Cursor)
+	}, func(ctx context.Context, input *ItemsListInput) (*ItemsListOutput, error) {
+		filter := input.query()
+		cursor, err := cursors.Decode(input.Cursor, filter)
 		if err != nil {
-			return nil, huma.Error400BadRequest("invalid cursor format")
+			return nil, huma.Error400BadRequest(err.Error())
 		}
 
 		if input.Cursor != "" && cursor.Type != cursorType {
 			return nil, huma.Error400BadRequest("cursor type mismatch")
 		}
 
-		filtered := filterItems(mockItems, input.Category)
+		params := input.listParams()
+		params.Cursor = cursor
+		params.CursorType = cursorType
+		params.Codec = cursors
+		params.BaseURL = prefix + "/items"
+		params.Query = filter
 
-		if cursor.Value != "" && findItemIndex(filtered, cursor.Value) == -1 {
-			return nil, huma.Error400BadRequest("cursor references unknown item")
+		if w, ok := stream.FromContext(ctx); ok {
+			if cursor.Before {
+				return nil, huma.Error400BadRequest("a streamed list can only start at a next cursor")
+			}
+			return nil, streamItems(ctx, w, store, params, input.Cursor, input.DisplayParams, input.Selection)
 		}
 
-		query := url.Values{}
-		if input.Category != "" {
-			query.Set("category", input.Category)
+		params.Limit = input.DefaultLimit()
+		result, err := store.List(ctx, params)
+		if err != nil {
+			return nil, mapServiceError(ctx, "list", err)
+		}
+		items := make([]Item, len(result.Items))
+		tag, localized := input.locale()
+		for i := range result.Items {
+			items[i] = toHTTPItem(&result.Items[i])
+			if localized {
+				items[i].PriceDisplay = items[i].Price.Format(tag)
+			}
+		}
+
+		page := result.Page
+		if input.Total {
+			page.Total = &result.Total
+		}
+		// Links also carry the display parameters, which the cursors are not bound to, so a client may
+		// change them between pages.
+		linkQuery := maps.Clone(filter)
+		if input.Total {
+			linkQuery.Set("total", "true")
+		}
+		input.SetQuery(linkQuery)
+		linkQuery.Set("limit", strconv.Itoa(params.Limit))
+		out := &ItemsListOutput{
+			Link:       result.Links.Header(params.BaseURL, linkQuery),
+			Pagination: page.Header(),
+			Vary:       []string{"Accept-Language"},
+			Body:       ListData{Items: items, Total: page.Total},
 		}
+		return out, nil
+	})
 
-		result := pagination.Paginate(
-			filtered,
-			cursor,
-			input.DefaultLimit(),
-			cursorType,
-			func(item Item) string { return item.ID },
-			prefix+"/items",
-			query,
-		)
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-item",
+		Method:        http.MethodPost,
+		Path:          "/items",
+		Summary:       "Create item",
+		Description:   "Adds an item to the catalog. Requires the admin role.",
+		Tags:          []string{"Items"},
+		DefaultStatus: http.StatusCreated,
+		Security:      auth.RequireRole(auth.RoleAdmin),
+		Errors: []int{
+			http.StatusBadRequest,
+			http.StatusUnauthorized,
+			http.StatusForbidden,
+			http.StatusRequestTimeout,
+			http.StatusRequestEntityTooLarge,
+			http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
+		},
+	}, func(ctx context.Context, input *ItemCreateInput) (*ItemCreateOutput, error) {
+		user := auth.UserFromContext(ctx)
 
-		return &ItemsListOutput{
-			Link: result.LinkHeader,
-			Body: ListData{
-				Items: result.Items,
-				Total: result.Total,
-			},
+		item, err := store.Create(ctx, user.UID, itemsvc.CreateParams{
+			Name:        input.Body.Name,
+			Category:    input.Body.Category,
+			Price:       input.Body.Price,
+			InStock:     input.Body.InStock,
+			Description: input.Body.Description,
+		})
+		if err != nil {
+			return nil, mapServiceError(ctx, "create", err)
+		}
+		return &ItemCreateOutput{
+			Location: prefix + "/items/" + item.ID,
+			Body:     toHTTPItem(item),
 		}, nil
 	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-item",
+		Method:      http.MethodGet,
+		Path:        "/items/{id}",
+		Summary:     "Get item",
+		Description: "Retrieves a single catalog item.",
+		Tags:        []string{"Items"},
+		Metadata:    map[string]any{fields.PathKey: ""},
+		Errors: []int{
+			http.StatusNotFound,
+			http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
+		},
+	}, func(ctx context.Context, input *ItemGetInput) (*ItemGetOutput, error) {
+		item, err := store.Get(ctx, input.ID)
+		if err != nil {
+			return nil, mapServiceError(ctx, "get", err)
+		}
+		out := &ItemGetOutput{Vary: []string{"Accept-Language"}, Body: toHTTPItem(item)}
+		if tag, ok := input.locale(); ok {
+			out.Body.PriceDisplay = item.Price.Format(tag)
+		}
+		return out, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-item",
+		Method:      http.MethodPatch,
+		Path:        "/items/{id}",
+		Summary:     "Update item",
+		Description: "Updates fields on a catalog item. Only provided fields are updated. Requires the admin role.",
+		Tags:        []string{"Items"},
+		Security:    auth.RequireRole(auth.RoleAdmin),
+		Errors: []int{
+			http.StatusBadRequest,
+			http.StatusUnauthorized,
+			http.StatusForbidden,
+			http.StatusNotFound,
+			http.StatusRequestTimeout,
+			http.StatusRequestEntityTooLarge,
+			http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
+		},
+	}, func(ctx context.Context, input *ItemUpdateInput) (*ItemUpdateOutput, error) {
+		user := auth.UserFromContext(ctx)
+		if !hasItemUpdateFields(input) {
+			return nil, huma.Error422UnprocessableEntity("at least one field must be provided")
+		}
+
+		item, err := store.Update(ctx, user.UID, input.ID, itemsvc.UpdateParams{
+			Name:        input.Body.Name,
+			Category:    input.Body.Category,
+			Price:       input.Body.Price,
+			InStock:     input.Body.InStock,
+			Description: input.Body.Description,
+		})
+		if err != nil {
+			return nil, mapServiceError(ctx, "update", err)
+		}
+		return &ItemUpdateOutput{Body: toHTTPItem(item)}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-item",
+		Method:        http.MethodDelete,
+		Path:          "/items/{id}",
+		Summary:       "Delete item",
+		Description:   "Permanently removes an item from the catalog. Requires the admin role.",
+		Tags:          []string{"Items"},
+		DefaultStatus: http.StatusNoContent,
+		Security:      auth.RequireRole(auth.RoleAdmin),
+		Errors: []int{
+			http.StatusUnauthorized,
+			http.StatusForbidden,
+			http.StatusNotFound,
+			http.StatusUnprocessableEntity,
+			http.StatusServiceUnavailable,
+		},
+	}, func(ctx context.Context, input *ItemDeleteInput) (*struct{}, error) {
+		user := auth.UserFromContext(ctx)
+
+		if err := store.Delete(ctx, user.UID, input.ID); err != nil {
+			return nil, mapServiceError(ctx, "delete", err)
+		}
+		return nil, nil
+	})
+}
+
+func hasItemUpdateFields(input *ItemUpdateInput) bool {
+	return input.Body.Name != nil ||
+		input.Body.Category != nil ||
+		input.Body.Price != nil ||
+		input.Body.InStock != nil ||
+		input.Body.Description != nil
 }
 
-func filterItems(items []Item, category string) []Item {
-	if category == "" {
-		return items
+func mapServiceError(ctx context.Context, operation string, err error) error {
+	switch {
+	case errors.Is(err, itemsvc.ErrNotFound):
+		return huma.Error404NotFound("item not found")
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return huma.Error400BadRequest(pagination.ErrInvalidCursor.Error())
+	case errors.Is(err, itemsvc.ErrUnavailable), errors.Is(err, context.DeadlineExceeded):
+		obs.Logger(ctx).Warn("item store unavailable",
+			zap.String("operation", operation), zap.Error(err))
+		return huma.Error503ServiceUnavailable("item service temporarily unavailable")
+	default:
+		obs.Logger(ctx).Error("item operation failed",
+			zap.String("operation", operation), zap.Error(err))
+		return huma.Error500InternalServerError("internal error")
+	}
+}
+
+func toHTTPItem(item *itemsvc.Item) Item {
+	return Item{
+		ID:          item.ID,
+		Name:        item.Name,
+		Category:    item.Category,
+		Price:       item.Price,
+		PriceMinor:  item.Price.Minor(),
+		Currency:    item.Price.Currency(),
+		InStock:     item.InStock,
+		CreatedAt:   timeutil.Time{Time: item.CreatedAt},
+		UpdatedAt:   timeutil.Time{Time: item.UpdatedAt},
+		Description: item.Description,
 	}
-	return slices.DeleteFunc(slices.Clone(items), func(item Item) bool {
-		return item.Category != category
-	})
 }
 
-func findItemIndex(items []Item, id string) int {
-	return slices.IndexFunc(items, func(item Item) bool {
-		return item.ID == id
+// query returns the filters in canonical form, to bind to cursors and preserve in Link headers.
+func (f ItemFilters) query() url.Values {
+	query := url.Values{}
+	if len(f.Category) > 0 {
+		query.Set("category", strings.Join(slices.Sorted(slices.Values(f.Category)), ","))
+	}
+	setIfNotEmpty(query, "inStock", f.InStock)
+	setIfNotEmpty(query, "currency", f.Currency)
+	if f.MinPriceMinor > 0 {
+		query.Set("minPriceMinor", strconv.FormatInt(f.MinPriceMinor, 10))
+	}
+	if f.hasMaxPrice {
+		query.Set("maxPriceMinor", strconv.FormatInt(f.MaxPriceMinor, 10))
+	}
+	setIfNotEmpty(query, "q", f.Q)
+	setIfNotEmpty(query, "sort", f.Sort)
+	setIfNotEmpty(query, "direction", f.Direction)
+	return query
+}
+
+// listParams converts the filters to store list parameters.
+func (f ItemFilters) listParams() itemsvc.ListParams {
+	params := itemsvc.ListParams{
+		Categories:    f.Category,
+		Currency:      f.Currency,
+		MinPriceMinor: f.MinPriceMinor,
+		Q:             f.Q,
+		Sort:          f.Sort,
+		Desc:          f.Direction == "desc",
+		Total:         f.Total,
+	}
+	if f.InStock != "" {
+		inStock := f.InStock == "true"
+		params.InStock = &inStock
+	}
+	if f.hasMaxPrice {
+		maxPrice := f.MaxPriceMinor
+		params.MaxPriceMinor = &maxPrice
+	}
+	return params
+}
+
+// streamItems writes the selected fields of every item from cursor on, reading the store one page of
+// streamBatchSize at a time.
+func streamItems(
+	ctx context.Context,
+	w *stream.Writer,
+	store itemsvc.Store,
+	params itemsvc.ListParams,
+	cursor string,
+	display DisplayParams,
+	selection fields.Selection,
+) error {
+	tag, localized := display.locale()
+	// A stream has no total, and counting on every page would reread the matches each time.
+	params.Limit, params.Total = streamBatchSize, false
+	return stream.Pages(ctx, w, cursor, func(ctx context.Context, cursor string) ([]any, string, error) {
+		var err error
+		if params.Cursor, err = params.Codec.Decode(cursor, params.Query); err != nil {
+			return nil, "", huma.Error400BadRequest(err.Error())
+		}
+		result, err := store.List(ctx, params)
+		if err != nil {
+			return nil, "", mapServiceError(ctx, "list", err)
+		}
+		records := make([]any, len(result.Items))
+		for i := range result.Items {
+			item := toHTTPItem(&result.Items[i])
+			if localized {
+				item.PriceDisplay = item.Price.Format(tag)
+			}
+			records[i] = selection.Record(item)
+		}
+		return records, result.NextCursor, nil
 	})
 }
+
+func setIfNotEmpty(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
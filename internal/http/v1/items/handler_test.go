@@ -1,9 +1,15 @@
 package items
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
 	"strings"
 	"testing"
 
@@ -15,18 +21,50 @@ import (
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/janisto/huma-observability/v2"
 
+	"github.com/janisto/huma-playground/internal/platform/auth"
+	"github.com/janisto/huma-playground/internal/platform/fields"
+	"github.com/janisto/huma-playground/internal/platform/money"
 	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/stream"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
+	"github.com/janisto/huma-playground/internal/testutil"
 )
 
+// testCursors signs the cursors the test router accepts.
+var testCursors = testutil.Cursors()
+
+// tokenVerifier accepts "admin-token" as an admin and "user-token" as a user without roles.
+type tokenVerifier struct{}
+
+func (tokenVerifier) Verify(_ context.Context, token string) (*auth.FirebaseUser, error) {
+	switch token {
+	case "admin-token":
+		return &auth.FirebaseUser{UID: "admin-1", Roles: []string{auth.RoleAdmin}}, nil
+	case "user-token":
+		return &auth.FirebaseUser{UID: "user-1"}, nil
+	default:
+		return nil, auth.ErrInvalidToken
+	}
+}
+
 func newTestRouter() chi.Router {
+	return newTestRouterWithStore(itemsvc.NewMemoryStore(itemsvc.SampleItems()...))
+}
+
+func newTestRouterWithStore(store itemsvc.Store) chi.Router {
 	router := chi.NewRouter()
 	router.Use(
 		chimiddleware.ClientIPFromRemoteAddr,
 	)
-	api := humachi.New(router, huma.DefaultConfig("ItemsTest", "test"))
+	config := huma.DefaultConfig("ItemsTest", "test")
+	config.Transformers = append(config.Transformers, fields.Transform)
+	api := humachi.New(router, config)
+	fields.Register(api)
 	api.UseMiddleware(obs.RequestContext(obs.RequestContextConfig{}))
 	api.UseMiddleware(obs.AccessLogger(obs.AccessLoggerConfig{}))
-	Register(api, "")
+	api.UseMiddleware(stream.Middleware())
+	api.UseMiddleware(auth.NewAuthMiddleware(api, tokenVerifier{}))
+	Register(api, "", store, testCursors)
 	return router
 }
 
@@ -50,8 +88,8 @@ func TestListFirstPage(t *testing.T) {
 	if len(data.Items) != 20 {
 		t.Errorf("expected 20 items (default limit), got %d", len(data.Items))
 	}
-	if data.Total != 30 {
-		t.Errorf("expected total 30, got %d", data.Total)
+	if data.Total != nil {
+		t.Errorf("expected total to be omitted unless requested, got %d", *data.Total)
 	}
 	if data.Items[0].ID != "item-001" {
 		t.Errorf("expected first item item-001, got %s", data.Items[0].ID)
@@ -96,7 +134,7 @@ func TestListWithLimit(t *testing.T) {
 func TestListMiddlePage(t *testing.T) {
 	router := newTestRouter()
 
-	cursor := pagination.Cursor{Type: "item", Value: "item-010"}.Encode()
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-010"}, nil)
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor+"&limit=5", nil)
 	req.Header.Set(chimiddleware.RequestIDHeader, "items-middle-page")
 	resp := httptest.NewRecorder()
@@ -130,7 +168,7 @@ func TestListMiddlePage(t *testing.T) {
 func TestListLastPage(t *testing.T) {
 	router := newTestRouter()
 
-	cursor := pagination.Cursor{Type: "item", Value: "item-025"}.Encode()
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-025"}, nil)
 	req := httptest.NewRequestWithContext(
 		t.Context(),
 		http.MethodGet,
@@ -189,7 +227,7 @@ func TestListWithCategoryFilter(t *testing.T) {
 		}
 	}
 
-	if data.Total == 0 {
+	if len(data.Items) == 0 {
 		t.Error("expected at least one tool item")
 	}
 }
@@ -242,7 +280,7 @@ func TestListInvalidCursor(t *testing.T) {
 func TestListCursorTypeMismatch(t *testing.T) {
 	router := newTestRouter()
 
-	cursor := pagination.Cursor{Type: "wrongtype", Value: "item-001"}.Encode()
+	cursor := testCursors.Encode(pagination.Cursor{Type: "wrongtype", Value: "item-001"}, nil)
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor, nil)
 	req.Header.Set(chimiddleware.RequestIDHeader, "items-cursor-mismatch")
 	resp := httptest.NewRecorder()
@@ -264,7 +302,7 @@ func TestListCursorTypeMismatch(t *testing.T) {
 
 func TestListCursorRequiresType(t *testing.T) {
 	router := newTestRouter()
-	cursor := pagination.Cursor{Value: "item-001"}.Encode()
+	cursor := testCursors.Encode(pagination.Cursor{Value: "item-001"}, nil)
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor, nil)
 	resp := httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
@@ -288,26 +326,30 @@ func TestListCursorLengthIsBounded(t *testing.T) {
 	}
 }
 
-func TestListCursorUnknownItem(t *testing.T) {
-	router := newTestRouter()
+func TestListCursorResumesAfterDeletedItem(t *testing.T) {
+	store := itemsvc.NewMemoryStore(itemsvc.SampleItems()...)
+	router := newTestRouterWithStore(store)
 
-	cursor := pagination.Cursor{Type: "item", Value: "nonexistent"}.Encode()
-	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor, nil)
-	req.Header.Set(chimiddleware.RequestIDHeader, "items-cursor-unknown")
+	first := listPages(t, router, "/items?limit=5")[0]
+	if err := store.Delete(t.Context(), "admin-1", "item-005"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: first.Items[4].ID}, nil)
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?limit=5&cursor="+cursor, nil)
+	req.Header.Set(chimiddleware.RequestIDHeader, "items-cursor-deleted")
 	resp := httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
 
-	if resp.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400, got %d", resp.Code)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
 	}
-
-	var problem huma.ErrorModel
-	if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
-		t.Fatalf("json unmarshal problem: %v", err)
+	var data ListData
+	if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
 	}
-
-	if !strings.Contains(problem.Detail, "unknown item") {
-		t.Errorf("expected unknown item error, got %s", problem.Detail)
+	if len(data.Items) != 5 || data.Items[0].ID != "item-006" {
+		t.Fatalf("expected the page to resume at item-006, got %+v", data.Items)
 	}
 }
 
@@ -458,7 +500,7 @@ func TestListValidateLimitRange(t *testing.T) {
 func TestListCursorAtFirstItem(t *testing.T) {
 	router := newTestRouter()
 
-	cursor := pagination.Cursor{Type: "item", Value: "item-001"}.Encode()
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-001"}, nil)
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor+"&limit=5", nil)
 	req.Header.Set(chimiddleware.RequestIDHeader, "items-cursor-first")
 	resp := httptest.NewRecorder()
@@ -486,7 +528,7 @@ func TestListCursorAtFirstItem(t *testing.T) {
 func TestListCursorAtLastItem(t *testing.T) {
 	router := newTestRouter()
 
-	cursor := pagination.Cursor{Type: "item", Value: "item-030"}.Encode()
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-030"}, nil)
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor+"&limit=5", nil)
 	req.Header.Set(chimiddleware.RequestIDHeader, "items-cursor-last")
 	resp := httptest.NewRecorder()
@@ -565,7 +607,7 @@ func TestListLimitPreservedInLink(t *testing.T) {
 func TestListBackwardsNavigation(t *testing.T) {
 	router := newTestRouter()
 
-	cursor := pagination.Cursor{Type: "item", Value: "item-005"}.Encode()
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-005"}, nil)
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor+"&limit=5", nil)
 	req.Header.Set(chimiddleware.RequestIDHeader, "items-backwards")
 	resp := httptest.NewRecorder()
@@ -600,6 +642,44 @@ func TestListBackwardsNavigation(t *testing.T) {
 	}
 }
 
+func TestListWalksBackwardFromLastLink(t *testing.T) {
+	router := newTestRouter()
+	path := "/items?currency=USD&sort=priceMinor&direction=desc&limit=7"
+
+	var forward []string
+	for _, page := range listPages(t, router, path) {
+		for _, item := range page.Items {
+			forward = append(forward, item.ID)
+		}
+	}
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if got := resp.Header().Get("Pagination"); got != "limit=7, count=7, next, prev=?0" {
+		t.Fatalf("unexpected Pagination header %q", got)
+	}
+	lastURL := extractLinkURL(resp.Header().Get("Link"), "last")
+	if lastURL == "" {
+		t.Fatalf("expected a last link, got %q", resp.Header().Get("Link"))
+	}
+
+	pages := followLinks(t, router, lastURL, "prev")
+	var backward []string
+	for i := len(pages) - 1; i >= 0; i-- {
+		for _, item := range pages[i].Items {
+			backward = append(backward, item.ID)
+		}
+	}
+	// The last page is a full page, so walking back from it yields every item exactly once.
+	if !slices.Equal(backward, forward) {
+		t.Fatalf("expected backward pages to mirror the forward order\n%v\ngot\n%v", forward, backward)
+	}
+	if len(pages[0].Items) != 7 {
+		t.Fatalf("expected a full last page, got %d items", len(pages[0].Items))
+	}
+}
+
 func TestListExactPageBoundary(t *testing.T) {
 	router := newTestRouter()
 
@@ -671,6 +751,52 @@ func TestListCategoryFilterWithPagination(t *testing.T) {
 	}
 }
 
+func TestListRejectsCursorFromOtherFilters(t *testing.T) {
+	router := newTestRouter()
+
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-003"}, url.Values{"category": {"tools"}})
+	for category, want := range map[string]int{"tools": http.StatusOK, "power": http.StatusBadRequest} {
+		req := httptest.NewRequestWithContext(
+			t.Context(),
+			http.MethodGet,
+			"/items?category="+category+"&cursor="+cursor,
+			nil,
+		)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != want {
+			t.Fatalf("category=%s: expected %d, got %d: %s", category, want, resp.Code, resp.Body.String())
+		}
+	}
+}
+
+func TestListRejectsUnsignedCursors(t *testing.T) {
+	router := newTestRouter()
+	unknownKey := strings.Replace(
+		testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-010"}, nil), ".test.", ".other.", 1,
+	)
+	for name, cursor := range map[string]string{
+		"legacy":      pagination.Cursor{Type: "item", Value: "item-010"}.Encode(),
+		"unknown key": unknownKey,
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?cursor="+cursor, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			if resp.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", resp.Code)
+			}
+			var problem huma.ErrorModel
+			if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("json unmarshal problem: %v", err)
+			}
+			if problem.Detail != pagination.ErrInvalidCursor.Error() {
+				t.Errorf("expected %q, got %q", pagination.ErrInvalidCursor.Error(), problem.Detail)
+			}
+		})
+	}
+}
+
 func TestListValidCategory(t *testing.T) {
 	router := newTestRouter()
 
@@ -688,7 +814,7 @@ func TestListValidCategory(t *testing.T) {
 		t.Fatalf("json unmarshal: %v", err)
 	}
 
-	if data.Total == 0 {
+	if len(data.Items) == 0 {
 		t.Error("expected items for electronics category")
 	}
 
@@ -800,7 +926,7 @@ func TestListValidationErrors422(t *testing.T) {
 func TestListEmptyCategoryReturnsAll(t *testing.T) {
 	router := newTestRouter()
 
-	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?category=", nil)
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?category=&total=true", nil)
 	req.Header.Set(chimiddleware.RequestIDHeader, "items-empty-category")
 	resp := httptest.NewRecorder()
 	router.ServeHTTP(resp, req)
@@ -814,8 +940,8 @@ func TestListEmptyCategoryReturnsAll(t *testing.T) {
 		t.Fatalf("json unmarshal: %v", err)
 	}
 
-	if data.Total != 30 {
-		t.Errorf("empty category should return all items, got %d", data.Total)
+	if data.Total == nil || *data.Total != 30 {
+		t.Errorf("empty category should return all items, got %v", data.Total)
 	}
 }
 
@@ -834,14 +960,9 @@ func TestListCursorErrors400(t *testing.T) {
 		},
 		{
 			name:        "wrong-cursor-type",
-			cursor:      pagination.Cursor{Type: "order", Value: "item-001"}.Encode(),
+			cursor:      testCursors.Encode(pagination.Cursor{Type: "order", Value: "item-001"}, nil),
 			wantMessage: "cursor type mismatch",
 		},
-		{
-			name:        "unknown-item-id",
-			cursor:      pagination.Cursor{Type: "item", Value: "item-999"}.Encode(),
-			wantMessage: "unknown item",
-		},
 		{
 			name:        "malformed-cursor-no-separator",
 			cursor:      "dGVzdA",
@@ -907,7 +1028,7 @@ func TestListAllValidCategories(t *testing.T) {
 				t.Fatalf("json unmarshal: %v", err)
 			}
 
-			if data.Total == 0 {
+			if len(data.Items) == 0 {
 				t.Errorf("expected items for category %s", category)
 			}
 
@@ -920,6 +1041,232 @@ func TestListAllValidCategories(t *testing.T) {
 	}
 }
 
+// listPages follows next links from path and returns every page.
+func listPages(t *testing.T, router chi.Router, path string) []ListData {
+	t.Helper()
+	return followLinks(t, router, path, "next")
+}
+
+// followLinks requests path and then each page linked by rel, returning the pages in request order.
+func followLinks(t *testing.T, router chi.Router, path, rel string) []ListData {
+	t.Helper()
+	var pages []ListData
+	for path != "" && len(pages) < 20 {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", path, resp.Code, resp.Body.String())
+		}
+		var data ListData
+		if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+			t.Fatalf("%s: json unmarshal: %v", path, err)
+		}
+		pages = append(pages, data)
+		path = extractLinkURL(resp.Header().Get("Link"), rel)
+	}
+	return pages
+}
+
+func TestListSortsAcrossPages(t *testing.T) {
+	router := newTestRouter()
+	for _, test := range []struct {
+		query string
+		less  func(a, b Item) bool
+	}{
+		{"currency=USD&sort=priceMinor&direction=desc", func(a, b Item) bool {
+			return a.Price.Minor() > b.Price.Minor() || (a.Price.Minor() == b.Price.Minor() && a.ID > b.ID)
+		}},
+		{"sort=name", func(a, b Item) bool { return a.Name < b.Name || (a.Name == b.Name && a.ID < b.ID) }},
+		{"sort=createdAt&direction=desc", func(a, b Item) bool {
+			return a.CreatedAt.After(b.CreatedAt.Time) || (a.CreatedAt.Equal(b.CreatedAt.Time) && a.ID > b.ID)
+		}},
+		{"direction=desc", func(a, b Item) bool { return a.ID > b.ID }},
+	} {
+		t.Run(test.query, func(t *testing.T) {
+			var items []Item
+			for _, page := range listPages(t, router, "/items?limit=7&"+test.query) {
+				items = append(items, page.Items...)
+			}
+			if len(items) != len(itemsvc.SampleItems()) {
+				t.Fatalf("expected %d items, got %d", len(itemsvc.SampleItems()), len(items))
+			}
+			for i := 1; i < len(items); i++ {
+				if !test.less(items[i-1], items[i]) {
+					t.Fatalf("items out of order at %d: %s then %s", i, items[i-1].ID, items[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestListSortCursorKeepsPositionWhenItemChanges(t *testing.T) {
+	router := newTestRouter()
+
+	// The cursor was issued when item-001 cost 0; it resumes after that price, not after the
+	// item's current one.
+	query := url.Values{"currency": {"USD"}, "sort": {"priceMinor"}}
+	cursor := testCursors.Encode(pagination.Cursor{Type: "item", Value: `["0","item-001"]`}, query)
+	pages := listPages(t, router, "/items?currency=USD&sort=priceMinor&limit=100&cursor="+cursor)
+	if len(pages[0].Items) != len(itemsvc.SampleItems()) {
+		t.Fatalf("expected every item to sort after the cursor, got %d", len(pages[0].Items))
+	}
+}
+
+func TestListFiltersCombine(t *testing.T) {
+	router := newTestRouter()
+
+	pages := listPages(
+		t,
+		router,
+		"/items?category=tools,power&inStock=true&currency=USD&minPriceMinor=1000&maxPriceMinor=10000&limit=2&total=true",
+	)
+	var items []Item
+	for _, page := range pages {
+		items = append(items, page.Items...)
+	}
+	if len(items) == 0 {
+		t.Fatal("expected matching items")
+	}
+	for _, item := range items {
+		if item.Category != "tools" && item.Category != "power" || !item.InStock ||
+			item.Price.Minor() < 1000 || item.Price.Minor() > 10000 {
+			t.Errorf("item %s does not match the filters: %+v", item.ID, item)
+		}
+	}
+	for i, page := range pages {
+		if page.Total == nil || *page.Total != len(items) {
+			t.Errorf("page %d: expected total %d, got %v", i+1, len(items), page.Total)
+		}
+	}
+}
+
+func TestListSearchesNameAndDescription(t *testing.T) {
+	router := newTestRouter()
+
+	pages := listPages(t, router, "/items?q=WIDGET&limit=100")
+	if len(pages[0].Items) == 0 {
+		t.Fatal("expected items matching widget")
+	}
+	for _, item := range pages[0].Items {
+		text := strings.ToLower(item.Name + " " + item.Description)
+		if !strings.Contains(text, "widget") {
+			t.Errorf("item %s does not mention widget", item.ID)
+		}
+	}
+}
+
+func TestListPreservesFiltersInLinks(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"/items?category=tools,electronics&sort=name&direction=desc&q=a&total=true&limit=1",
+		nil,
+	)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	next, err := url.Parse(extractLinkURL(resp.Header().Get("Link"), "next"))
+	if err != nil {
+		t.Fatalf("parse next link: %v", err)
+	}
+	query := next.Query()
+	for key, want := range map[string]string{
+		"category":  "electronics,tools",
+		"sort":      "name",
+		"direction": "desc",
+		"q":         "a",
+		"total":     "true",
+		"limit":     "1",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("expected %s=%q in next link, got %q", key, want, got)
+		}
+	}
+}
+
+func TestListRejectsCursorFromOtherSort(t *testing.T) {
+	router := newTestRouter()
+
+	byName := url.Values{"sort": {"name"}}
+	position := testCursors.Encode(pagination.Cursor{Type: "item", Value: `["Alpha Widget","item-001"]`}, byName)
+	bareID := testCursors.Encode(pagination.Cursor{Type: "item", Value: "item-001"}, byName)
+	for name, path := range map[string]string{
+		"other sort":         "/items?currency=USD&sort=priceMinor&cursor=" + position,
+		"malformed position": "/items?sort=name&cursor=" + bareID,
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			if resp.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestListRejectsInvalidFilters(t *testing.T) {
+	router := newTestRouter()
+	for _, query := range []string{
+		"sort=price",
+		"direction=up",
+		"category=tools,bogus",
+		"category=tools,tools",
+		"inStock=yes",
+		"minPriceMinor=-1",
+		"currency=USD&minPriceMinor=500&maxPriceMinor=100",
+		"currency=usd",
+		"minPriceMinor=500",
+		"maxPriceMinor=0",
+		"sort=priceMinor",
+		"q=" + strings.Repeat("a", 101),
+		"total=maybe",
+	} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?"+query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestListAcceptsZeroMaxPrice(t *testing.T) {
+	router := newTestRouter()
+
+	pages := listPages(t, router, "/items?currency=USD&maxPriceMinor=0&total=true")
+	if len(pages[0].Items) != 0 || pages[0].Total == nil || *pages[0].Total != 0 {
+		t.Fatalf("expected no free items, got %+v", pages[0])
+	}
+}
+
+func TestListComparesPricesWithinCurrency(t *testing.T) {
+	yen := itemsvc.Item{ID: "item-yen", Name: "Yen Widget", Category: "tools", Price: money.MustNew(500, "JPY")}
+	router := newTestRouterWithStore(itemsvc.NewMemoryStore(append(itemsvc.SampleItems(), yen)...))
+
+	for _, path := range []string{
+		"/items?currency=USD&maxPriceMinor=1000&limit=100",
+		"/items?currency=USD&sort=priceMinor&limit=100",
+	} {
+		for _, item := range listPages(t, router, path)[0].Items {
+			if item.Currency != "USD" {
+				t.Fatalf("%s: expected only USD items, got %s in %s", path, item.ID, item.Currency)
+			}
+		}
+	}
+	pages := listPages(t, router, "/items?currency=JPY&sort=priceMinor")
+	if len(pages[0].Items) != 1 || pages[0].Items[0].ID != yen.ID {
+		t.Fatalf("expected only the JPY item, got %+v", pages[0].Items)
+	}
+}
+
 func extractLinkURL(linkHeader, rel string) string {
 	for part := range strings.SplitSeq(linkHeader, ",") {
 		part = strings.TrimSpace(part)
@@ -933,3 +1280,544 @@ func extractLinkURL(linkHeader, rel string) string {
 	}
 	return ""
 }
+
+// failingStore fails every call with err.
+type failingStore struct {
+	itemsvc.MemoryStore
+	err error
+}
+
+func (s *failingStore) Get(context.Context, string) (*itemsvc.Item, error) { return nil, s.err }
+
+func (s *failingStore) List(context.Context, itemsvc.ListParams) (pagination.Result[itemsvc.Item], error) {
+	return pagination.Result[itemsvc.Item]{}, s.err
+}
+
+func serveItemRequest(t *testing.T, router chi.Router, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+const newItemBody = `{"name":"Omega Drill","category":"tools","price":{"amount":"89.00","currency":"EUR"},"inStock":true}`
+
+func TestCreateItem(t *testing.T) {
+	router := newTestRouter()
+
+	resp := serveItemRequest(t, router, http.MethodPost, "/items", "admin-token", newItemBody)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created Item
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if created.Name != "Omega Drill" || created.Price != money.MustNew(8900, "EUR") {
+		t.Fatalf("unexpected created item %+v", created)
+	}
+	location := resp.Header().Get("Location")
+	if location != "/items/"+created.ID {
+		t.Fatalf("expected Location /items/%s, got %q", created.ID, location)
+	}
+
+	resp = serveItemRequest(t, router, http.MethodGet, location, "", "")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200 from Location, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var found bool
+	for _, page := range listPages(t, router, "/items?category=tools") {
+		for _, item := range page.Items {
+			found = found || item.ID == created.ID
+		}
+	}
+	if !found {
+		t.Fatal("expected the created item to be listed")
+	}
+}
+
+func TestItemWritesRequireAdmin(t *testing.T) {
+	router := newTestRouter()
+
+	for _, tc := range []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/items", newItemBody},
+		{http.MethodPatch, "/items/item-001", `{"inStock":false}`},
+		{http.MethodDelete, "/items/item-001", ""},
+	} {
+		t.Run(tc.method, func(t *testing.T) {
+			if resp := serveItemRequest(t, router, tc.method, tc.path, "", tc.body); resp.Code != http.StatusUnauthorized {
+				t.Fatalf("anonymous: expected 401, got %d", resp.Code)
+			}
+			resp := serveItemRequest(t, router, tc.method, tc.path, "user-token", tc.body)
+			if resp.Code != http.StatusForbidden {
+				t.Fatalf("non-admin: expected 403, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+
+	resp := serveItemRequest(t, router, http.MethodGet, "/items/item-001", "", "")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected rejected writes to leave item-001 readable, got %d", resp.Code)
+	}
+	var item Item
+	if err := json.Unmarshal(resp.Body.Bytes(), &item); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if !item.InStock {
+		t.Fatal("expected rejected writes to leave item-001 unchanged")
+	}
+}
+
+func TestCreateItemValidation(t *testing.T) {
+	router := newTestRouter()
+
+	for name, body := range map[string]string{
+		"negative price":     `{"name":"Omega","category":"tools","price":{"amount":"-1.00","currency":"EUR"},"inStock":true}`,
+		"lowercase currency": `{"name":"Omega","category":"tools","price":{"amount":"1.00","currency":"eur"},"inStock":true}`,
+		"unknown currency":   `{"name":"Omega","category":"tools","price":{"amount":"1.00","currency":"ZZZ"},"inStock":true}`,
+		"no currency":        `{"name":"Omega","category":"tools","price":{"amount":"1.00","currency":"XXX"},"inStock":true}`,
+		"too many decimals":  `{"name":"Omega","category":"tools","price":{"amount":"100.5","currency":"JPY"},"inStock":true}`,
+		"numeric amount":     `{"name":"Omega","category":"tools","price":{"amount":1,"currency":"EUR"},"inStock":true}`,
+		"unknown category":   `{"name":"Omega","category":"toys","price":{"amount":"1.00","currency":"EUR"},"inStock":true}`,
+		"blank name":         `{"name":" ","category":"tools","price":{"amount":"1.00","currency":"EUR"},"inStock":true}`,
+		"missing price":      `{"name":"Omega","category":"tools","inStock":true}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := serveItemRequest(t, router, http.MethodPost, "/items", "admin-token", body)
+			if resp.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+			}
+		})
+	}
+
+	for name, tc := range map[string]struct {
+		price string
+		want  money.Money
+	}{
+		"free":        {`{"amount":"0","currency":"JPY"}`, money.MustNew(0, "JPY")},
+		"yen":         {`{"amount":"1200","currency":"JPY"}`, money.MustNew(1200, "JPY")},
+		"dinar fils":  {`{"amount":"4.125","currency":"KWD"}`, money.MustNew(4125, "KWD")},
+		"short cents": {`{"amount":"12.5","currency":"USD"}`, money.MustNew(1250, "USD")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body := `{"name":"Gadget","category":"tools","price":` + tc.price + `,"inStock":true}`
+			resp := serveItemRequest(t, router, http.MethodPost, "/items", "admin-token", body)
+			if resp.Code != http.StatusCreated {
+				t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+			}
+			var created Item
+			if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+				t.Fatalf("json unmarshal: %v", err)
+			}
+			if created.Price != tc.want {
+				t.Fatalf("expected price %v, got %v", tc.want, created.Price)
+			}
+		})
+	}
+}
+
+func TestUpdateItem(t *testing.T) {
+	router := newTestRouter()
+
+	resp := serveItemRequest(t, router, http.MethodPatch, "/items/item-003", "admin-token",
+		`{"price":{"amount":"12.50","currency":"GBP"}}`)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var item Item
+	if err := json.Unmarshal(resp.Body.Bytes(), &item); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if item.Price != money.MustNew(1250, "GBP") || item.Name != "Gamma Tool" {
+		t.Fatalf("unexpected updated item %+v", item)
+	}
+	if !item.UpdatedAt.After(item.CreatedAt.Time) {
+		t.Fatalf("expected updatedAt to advance, got %v", item.UpdatedAt)
+	}
+
+	for _, tc := range []struct {
+		name, path, body string
+		status           int
+	}{
+		{"empty body", "/items/item-003", `{}`, http.StatusUnprocessableEntity},
+		{"unknown currency", "/items/item-003", `{"price":{"amount":"1.00","currency":"ZZZ"}}`, http.StatusUnprocessableEntity},
+		{"negative price", "/items/item-003", `{"price":{"amount":"-0.05","currency":"EUR"}}`, http.StatusUnprocessableEntity},
+		{"price without currency", "/items/item-003", `{"price":{"amount":"1.00"}}`, http.StatusUnprocessableEntity},
+		{"malformed ID", "/items/ITEM_3", `{"inStock":true}`, http.StatusUnprocessableEntity},
+		{"unknown item", "/items/item-999", `{"inStock":true}`, http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := serveItemRequest(t, router, http.MethodPatch, tc.path, "admin-token", tc.body)
+			if resp.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteItem(t *testing.T) {
+	router := newTestRouter()
+
+	resp := serveItemRequest(t, router, http.MethodDelete, "/items/item-002", "admin-token", "")
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp = serveItemRequest(t, router, http.MethodGet, "/items/item-002", "", ""); resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", resp.Code)
+	}
+	resp = serveItemRequest(t, router, http.MethodDelete, "/items/item-002", "admin-token", "")
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 on second delete, got %d", resp.Code)
+	}
+}
+
+func TestItemStoreErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"unavailable", errors.Join(itemsvc.ErrUnavailable, context.DeadlineExceeded), http.StatusServiceUnavailable},
+		{"unexpected", errors.New("boom"), http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newTestRouterWithStore(&failingStore{err: tc.err})
+			for _, path := range []string{"/items", "/items/item-001"} {
+				resp := serveItemRequest(t, router, http.MethodGet, path, "", "")
+				if resp.Code != tc.status {
+					t.Fatalf("%s: expected %d, got %d", path, tc.status, resp.Code)
+				}
+				if strings.Contains(resp.Body.String(), "boom") {
+					t.Fatalf("%s: expected the store error to stay out of the response", path)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceDisplayFollowsAcceptLanguage(t *testing.T) {
+	store := itemsvc.NewMemoryStore(itemsvc.Item{
+		ID:       "item-001",
+		Name:     "Euro Lamp",
+		Category: "electronics",
+		Price:    money.MustNew(123450, "EUR"),
+	})
+	router := newTestRouterWithStore(store)
+
+	for _, tc := range []struct {
+		name, acceptLanguage, want string
+	}{
+		{"german", "de-DE,en;q=0.5", "€ 1.234,50"},
+		{"english", "en-US", "€ 1,234.50"},
+		{"none", "", ""},
+		{"malformed", "!!", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, path := range []string{"/items", "/items/item-001"} {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				if tc.acceptLanguage != "" {
+					req.Header.Set("Accept-Language", tc.acceptLanguage)
+				}
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+				if resp.Code != http.StatusOK {
+					t.Fatalf("%s: expected 200, got %d: %s", path, resp.Code, resp.Body.String())
+				}
+				if !slices.Contains(resp.Header().Values("Vary"), "Accept-Language") {
+					t.Fatalf("%s: expected Vary: Accept-Language, got %q", path, resp.Header().Values("Vary"))
+				}
+
+				item := Item{}
+				if path == "/items" {
+					var data ListData
+					if err := json.Unmarshal(resp.Body.Bytes(), &data); err != nil {
+						t.Fatalf("json unmarshal: %v", err)
+					}
+					item = data.Items[0]
+				} else if err := json.Unmarshal(resp.Body.Bytes(), &item); err != nil {
+					t.Fatalf("json unmarshal: %v", err)
+				}
+				if item.PriceDisplay != tc.want {
+					t.Fatalf("%s: expected priceDisplay %q, got %q", path, tc.want, item.PriceDisplay)
+				}
+				if item.Price.Amount() != "1234.50" || item.Price.Currency() != "EUR" {
+					t.Fatalf("%s: expected the price to be unaffected by locale, got %v", path, item.Price)
+				}
+			}
+		})
+	}
+}
+
+// getStream requests path with the given streaming Accept type.
+func getStream(t *testing.T, router chi.Router, path, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Accept-Language", "en-US")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestListItemsStreamsEveryMatch(t *testing.T) {
+	router := newTestRouter()
+	all := listPages(t, router, "/items?category=electronics&limit=100")[0].Items
+
+	for _, tc := range []struct {
+		accept string
+		decode func(t *testing.T, body []byte) []Item
+	}{
+		{stream.NDJSON, func(t *testing.T, body []byte) []Item {
+			var items []Item
+			for line := range strings.Lines(string(body)) {
+				var item Item
+				if err := json.Unmarshal([]byte(line), &item); err != nil {
+					t.Fatalf("decode line %q: %v", line, err)
+				}
+				items = append(items, item)
+			}
+			return items
+		}},
+		{stream.CBORSeq, func(t *testing.T, body []byte) []Item {
+			var items []Item
+			for rest := body; len(rest) > 0; {
+				var item Item
+				var err error
+				if rest, err = cbor.UnmarshalFirst(rest, &item); err != nil {
+					t.Fatalf("decode record: %v", err)
+				}
+				items = append(items, item)
+			}
+			return items
+		}},
+	} {
+		t.Run(tc.accept, func(t *testing.T) {
+			resp := getStream(t, router, "/items?category=electronics&limit=1", tc.accept)
+
+			if resp.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+			}
+			if got := resp.Header().Get("Content-Type"); got != tc.accept {
+				t.Fatalf("expected Content-Type %q, got %q", tc.accept, got)
+			}
+			items := tc.decode(t, resp.Body.Bytes())
+			if len(items) != len(all) {
+				t.Fatalf("expected all %d matches ignoring the limit, got %d", len(all), len(items))
+			}
+			for i, item := range items {
+				if item.ID != all[i].ID {
+					t.Fatalf("record %d: expected %s, got %s", i, all[i].ID, item.ID)
+				}
+				if item.PriceDisplay == "" {
+					t.Fatalf("record %d: expected a priceDisplay for the requested locale", i)
+				}
+			}
+		})
+	}
+}
+
+func TestListItemsStreamStartsAtNextCursor(t *testing.T) {
+	router := newTestRouter()
+	all := listPages(t, router, "/items?limit=100")[0].Items
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?limit=5", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	links := resp.Header().Get("Link")
+
+	resp = getStream(t, router, extractLinkURL(links, "next"), stream.NDJSON)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var ids []string
+	for line := range strings.Lines(resp.Body.String()) {
+		var item Item
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			t.Fatalf("decode line %q: %v", line, err)
+		}
+		ids = append(ids, item.ID)
+	}
+	var want []string
+	for _, item := range all[5:] {
+		want = append(want, item.ID)
+	}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("expected the stream to continue after the first page with %v, got %v", want, ids)
+	}
+
+	resp = getStream(t, router, extractLinkURL(links, "last"), stream.NDJSON)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a backward cursor, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Fatalf("expected Problem Details, got %q", got)
+	}
+}
+
+func TestListItemsCursorsIgnoreDisplayParameters(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet,
+		"/items?category=electronics&limit=2&fields=id&total=true", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	next, err := url.Parse(extractLinkURL(resp.Header().Get("Link"), "next"))
+	if err != nil || next.Query().Get("fields") != "id" || next.Query().Get("total") != "true" {
+		t.Fatalf("expected the next link to keep fields and total, got %q", next)
+	}
+
+	for _, change := range []func(url.Values){
+		func(q url.Values) { q.Del("fields"); q.Del("total") },
+		func(q url.Values) { q.Set("fields", "id,name,priceMinor") },
+		func(q url.Values) { q.Set("total", "false") },
+	} {
+		q := next.Query()
+		change(q)
+		path := next.Path + "?" + q.Encode()
+		req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		if resp.Code != http.StatusOK {
+			t.Fatalf(
+				"%s: expected the cursor to survive a display change, got %d: %s",
+				path,
+				resp.Code,
+				resp.Body.String(),
+			)
+		}
+	}
+
+	q := next.Query()
+	q.Set("category", "tools")
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, next.Path+"?"+q.Encode(), nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a changed filter, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+// pageRecordingStore records the limit of every List call and whether it asked for a total.
+type pageRecordingStore struct {
+	*itemsvc.MemoryStore
+	limits []int
+	totals []bool
+}
+
+func (s *pageRecordingStore) List(
+	ctx context.Context,
+	params itemsvc.ListParams,
+) (pagination.Result[itemsvc.Item], error) {
+	s.limits = append(s.limits, params.Limit)
+	s.totals = append(s.totals, params.Total)
+	return s.MemoryStore.List(ctx, params)
+}
+
+func TestListItemsStreamReadsStorePages(t *testing.T) {
+	stored := make([]itemsvc.Item, 2*streamBatchSize+1)
+	for i := range stored {
+		stored[i] = itemsvc.Item{
+			ID:       fmt.Sprintf("item-%03d", i),
+			Name:     fmt.Sprintf("Item %d", i),
+			Category: "tools",
+			Price:    money.MustNew(100, "EUR"),
+		}
+	}
+	store := &pageRecordingStore{MemoryStore: itemsvc.NewMemoryStore(stored...)}
+	router := newTestRouterWithStore(store)
+
+	resp := getStream(t, router, "/items?fields=id&total=true", stream.NDJSON)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var ids []string
+	for line := range strings.Lines(resp.Body.String()) {
+		var item Item
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			t.Fatalf("decode line %q: %v", line, err)
+		}
+		ids = append(ids, item.ID)
+	}
+	if len(ids) != len(stored) || ids[0] != stored[0].ID || ids[len(ids)-1] != stored[len(stored)-1].ID {
+		t.Fatalf("expected all %d items in order, got %d", len(stored), len(ids))
+	}
+	if want := []int{streamBatchSize, streamBatchSize, streamBatchSize}; !slices.Equal(store.limits, want) {
+		t.Fatalf("expected the store to be read in pages %v, got %v", want, store.limits)
+	}
+	if slices.Contains(store.totals, true) {
+		t.Fatalf("expected a stream not to count matches, got totals %v", store.totals)
+	}
+}
+
+func TestItemFieldSelection(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?limit=2&fields=id,name,price", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var list struct {
+		Items []map[string]json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &list); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(list.Items))
+	}
+	for _, item := range list.Items {
+		if got := slices.Sorted(maps.Keys(item)); !slices.Equal(got, []string{"id", "name", "price"}) {
+			t.Fatalf("expected only id, name, and price, got %v", got)
+		}
+	}
+	next := extractLinkURL(resp.Header().Get("Link"), "next")
+	if parsed, err := url.Parse(next); err != nil || parsed.Query().Get("fields") != "id,name,price" {
+		t.Fatalf("expected the next link to keep the selection, got %q", next)
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items/item-001?fields=priceDisplay", nil)
+	req.Header.Set("Accept-Language", "en-US")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if got := strings.TrimSpace(resp.Body.String()); got != `{"priceDisplay":"$ 29.99"}` {
+		t.Fatalf(`expected {"priceDisplay":"$ 29.99"}, got %d %s`, resp.Code, got)
+	}
+
+	resp = getStream(t, router, "/items?fields=id", stream.NDJSON)
+	line, _, _ := strings.Cut(resp.Body.String(), "\n")
+	if line != `{"id":"item-001"}` {
+		t.Fatalf(`expected streamed records to be pruned to {"id":"item-001"}, got %s`, line)
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items/item-001?fields=id,name,priceMinor", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if got := strings.TrimSpace(resp.Body.String()); got != `{"id":"item-001","name":"Alpha Widget","priceMinor":2999}` {
+		t.Fatalf(`expected {"id":"item-001","name":"Alpha Widget","priceMinor":2999}, got %d %s`, resp.Code, got)
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/items?fields=id,priceMinor,cost", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for an unknown field, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if !strings.Contains(resp.Body.String(), `"location":"query.fields"`) {
+		t.Fatalf("expected a query.fields error, got %s", resp.Body.String())
+	}
+}
@@ -1,9 +1,134 @@
 package items
 
-import "github.com/janisto/huma-playground/internal/platform/pagination"
+import (
+	"github.com/danielgtaylor/huma/v2"
+	"golang.org/x/text/language"
+
+	"github.com/janisto/huma-playground/internal/platform/fields"
+	"github.com/janisto/huma-playground/internal/platform/money"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
+)
+
+// ItemFilters defines query parameters for filtering, sorting, and counting items.
+type ItemFilters struct {
+	Category      []string `query:"category"      doc:"Comma-separated categories; items match any"                                                                                 example:"electronics,tools" maxItems:"6" uniqueItems:"true" enum:"electronics,tools,accessories,robotics,power,components"`
+	InStock       string   `query:"inStock"       doc:"Filter by availability"                                                                                                      example:"true"                                              enum:"true,false"`
+	Currency      string   `query:"currency"      doc:"ISO 4217 currency code; required with a price range or sort=priceMinor, since prices in different currencies do not compare" example:"EUR"                                                                                                              pattern:"^[A-Z]{3}$"`
+	MinPriceMinor int64    `query:"minPriceMinor" doc:"Minimum price in the currency minor unit, inclusive; requires currency"                                                      example:"1000"                                                                                                                                  minimum:"0"`
+	MaxPriceMinor int64    `query:"maxPriceMinor" doc:"Maximum price in the currency minor unit, inclusive; requires currency"                                                      example:"5000"                                                                                                                                  minimum:"0"`
+	Q             string   `query:"q"             doc:"Case-insensitive text to find in the name or description"                                                                    example:"widget"                                                                                                                                            maxLength:"100"`
+	Sort          string   `query:"sort"          doc:"Sort field; items are ordered by ID when omitted, and priceMinor requires currency"                                          example:"priceMinor"                                        enum:"name,priceMinor,createdAt"`
+	Direction     string   `query:"direction"     doc:"Sort direction"                                                                                                              example:"desc"                                              enum:"asc,desc"`
+	Total         bool     `query:"total"         doc:"Also return the number of items matching the filters, on every page"                                                         example:"true"`
+
+	// hasMaxPrice distinguishes maxPriceMinor=0 from an omitted parameter.
+	hasMaxPrice bool
+}
+
+// Resolve records whether a maximum price was given, rejects price ranges that end before they start,
+// and requires a currency wherever prices are compared.
+func (f *ItemFilters) Resolve(ctx huma.Context) []error {
+	f.hasMaxPrice = ctx.Query("maxPriceMinor") != ""
+	var errs []error
+	if f.hasMaxPrice && f.MaxPriceMinor < f.MinPriceMinor {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: "query.maxPriceMinor",
+			Message:  "maxPriceMinor must not be less than minPriceMinor",
+			Value:    f.MaxPriceMinor,
+		})
+	}
+	if f.Currency == "" && (f.MinPriceMinor > 0 || f.hasMaxPrice || f.Sort == itemsvc.SortPrice) {
+		errs = append(errs, &huma.ErrorDetail{
+			Location: "query.currency",
+			Message:  "currency is required with minPriceMinor, maxPriceMinor, or sort=priceMinor",
+		})
+	}
+	return errs
+}
+
+// DisplayParams selects the locale used to format prices for display.
+type DisplayParams struct {
+	AcceptLanguage string `header:"Accept-Language" doc:"Locale for priceDisplay; omitted when not sent" example:"de-DE"`
+}
+
+// locale returns the most preferred language in the Accept-Language header. Malformed headers are
+// ignored rather than rejected, since the header only affects presentation.
+func (p DisplayParams) locale() (language.Tag, bool) {
+	tags, _, _ := language.ParseAcceptLanguage(p.AcceptLanguage)
+	for _, tag := range tags {
+		if tag != language.Und {
+			return tag, true
+		}
+	}
+	return language.Und, false
+}
 
 // ItemsListInput defines query parameters for listing items.
 type ItemsListInput struct {
 	pagination.Params
-	Category string `query:"category" doc:"Filter by category" example:"electronics" enum:"electronics,tools,accessories,robotics,power,components"`
+	ItemFilters
+	DisplayParams
+	fields.Selection
+}
+
+// ItemCreateInput for POST /items
+type ItemCreateInput struct {
+	Body struct {
+		Name        string      `json:"name"                  minLength:"1" maxLength:"100" pattern:"^\\S(?:.*\\S)?$" doc:"Display name"                     example:"Alpha Widget"`
+		Category    string      `json:"category"              enum:"electronics,tools,accessories,robotics,power,components"  doc:"Item category"                    example:"electronics"`
+		Price       money.Money `json:"price"                 doc:"Price as a decimal amount with its ISO 4217 currency"`
+		InStock     bool        `json:"inStock"                                                                               doc:"Availability status"              example:"true"`
+		Description string      `json:"description,omitempty" maxLength:"500"                                                 doc:"Detailed description of the item"`
+	}
+}
+
+// Resolve rejects negative prices.
+func (i *ItemCreateInput) Resolve(huma.Context) []error {
+	return validatePrice(i.Body.Price)
+}
+
+// ItemGetInput for GET /items/{id}
+type ItemGetInput struct {
+	ID string `path:"id" maxLength:"64" pattern:"^item-[0-9a-z]+$" doc:"Item identifier" example:"item-001"`
+	DisplayParams
+	fields.Selection
+}
+
+// ItemUpdateInput for PATCH /items/{id}
+type ItemUpdateInput struct {
+	ID   string `path:"id" maxLength:"64" pattern:"^item-[0-9a-z]+$" doc:"Item identifier" example:"item-001"`
+	Body struct {
+		Name        *string      `json:"name,omitempty"        minLength:"1" maxLength:"100" pattern:"^\\S(?:.*\\S)?$" doc:"Display name"                     example:"Alpha Widget"`
+		Category    *string      `json:"category,omitempty"    enum:"electronics,tools,accessories,robotics,power,components"  doc:"Item category"                    example:"electronics"`
+		Price       *money.Money `json:"price,omitempty"       doc:"Price as a decimal amount with its ISO 4217 currency"`
+		InStock     *bool        `json:"inStock,omitempty"                                                                     doc:"Availability status"              example:"true"`
+		Description *string      `json:"description,omitempty" maxLength:"500"                                                 doc:"Detailed description of the item"`
+	}
+}
+
+// Resolve rejects negative prices.
+func (i *ItemUpdateInput) Resolve(huma.Context) []error {
+	if i.Body.Price == nil {
+		return nil
+	}
+	return validatePrice(*i.Body.Price)
+}
+
+// ItemDeleteInput for DELETE /items/{id}
+type ItemDeleteInput struct {
+	ID string `path:"id" maxLength:"64" pattern:"^item-[0-9a-z]+$" doc:"Item identifier" example:"item-001"`
+}
+
+// validatePrice rejects negative amounts. Money itself allows them, since refunds and discounts are
+// negative, but a catalog price never is.
+func validatePrice(price money.Money) []error {
+	if price.Minor() >= 0 {
+		return nil
+	}
+	return []error{&huma.ErrorDetail{
+		Location: "body.price.amount",
+		Message:  "price must not be negative",
+		Value:    price.Amount(),
+	}}
 }
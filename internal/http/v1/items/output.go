@@ -2,12 +2,31 @@ package items
 
 // ListData is the response body containing paginated items.
 type ListData struct {
-	Items []Item `json:"items" doc:"List of items"`
-	Total int    `json:"total" doc:"Total count of items matching the filter" example:"30"`
+	Items []Item `json:"items"           doc:"List of items"`
+	Total *int   `json:"total,omitempty" doc:"Number of items matching the filters, when requested with total=true" example:"30"`
 }
 
 // ItemsListOutput is the response wrapper with pagination Link header.
 type ItemsListOutput struct {
-	Link string `header:"Link" doc:"RFC 8288 pagination links"`
-	Body ListData
+	Link       string   `header:"Link"       doc:"RFC 8288 pagination links"`
+	Pagination string   `header:"Pagination" doc:"RFC 9651 dictionary of page metadata, such as limit=20, count=20, next, prev=?0"`
+	Vary       []string `header:"Vary"       doc:"Request headers that select the representation"`
+	Body       ListData
+}
+
+// ItemCreateOutput for POST /items (201 Created)
+type ItemCreateOutput struct {
+	Location string `header:"Location" doc:"URL of created item"`
+	Body     Item
+}
+
+// ItemGetOutput for GET /items/{id}
+type ItemGetOutput struct {
+	Vary []string `header:"Vary" doc:"Request headers that select the representation"`
+	Body Item
+}
+
+// ItemUpdateOutput for PATCH /items/{id}
+type ItemUpdateOutput struct {
+	Body Item
 }
@@ -3,27 +3,38 @@ package routes
 import (
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/janisto/huma-playground/internal/http/v1/batch"
 	githubhandler "github.com/janisto/huma-playground/internal/http/v1/github"
 	"github.com/janisto/huma-playground/internal/http/v1/hello"
 	"github.com/janisto/huma-playground/internal/http/v1/items"
 	"github.com/janisto/huma-playground/internal/http/v1/profile"
 	"github.com/janisto/huma-playground/internal/platform/auth"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
 	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
 	profilesvc "github.com/janisto/huma-playground/internal/service/profile"
 )
 
-// Register wires all HTTP routes into the provided API router.
+// Register wires all HTTP routes into the provided API router. Middlewares run after authentication,
+// so they can see the authenticated user.
 func Register(
 	api huma.API,
 	prefix string,
 	verifier auth.Verifier,
+	itemStore itemsvc.Store,
 	profileStore profilesvc.Store,
 	githubService githubsvc.Service,
+	activity *githubsvc.ActivityFeed,
+	cursors *pagination.Codec,
+	middlewares ...func(huma.Context, func(huma.Context)),
 ) {
 	api.UseMiddleware(auth.NewAuthMiddleware(api, verifier))
+	api.UseMiddleware(middlewares...)
 
 	hello.Register(api)
-	items.Register(api, prefix)
+	items.Register(api, prefix, itemStore, cursors)
 	profile.Register(api, prefix, profileStore)
-	githubhandler.Register(api, githubService, prefix)
+	githubhandler.Register(api, githubService, prefix, cursors)
+	githubhandler.RegisterActivityEvents(api, activity)
+	batch.Register(api, prefix)
 }
@@ -14,10 +14,13 @@ import (
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
 
 	"github.com/janisto/huma-playground/internal/platform/auth"
 	githubsvc "github.com/janisto/huma-playground/internal/service/github"
+	itemsvc "github.com/janisto/huma-playground/internal/service/items"
 	profilesvc "github.com/janisto/huma-playground/internal/service/profile"
+	"github.com/janisto/huma-playground/internal/testutil"
 )
 
 type stubVerifier struct {
@@ -68,6 +71,10 @@ func (mockGitHubService) ListActivity(
 	return &githubsvc.ActivityPage{Activities: []githubsvc.Activity{}}, nil
 }
 
+func (mockGitHubService) PollActivity(context.Context, string, string, string) (*githubsvc.ActivityPoll, error) {
+	return &githubsvc.ActivityPoll{Activities: []githubsvc.Activity{}}, nil
+}
+
 func (mockGitHubService) ListLanguages(context.Context, string, string) (map[string]int64, error) {
 	return map[string]int64{}, nil
 }
@@ -76,6 +83,73 @@ func (mockGitHubService) ListTags(context.Context, string, string) ([]githubsvc.
 	return []githubsvc.Tag{}, nil
 }
 
+func (mockGitHubService) ListReleases(
+	context.Context,
+	string,
+	string,
+	int,
+	string,
+) (*githubsvc.ReleasePage, error) {
+	return &githubsvc.ReleasePage{Releases: []githubsvc.Release{}}, nil
+}
+
+func (mockGitHubService) GetLatestRelease(context.Context, string, string) (*githubsvc.Release, error) {
+	return &githubsvc.Release{TagName: "v1.0.0", Assets: []githubsvc.ReleaseAsset{}}, nil
+}
+
+func (mockGitHubService) ListPullRequests(
+	context.Context,
+	string,
+	string,
+	githubsvc.PullRequestListParams,
+) (*githubsvc.PullRequestPage, error) {
+	return &githubsvc.PullRequestPage{PullRequests: []githubsvc.PullRequest{}}, nil
+}
+
+func (mockGitHubService) ListIssues(
+	context.Context,
+	string,
+	string,
+	githubsvc.IssueListParams,
+) (*githubsvc.IssuePage, error) {
+	return &githubsvc.IssuePage{Issues: []githubsvc.Issue{}}, nil
+}
+
+func (mockGitHubService) ListCommits(
+	context.Context,
+	string,
+	string,
+	githubsvc.CommitListParams,
+) (*githubsvc.CommitPage, error) {
+	return &githubsvc.CommitPage{Commits: []githubsvc.CommitSummary{}}, nil
+}
+
+func (mockGitHubService) CompareCommits(
+	context.Context,
+	string,
+	string,
+	string,
+	string,
+) (*githubsvc.Comparison, error) {
+	return &githubsvc.Comparison{
+		Status:  "identical",
+		Commits: []githubsvc.CommitSummary{},
+		Files:   []githubsvc.FileChange{},
+	}, nil
+}
+
+func (mockGitHubService) GetContents(context.Context, string, string, string, string) (*githubsvc.Content, error) {
+	return &githubsvc.Content{Type: githubsvc.ContentTypeDir}, nil
+}
+
+func (mockGitHubService) GetReadme(context.Context, string, string, string) (*githubsvc.Content, error) {
+	return &githubsvc.Content{Type: githubsvc.ContentTypeFile, Name: "README.md", Data: []byte("# Hello")}, nil
+}
+
+func (mockGitHubService) GetReleaseByTag(context.Context, string, string, string) (*githubsvc.Release, error) {
+	return &githubsvc.Release{TagName: "v1.0.0", Assets: []githubsvc.ReleaseAsset{}}, nil
+}
+
 func (m *mockProfileService) Create(
 	_ context.Context,
 	userID string,
@@ -135,7 +209,9 @@ func newTestRouter() chi.Router {
 	verifier := &stubVerifier{User: testUser()}
 	profileService := &mockProfileService{}
 	githubService := mockGitHubService{}
-	Register(api, "/v1", verifier, profileService, githubService)
+	itemStore := itemsvc.NewMemoryStore(itemsvc.SampleItems()...)
+	activity := githubsvc.NewActivityFeed(githubService, time.Minute, zap.NewNop())
+	Register(api, "/v1", verifier, itemStore, profileService, githubService, activity, testutil.Cursors())
 	return router
 }
 
@@ -165,6 +241,20 @@ func TestRegisterRoutesItems(t *testing.T) {
 	}
 }
 
+func TestRegisterRoutesItemWritesRequireAdmin(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/items/item-001", nil)
+	req.Header.Set(chimiddleware.RequestIDHeader, "routes-items-delete")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.Code)
+	}
+}
+
 func TestRegisterRoutesProfileGet(t *testing.T) {
 	router := newTestRouter()
 
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 
 	fbauth "firebase.google.com/go/v4/auth"
@@ -14,6 +15,14 @@ type FirebaseUser struct {
 	UID           string
 	Email         string
 	EmailVerified bool
+
+	// Roles come from the "roles" custom claim, set with the Admin SDK's SetCustomUserClaims.
+	Roles []string
+}
+
+// HasRole reports whether the user was granted role.
+func (u *FirebaseUser) HasRole(role string) bool {
+	return slices.Contains(u.Roles, role)
 }
 
 // Error types for authentication failures.
@@ -92,9 +101,22 @@ func (v *FirebaseVerifier) Verify(ctx context.Context, idToken string) (*Firebas
 		UID:           token.UID,
 		Email:         email,
 		EmailVerified: verified,
+		Roles:         rolesClaim(token.Claims["roles"]),
 	}, nil
 }
 
+// rolesClaim reads the roles custom claim, ignoring entries that are not strings.
+func rolesClaim(claim any) []string {
+	values, _ := claim.([]any)
+	roles := make([]string, 0, len(values))
+	for _, value := range values {
+		if role, ok := value.(string); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
 // ExtractBearerToken extracts the token from Authorization header.
 func ExtractBearerToken(header string) (string, error) {
 	if header == "" {
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"slices"
 	"testing"
 
 	firebase "firebase.google.com/go/v4"
@@ -130,6 +131,31 @@ func TestFirebaseUserFields(t *testing.T) {
 	}
 }
 
+func TestRolesClaim(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim any
+		want  []string
+	}{
+		{"missing", nil, []string{}},
+		{"not a list", "admin", []string{}},
+		{"strings", []any{"admin", "editor"}, []string{"admin", "editor"}},
+		{"mixed", []any{"admin", 1, "", true}, []string{"admin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rolesClaim(tt.claim); !slices.Equal(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	user := FirebaseUser{UID: "user-123", Roles: []string{RoleAdmin}}
+	if !user.HasRole(RoleAdmin) || user.HasRole("editor") {
+		t.Fatalf("unexpected HasRole results for %v", user.Roles)
+	}
+}
+
 func TestErrorTypes(t *testing.T) {
 	tests := []struct {
 		name string
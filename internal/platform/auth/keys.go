@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// IDTokenCertificatesURL serves the public keys that sign Firebase ID tokens. The Admin SDK fetches
+// them while verifying tokens.
+const IDTokenCertificatesURL = "https://www.googleapis.com/robot/v1/metadata/x509/securetoken@system.gserviceaccount.com"
+
+// KeyFetchChecker reports whether the keys needed to verify ID tokens can be fetched.
+type KeyFetchChecker struct {
+	client *http.Client
+	url    string
+}
+
+// NewKeyFetchChecker creates a checker that fetches url, normally IDTokenCertificatesURL. With the
+// Auth emulator, which does not sign tokens, pass the emulator origin to check its reachability.
+func NewKeyFetchChecker(client *http.Client, url string) *KeyFetchChecker {
+	return &KeyFetchChecker{client: client, url: url}
+}
+
+// Check fetches the keys and returns ErrCertificateFetch if they are not served.
+func (c *KeyFetchChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("creating key fetch request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Join(ErrCertificateFetch, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Join(ErrCertificateFetch, fmt.Errorf("unexpected status %d", resp.StatusCode))
+	}
+	return nil
+}
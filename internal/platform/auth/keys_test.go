@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestKeyFetchCheckerReportsAvailability(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+	checker := NewKeyFetchChecker(server.Client(), server.URL)
+
+	if err := checker.Check(t.Context()); err != nil {
+		t.Fatalf("expected keys to be available, got %v", err)
+	}
+	status.Store(http.StatusInternalServerError)
+	if err := checker.Check(t.Context()); !errors.Is(err, ErrCertificateFetch) {
+		t.Fatalf("expected ErrCertificateFetch, got %v", err)
+	}
+	server.Close()
+	if err := checker.Check(t.Context()); !errors.Is(err, ErrCertificateFetch) {
+		t.Fatalf("expected ErrCertificateFetch for unreachable keys, got %v", err)
+	}
+}
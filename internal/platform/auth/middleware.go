@@ -14,10 +14,12 @@ import (
 type userContextKey struct{}
 
 // NewAuthMiddleware creates Huma middleware for Firebase authentication.
-// It checks the operation's Security requirements and validates tokens.
+// It checks the operation's Security requirements, validates tokens, and rejects users without the
+// roles an operation requires with 403.
 func NewAuthMiddleware(api huma.API, verifier Verifier) func(huma.Context, func(huma.Context)) {
 	return func(ctx huma.Context, next func(huma.Context)) {
-		if !requiresBearerAuth(ctx.Operation().Security) {
+		roles, required := bearerRequirement(ctx.Operation().Security)
+		if !required {
 			next(ctx)
 			return
 		}
@@ -63,6 +65,14 @@ func NewAuthMiddleware(api huma.API, verifier Verifier) func(huma.Context, func(
 			writeAuthUnavailable(api, ctx)
 			return
 		}
+		for _, role := range roles {
+			if !user.HasRole(role) {
+				obs.Logger(ctx.Context()).Warn("auth failed: missing role",
+					zap.String("reason", "forbidden"), zap.String("role", role))
+				writeAuthError(api, ctx, http.StatusForbidden, "insufficient permissions")
+				return
+			}
+		}
 
 		ctx = huma.WithValue(ctx, userContextKey{}, user)
 		next(ctx)
@@ -82,13 +92,14 @@ func writeAuthUnavailable(api huma.API, ctx huma.Context) {
 	writeAuthError(api, ctx, http.StatusServiceUnavailable, "authentication service temporarily unavailable")
 }
 
-func requiresBearerAuth(requirements []map[string][]string) bool {
+// bearerRequirement reports whether requirements include the bearer scheme and which roles it requires.
+func bearerRequirement(requirements []map[string][]string) ([]string, bool) {
 	for _, requirement := range requirements {
-		if _, ok := requirement[BearerAuthScheme]; ok {
-			return true
+		if roles, ok := requirement[BearerAuthScheme]; ok {
+			return roles, true
 		}
 	}
-	return false
+	return nil, false
 }
 
 func writeAuthError(api huma.API, ctx huma.Context, status int, detail string) {
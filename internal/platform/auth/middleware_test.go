@@ -114,6 +114,45 @@ func TestRequireAuth(t *testing.T) {
 	}
 }
 
+func TestMiddlewareEnforcesRequiredRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		roles  []string
+		status int
+	}{
+		{"no roles", nil, http.StatusForbidden},
+		{"other role", []string{"editor"}, http.StatusForbidden},
+		{"required role", []string{"editor", RoleAdmin}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			api := humachi.New(router, huma.DefaultConfig("Test", "1.0.0"))
+			user := &FirebaseUser{UID: "role-user", Roles: tt.roles}
+			api.UseMiddleware(NewAuthMiddleware(api, &MockVerifier{User: user}))
+			huma.Register(api, huma.Operation{
+				OperationID: "admin-endpoint",
+				Method:      http.MethodGet,
+				Path:        "/admin",
+				Security:    RequireRole(RoleAdmin),
+			}, func(context.Context, *struct{}) (*testOutput, error) {
+				return &testOutput{}, nil
+			})
+
+			request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/admin", nil)
+			request.Header.Set("Authorization", "Bearer valid-token")
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+			if response.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, response.Code, response.Body.String())
+			}
+			if response.Header().Get("WWW-Authenticate") != "" {
+				t.Fatal("expected no WWW-Authenticate challenge for an authenticated user")
+			}
+		})
+	}
+}
+
 func TestRegisterSecurityScheme(t *testing.T) {
 	router := chi.NewRouter()
 	api := humachi.New(router, huma.DefaultConfig("Test", "1.0.0"))
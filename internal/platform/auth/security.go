@@ -4,11 +4,26 @@ import "github.com/danielgtaylor/huma/v2"
 
 const BearerAuthScheme = "bearerAuth"
 
+// RoleAdmin is the role that may change shared resources such as the item catalog.
+const RoleAdmin = "admin"
+
 // RequireAuth returns Huma operation security requirements for Firebase bearer tokens.
 func RequireAuth() []map[string][]string {
 	return []map[string][]string{{BearerAuthScheme: {}}}
 }
 
+// RequireRole returns security requirements for a Firebase bearer token whose user holds role. The
+// role is listed as the requirement's scope, which OpenAPI 3.1 allows for non-OAuth schemes.
+func RequireRole(role string) []map[string][]string {
+	return []map[string][]string{{BearerAuthScheme: {role}}}
+}
+
+// IsProtected reports whether op requires a Firebase bearer token.
+func IsProtected(op *huma.Operation) bool {
+	_, required := bearerRequirement(op.Security)
+	return required
+}
+
 // RegisterSecurityScheme adds the Firebase bearer-token security scheme to OpenAPI.
 func RegisterSecurityScheme(api huma.API) {
 	openAPI := api.OpenAPI()
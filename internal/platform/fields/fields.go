@@ -0,0 +1,253 @@
+// Package fields implements sparse fieldsets: a fields query parameter that trims successful JSON and
+// CBOR response bodies to the requested record properties.
+package fields
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+const (
+	// PathKey is the huma.Operation Metadata key for the JSON property of the response body that
+	// holds the records fields select from, or "" when the body is itself the record. Operations
+	// with it must embed Selection in their input, and operations that embed Selection must set it.
+	PathKey = "fields.path"
+
+	// allowedKey is the Metadata key Register stores the selectable field names under.
+	allowedKey = "fields.allowed"
+
+	// schemaProperty is added to bodies by Huma's schema link transformer. Pruned bodies drop it,
+	// since they no longer match the schema it names.
+	schemaProperty = "$schema"
+)
+
+// Selection embeds into Huma input structs of operations that select fields.
+type Selection struct {
+	Fields []string `query:"fields" doc:"Comma-separated record fields to return instead of the full representation" example:"id,name" maxItems:"32"`
+}
+
+// Resolve rejects fields the operation's records do not have.
+func (s *Selection) Resolve(ctx huma.Context) []error {
+	allowed, _ := ctx.Operation().Metadata[allowedKey].([]string)
+	var errs []error
+	for _, name := range s.Fields {
+		if !slices.Contains(allowed, name) {
+			errs = append(errs, &huma.ErrorDetail{
+				Location: "query.fields",
+				Message:  "unknown field; expected one of: " + strings.Join(allowed, ", "),
+				Value:    name,
+			})
+		}
+	}
+	return errs
+}
+
+// SetQuery adds the selection to query, so links to other pages keep it.
+func (s Selection) SetQuery(query url.Values) {
+	if len(s.Fields) > 0 {
+		query.Set("fields", strings.Join(slices.Compact(slices.Sorted(slices.Values(s.Fields))), ","))
+	}
+}
+
+// Record returns record trimmed to the selected fields, or record itself when none are selected. It is
+// for records written outside the response body, such as streamed ones.
+func (s Selection) Record(record any) any {
+	if len(s.Fields) == 0 {
+		return record
+	}
+	return prune(record, "", s.Fields)
+}
+
+// Register validates and documents the fields parameter of every operation with a PathKey. Call it
+// before operations are registered, and add Transform to the API's Transformers.
+func Register(api huma.API) {
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		var param *huma.Param
+		for _, p := range op.Parameters {
+			if p.In == "query" && p.Name == "fields" {
+				param = p
+			}
+		}
+		value, selects := op.Metadata[PathKey]
+		if param == nil && !selects {
+			return
+		}
+		if param == nil || !selects {
+			panic(fmt.Sprintf("%s %s: fields.Selection and %s must be used together", op.Method, op.Path, PathKey))
+		}
+		path, ok := value.(string)
+		if !ok {
+			panic(fmt.Sprintf("%s %s: %s must be a string, got %T", op.Method, op.Path, PathKey, value))
+		}
+		allowed := recordFields(oapi, op, path)
+		op.Metadata[allowedKey] = allowed
+
+		// The parameter's own schema also validates requests, so the documented enum goes on a copy.
+		enum := make([]any, len(allowed))
+		for i, name := range allowed {
+			enum[i] = name
+		}
+		items := *param.Schema.Items
+		items.Enum = enum
+		schema := *param.Schema
+		schema.Items = &items
+		param.Schema = &schema
+	})
+}
+
+// recordFields returns the sorted property names of the records in the operation's 200 response.
+func recordFields(oapi *huma.OpenAPI, op *huma.Operation, path string) []string {
+	registry := oapi.Components.Schemas
+	resolve := func(schema *huma.Schema) *huma.Schema {
+		if schema != nil && schema.Ref != "" {
+			return registry.SchemaFromRef(schema.Ref)
+		}
+		return schema
+	}
+	var schema *huma.Schema
+	if response := op.Responses["200"]; response != nil {
+		if content := response.Content["application/json"]; content != nil {
+			schema = resolve(content.Schema)
+		}
+	}
+	if schema != nil && path != "" {
+		schema = schema.Properties[path]
+		if schema != nil {
+			schema = resolve(schema.Items)
+		}
+	}
+	if schema == nil || len(schema.Properties) == 0 {
+		panic(fmt.Sprintf("%s %s: no records with properties at %q in the 200 response", op.Method, op.Path, path))
+	}
+	var names []string
+	for name := range schema.Properties {
+		if name != schemaProperty {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Transform trims successful response bodies of operations with a PathKey to the fields requested.
+// Add it to the API's Transformers after Huma's defaults.
+func Transform(ctx huma.Context, status string, v any) (any, error) {
+	op := ctx.Operation()
+	if op == nil || !strings.HasPrefix(status, "2") {
+		return v, nil
+	}
+	path, ok := op.Metadata[PathKey].(string)
+	raw := ctx.Query("fields")
+	if !ok || raw == "" {
+		return v, nil
+	}
+	// Huma splits the parameter the same way, and Selection.Resolve has already checked each name.
+	return prune(v, path, strings.Split(raw, ",")), nil
+}
+
+// prune returns v as a map holding only the selected properties, or, with a path, v with the records
+// in that property pruned. Values keep their own types, so they encode as before in JSON and CBOR.
+func prune(v any, path string, selected []string) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return v
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return v
+	}
+	// An addressable copy lets fields with pointer-receiver marshalers be encoded through a pointer.
+	addressable := reflect.New(rv.Type()).Elem()
+	addressable.Set(rv)
+
+	keep := func(name string) bool { return slices.Contains(selected, name) }
+	if path == "" {
+		return properties(addressable, keep)
+	}
+	body := properties(addressable, func(name string) bool { return name != schemaProperty })
+	if records, ok := body[path]; ok {
+		list := reflect.ValueOf(records).Elem()
+		if list.Kind() == reflect.Slice && !list.IsNil() {
+			pruned := make([]map[string]any, list.Len())
+			for i := range pruned {
+				pruned[i] = properties(reflect.Indirect(list.Index(i)), keep)
+			}
+			body[path] = pruned
+		}
+	}
+	return body
+}
+
+// properties collects the JSON properties of the addressable struct rv that keep accepts, following
+// encoding/json's rules for names, embedded structs, and omitempty.
+func properties(rv reflect.Value, keep func(name string) bool) map[string]any {
+	out := map[string]any{}
+	collect(rv, keep, out)
+	return out
+}
+
+func collect(rv reflect.Value, keep func(name string) bool, out map[string]any) {
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	for i := range rv.NumField() {
+		field := rv.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		value := rv.Field(i)
+		if field.Anonymous && name == "" {
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				collect(value, keep, out)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if !keep(name) {
+			continue
+		}
+		options := strings.Split(opts, ",")
+		if slices.Contains(options, "omitempty") && isEmpty(value) ||
+			slices.Contains(options, "omitzero") && value.IsZero() {
+			continue
+		}
+		out[name] = value.Addr().Interface()
+	}
+}
+
+// isEmpty reports whether encoding/json's omitempty would omit v.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
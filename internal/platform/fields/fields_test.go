@@ -0,0 +1,268 @@
+package fields
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	_ "github.com/danielgtaylor/huma/v2/formats/cbor"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-chi/chi/v5"
+)
+
+// label has pointer-receiver marshalers, which pruned bodies must still use.
+type label struct {
+	text string
+}
+
+func (l *label) MarshalJSON() ([]byte, error) {
+	return json.Marshal("label:" + l.text)
+}
+
+func (l *label) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal("label:" + l.text)
+}
+
+// Audit is embedded in record, so its fields are promoted into record's properties.
+type Audit struct {
+	Revision int `json:"revision"`
+}
+
+type record struct {
+	Audit
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Note    string  `json:"note,omitempty"`
+	Price   int64   `json:"price"`
+	Label   label   `json:"label"`
+	Parent  *string `json:"parent"`
+	private string
+}
+
+type listBody struct {
+	Records []record `json:"records"`
+	Count   int      `json:"count"`
+}
+
+type getInput struct {
+	Selection
+}
+
+type listInput struct {
+	Selection
+}
+
+func testRecords() []record {
+	return []record{
+		{Audit: Audit{Revision: 2}, ID: "r1", Name: "One", Price: 100, Label: label{"a"}, private: "x"},
+		{Audit: Audit{Revision: 1}, ID: "r2", Name: "Two", Note: "kept", Price: 200, Label: label{"b"}},
+	}
+}
+
+func newTestAPI(t *testing.T) http.Handler {
+	t.Helper()
+	router := chi.NewRouter()
+	config := huma.DefaultConfig("FieldsTest", "test")
+	config.RejectUnknownQueryParameters = true
+	config.Transformers = append(config.Transformers, Transform)
+	api := humachi.New(router, config)
+	Register(api)
+	huma.Register(api, huma.Operation{
+		OperationID: "get-record",
+		Method:      http.MethodGet,
+		Path:        "/records/r1",
+		Metadata:    map[string]any{PathKey: ""},
+	}, func(context.Context, *getInput) (*struct{ Body record }, error) {
+		return &struct{ Body record }{Body: testRecords()[0]}, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "list-records",
+		Method:      http.MethodGet,
+		Path:        "/records",
+		Metadata:    map[string]any{PathKey: "records"},
+	}, func(context.Context, *listInput) (*struct{ Body listBody }, error) {
+		return &struct{ Body listBody }{Body: listBody{Records: testRecords(), Count: 2}}, nil
+	})
+	return router
+}
+
+func get(t *testing.T, handler http.Handler, path, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	req.Header.Set("Accept", accept)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected 200, got %d: %s", path, resp.Code, resp.Body.String())
+	}
+	return resp
+}
+
+func TestSelectedFieldsOfRecord(t *testing.T) {
+	resp := get(t, newTestAPI(t), "/records/r1?fields=id,label,revision", "application/json")
+
+	want := `{"id":"r1","label":"label:a","revision":2}`
+	if got := strings.TrimSpace(resp.Body.String()); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestSelectedFieldsOfRecordList(t *testing.T) {
+	resp := get(t, newTestAPI(t), "/records?fields=note,id,parent", "application/json")
+
+	want := `{"count":2,"records":[{"id":"r1","parent":null},{"id":"r2","note":"kept","parent":null}]}`
+	if got := strings.TrimSpace(resp.Body.String()); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestSelectedFieldsCBOR(t *testing.T) {
+	resp := get(t, newTestAPI(t), "/records?fields=price,label", "application/cbor")
+
+	var body struct {
+		Count   int              `cbor:"count"`
+		Records []map[string]any `cbor:"records"`
+	}
+	if err := cbor.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("cbor unmarshal: %v", err)
+	}
+	want := []map[string]any{
+		{"label": "label:a", "price": uint64(100)},
+		{"label": "label:b", "price": uint64(200)},
+	}
+	if body.Count != 2 || !reflect.DeepEqual(body.Records, want) {
+		t.Fatalf("expected count 2 and %v, got %d and %v", want, body.Count, body.Records)
+	}
+}
+
+func TestNoSelectionKeepsFullBody(t *testing.T) {
+	resp := get(t, newTestAPI(t), "/records/r1", "application/json")
+
+	var body map[string]any
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	for _, name := range []string{"$schema", "id", "name", "price", "label", "parent", "revision"} {
+		if _, ok := body[name]; !ok {
+			t.Errorf("expected %s in the full body, got %v", name, body)
+		}
+	}
+}
+
+func TestUnknownFieldIsRejected(t *testing.T) {
+	handler := newTestAPI(t)
+	for _, path := range []string{"/records/r1?fields=id,secret", "/records?fields=count", "/records?fields=private"} {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		if resp.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("GET %s: expected 422, got %d: %s", path, resp.Code, resp.Body.String())
+		}
+		var problem huma.ErrorModel
+		if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("json unmarshal: %v", err)
+		}
+		if len(problem.Errors) != 1 || problem.Errors[0].Location != "query.fields" {
+			t.Fatalf("GET %s: expected one query.fields error, got %+v", path, problem.Errors)
+		}
+	}
+}
+
+func TestRegisterDocumentsSelectableFields(t *testing.T) {
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("FieldsTest", "test"))
+	Register(api)
+	huma.Register(api, huma.Operation{
+		OperationID: "list-records",
+		Method:      http.MethodGet,
+		Path:        "/records",
+		Metadata:    map[string]any{PathKey: "records"},
+	}, func(context.Context, *listInput) (*struct{ Body listBody }, error) { return nil, nil })
+
+	var param *huma.Param
+	for _, p := range api.OpenAPI().Paths["/records"].Get.Parameters {
+		if p.Name == "fields" {
+			param = p
+		}
+	}
+	if param == nil {
+		t.Fatal("expected a fields parameter")
+	}
+	want := []any{"id", "label", "name", "note", "parent", "price", "revision"}
+	if !slices.Equal(param.Schema.Items.Enum, want) {
+		t.Fatalf("expected enum %v, got %v", want, param.Schema.Items.Enum)
+	}
+}
+
+func TestRegisterRequiresSelectionAndPathTogether(t *testing.T) {
+	for name, register := range map[string]func(huma.API){
+		"path without selection": func(api huma.API) {
+			huma.Register(api, huma.Operation{
+				OperationID: "get-record",
+				Method:      http.MethodGet,
+				Path:        "/records/r1",
+				Metadata:    map[string]any{PathKey: ""},
+			}, func(context.Context, *struct{}) (*struct{ Body record }, error) { return nil, nil })
+		},
+		"selection without path": func(api huma.API) {
+			huma.Register(api, huma.Operation{
+				OperationID: "get-record",
+				Method:      http.MethodGet,
+				Path:        "/records/r1",
+			}, func(context.Context, *getInput) (*struct{ Body record }, error) { return nil, nil })
+		},
+		"path without records": func(api huma.API) {
+			huma.Register(api, huma.Operation{
+				OperationID: "get-record",
+				Method:      http.MethodGet,
+				Path:        "/records/r1",
+				Metadata:    map[string]any{PathKey: "records"},
+			}, func(context.Context, *getInput) (*struct{ Body record }, error) { return nil, nil })
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := humachi.New(chi.NewRouter(), huma.DefaultConfig("FieldsTest", "test"))
+			Register(api)
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected registration to panic")
+				}
+			}()
+			register(api)
+		})
+	}
+}
+
+func TestSelectionSetQueryAndRecord(t *testing.T) {
+	selection := Selection{Fields: []string{"name", "id", "name"}}
+
+	query := url.Values{}
+	selection.SetQuery(query)
+	if got := query.Get("fields"); got != "id,name" {
+		t.Fatalf("expected fields=id,name, got %q", got)
+	}
+	Selection{}.SetQuery(query)
+	if got := query.Get("fields"); got != "id,name" {
+		t.Fatalf("expected an empty selection to leave the query alone, got %q", got)
+	}
+
+	data, err := json.Marshal(selection.Record(testRecords()[1]))
+	if err != nil {
+		t.Fatalf("json marshal: %v", err)
+	}
+	if got := string(data); got != `{"id":"r2","name":"Two"}` {
+		t.Fatalf(`expected {"id":"r2","name":"Two"}, got %s`, got)
+	}
+	if got := (Selection{}).Record(testRecords()[0]); !reflect.DeepEqual(got, testRecords()[0]) {
+		t.Fatalf("expected an empty selection to return the record, got %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const idempotencyCollection = "idempotency_keys"
+
+// firestoreRecord maps to the Firestore document structure. A Firestore TTL policy on expires_at
+// deletes expired keys; until it runs, Reserve treats them as absent.
+type firestoreRecord struct {
+	Fingerprint string              `firestore:"fingerprint"`
+	Completed   bool                `firestore:"completed"`
+	Status      int                 `firestore:"status"`
+	Header      map[string][]string `firestore:"header"`
+	Body        []byte              `firestore:"body"`
+	ExpiresAt   time.Time           `firestore:"expires_at"`
+}
+
+// FirestoreStore implements Store using Firestore, so keys are shared by every instance.
+type FirestoreStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreStore creates a new Firestore-backed store.
+func NewFirestoreStore(client *firestore.Client) *FirestoreStore {
+	return &FirestoreStore{client: client}
+}
+
+// Reserve claims key in a transaction unless an unexpired record exists.
+func (s *FirestoreStore) Reserve(ctx context.Context, key string, pending Record) (Record, bool, error) {
+	docRef := s.doc(key)
+	var existing Record
+	var reserved bool
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		existing, reserved = Record{}, false
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var fr firestoreRecord
+			if err := doc.DataTo(&fr); err != nil {
+				return err
+			}
+			if time.Now().Before(fr.ExpiresAt) {
+				existing = fromFirestore(fr)
+				return nil
+			}
+		}
+		reserved = true
+		return tx.Set(docRef, toFirestore(pending))
+	})
+	if err != nil {
+		return Record{}, false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	return existing, reserved, nil
+}
+
+// Complete stores the response for key.
+func (s *FirestoreStore) Complete(ctx context.Context, key string, record Record) error {
+	if _, err := s.doc(key).Set(ctx, toFirestore(record)); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release removes key.
+func (s *FirestoreStore) Release(ctx context.Context, key string) error {
+	if _, err := s.doc(key).Delete(ctx); err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// doc hashes key into a document ID because client keys may contain characters Firestore IDs reject.
+func (s *FirestoreStore) doc(key string) *firestore.DocumentRef {
+	sum := sha256.Sum256([]byte(key))
+	return s.client.Collection(idempotencyCollection).Doc(hex.EncodeToString(sum[:]))
+}
+
+func toFirestore(r Record) firestoreRecord {
+	return firestoreRecord{
+		Fingerprint: r.Fingerprint,
+		Completed:   r.Completed,
+		Status:      r.Status,
+		Header:      r.Header,
+		Body:        r.Body,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
+
+func fromFirestore(fr firestoreRecord) Record {
+	return Record{
+		Fingerprint: fr.Fingerprint,
+		Completed:   fr.Completed,
+		Status:      fr.Status,
+		Header:      http.Header(fr.Header),
+		Body:        fr.Body,
+		ExpiresAt:   fr.ExpiresAt,
+	}
+}
@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/janisto/huma-playground/internal/testutil"
+)
+
+func setupFirestoreTest(t *testing.T) *FirestoreStore {
+	t.Helper()
+
+	testutil.SkipIfEmulatorUnavailable(t)
+	testutil.SetupEmulator(t)
+	testutil.ClearFirestore(t)
+
+	client, err := firestore.NewClient(t.Context(), testutil.ProjectID)
+	if err != nil {
+		t.Fatalf("failed to create Firestore client: %v", err)
+	}
+	t.Cleanup(func() {
+		testutil.ClearFirestore(t)
+		if err := client.Close(); err != nil {
+			t.Errorf("close Firestore client: %v", err)
+		}
+	})
+	return NewFirestoreStore(client)
+}
+
+func TestFirestoreStoreLifecycle(t *testing.T) {
+	store := setupFirestoreTest(t)
+	ctx := t.Context()
+	key := "user-1|key/with/slashes"
+	pending := Record{Fingerprint: "a", ExpiresAt: time.Now().Add(time.Minute)}
+
+	if _, reserved, err := store.Reserve(ctx, key, pending); err != nil || !reserved {
+		t.Fatalf("expected reservation, got reserved=%v err=%v", reserved, err)
+	}
+	existing, reserved, err := store.Reserve(ctx, key, pending)
+	if err != nil || reserved || existing.Completed {
+		t.Fatalf("expected pending record, got reserved=%v err=%v %#v", reserved, err, existing)
+	}
+
+	record := Record{
+		Fingerprint: "a",
+		Completed:   true,
+		Status:      http.StatusCreated,
+		Header:      http.Header{"Location": {"/v1/profile"}},
+		Body:        []byte(`{"id":"user-1"}`),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	if err := store.Complete(ctx, key, record); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	existing, reserved, err = store.Reserve(ctx, key, pending)
+	if err != nil || reserved {
+		t.Fatalf("expected completed record, got reserved=%v err=%v", reserved, err)
+	}
+	if existing.Status != http.StatusCreated || existing.Header.Get("Location") != "/v1/profile" ||
+		string(existing.Body) != `{"id":"user-1"}` {
+		t.Fatalf("unexpected record %#v", existing)
+	}
+
+	if err := store.Release(ctx, key); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, reserved, err := store.Reserve(ctx, key, pending); err != nil || !reserved {
+		t.Fatalf("expected released key to be reservable, got reserved=%v err=%v", reserved, err)
+	}
+}
@@ -0,0 +1,280 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
+
+	"github.com/janisto/huma-playground/internal/platform/auth"
+)
+
+const (
+	// HeaderName is the request header carrying the client's key.
+	HeaderName = "Idempotency-Key"
+	// ReplayedHeader marks a response replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// Replayer makes authenticated POST operations safe to retry with an Idempotency-Key header.
+type Replayer struct {
+	store   Store
+	ttl     time.Duration
+	lockTTL func(*huma.Operation) time.Duration
+	now     func() time.Time
+}
+
+// New creates a replayer that keeps responses in store for ttl. lockTTL returns how long an
+// in-flight request to an operation holds its key and should exceed that operation's timeout, so a
+// crashed instance cannot block a key forever.
+func New(store Store, ttl time.Duration, lockTTL func(*huma.Operation) time.Duration) *Replayer {
+	return &Replayer{store: store, ttl: ttl, lockTTL: lockTTL, now: time.Now}
+}
+
+// Middleware stores the first response to each authenticated POST carrying an Idempotency-Key and
+// replays its status, headers, and negotiated body for retries. Keys are scoped to the Firebase UID,
+// so it must run after authentication. A retry whose method, path, query, content type, Accept header,
+// or body differs gets 422, since the stored body was negotiated for the first request's Accept, and a
+// retry while the first request is in flight gets 409.
+//
+// Server errors, timeouts, and 429s are not stored, so the client may retry them with the same key.
+// If the store fails, the request is rejected with 503 rather than risk a duplicate write.
+func (r *Replayer) Middleware(api huma.API) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		key := ctx.Header(HeaderName)
+		user := auth.UserFromContext(ctx.Context())
+		if key == "" || user == nil || !applies(ctx.Operation()) {
+			next(ctx)
+			return
+		}
+		if !validKey(key) {
+			writeErr(api, ctx, http.StatusBadRequest, "Idempotency-Key must be 1-255 printable ASCII characters")
+			return
+		}
+
+		source := ctx.BodyReader()
+		if source == nil {
+			source = http.NoBody
+		}
+		reader := source
+		maxBytes := ctx.Operation().MaxBodyBytes
+		if maxBytes > 0 {
+			// One byte past the limit tells an oversized body from one that fits exactly.
+			reader = io.LimitReader(source, maxBytes+1)
+		}
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			// Let Huma report the read error exactly as it would without the header.
+			next(&recordingContext{humaContext: ctx, body: io.MultiReader(bytes.NewReader(body), errReader{err})})
+			return
+		}
+		if maxBytes > 0 && int64(len(body)) > maxBytes {
+			// Huma answers 413 as it would without the header, and the key stays unused.
+			next(&recordingContext{humaContext: ctx, body: io.MultiReader(bytes.NewReader(body), source)})
+			return
+		}
+
+		storeKey := user.UID + "|" + key
+		pending := Record{Fingerprint: fingerprint(ctx, body), ExpiresAt: r.now().Add(r.lockTTL(ctx.Operation()))}
+		existing, reserved, err := r.store.Reserve(ctx.Context(), storeKey, pending)
+		if err != nil {
+			obs.Logger(ctx.Context()).Warn("idempotency store failed; rejecting request", zap.Error(err))
+			writeErr(api, ctx, http.StatusServiceUnavailable, "idempotency service temporarily unavailable")
+			return
+		}
+		if !reserved {
+			switch {
+			case existing.Fingerprint != pending.Fingerprint:
+				writeErr(api, ctx, http.StatusUnprocessableEntity,
+					"Idempotency-Key was already used with a different request")
+			case !existing.Completed:
+				writeErr(api, ctx, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+			default:
+				replay(ctx, existing)
+			}
+			return
+		}
+
+		// The request context may be done by the time the response is stored.
+		storeCtx := context.WithoutCancel(ctx.Context())
+		recorder := &recordingContext{humaContext: ctx, body: bytes.NewReader(body), header: http.Header{}}
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if err := r.store.Release(storeCtx, storeKey); err != nil {
+				obs.Logger(ctx.Context()).Warn("release idempotency key", zap.Error(err))
+			}
+		}()
+
+		next(recorder)
+
+		status := ctx.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if !storable(status) {
+			return
+		}
+		stored = true
+		if err := r.store.Complete(storeCtx, storeKey, Record{
+			Fingerprint: pending.Fingerprint,
+			Completed:   true,
+			Status:      status,
+			Header:      recorder.header,
+			Body:        recorder.buf.Bytes(),
+			ExpiresAt:   r.now().Add(r.ttl),
+		}); err != nil {
+			obs.Logger(ctx.Context()).Warn("store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// DocumentRequests adds the Idempotency-Key header and its 409 and 422 responses to every operation
+// the replayer applies to. Call it before operations are registered.
+func (r *Replayer) DocumentRequests(api huma.API) {
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if !applies(op) {
+			return
+		}
+		op.Parameters = append(op.Parameters, &huma.Param{
+			Name: HeaderName,
+			In:   "header",
+			Description: "Client-generated key, up to 255 printable ASCII characters, that makes retries " +
+				"replay the first response.",
+			Schema: &huma.Schema{Type: huma.TypeString},
+		})
+		if op.Responses == nil {
+			op.Responses = map[string]*huma.Response{}
+		}
+		for _, code := range []int{http.StatusConflict, http.StatusUnprocessableEntity} {
+			status := strconv.Itoa(code)
+			if op.Responses[status] != nil {
+				continue
+			}
+			errType := reflect.TypeOf(huma.NewError(code, ""))
+			op.Responses[status] = &huma.Response{
+				Description: http.StatusText(code),
+				Content: map[string]*huma.MediaType{
+					"application/problem+json": {Schema: oapi.Components.Schemas.Schema(errType, true, "")},
+				},
+			}
+		}
+	})
+}
+
+// applies reports whether op is an authenticated POST, the only operations keys are honored on.
+func applies(op *huma.Operation) bool {
+	return op.Method == http.MethodPost && len(op.Security) > 0
+}
+
+// storable reports whether a response is final; transient failures must remain retryable.
+func storable(status int) bool {
+	return status < http.StatusInternalServerError &&
+		status != http.StatusRequestTimeout &&
+		status != http.StatusTooManyRequests
+}
+
+func validKey(key string) bool {
+	if len(key) > maxKeyLength {
+		return false
+	}
+	for i := range len(key) {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// fingerprint identifies the request a key was first used with.
+func fingerprint(ctx huma.Context, body []byte) string {
+	u := ctx.URL()
+	h := sha256.New()
+	for _, part := range []string{ctx.Method(), u.Path, u.RawQuery, ctx.Header("Content-Type"), ctx.Header("Accept")} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(ctx huma.Context, record Record) {
+	for name, values := range record.Header {
+		for _, value := range values {
+			ctx.AppendHeader(name, value)
+		}
+	}
+	ctx.SetHeader(ReplayedHeader, "true")
+	ctx.SetStatus(record.Status)
+	if _, err := ctx.BodyWriter().Write(record.Body); err != nil {
+		obs.Logger(ctx.Context()).Warn("write replayed response", zap.Error(err))
+	}
+}
+
+func writeErr(api huma.API, ctx huma.Context, status int, detail string) {
+	if err := huma.WriteErr(api, ctx, status, detail); err != nil {
+		obs.Logger(ctx.Context()).Error("write idempotency error", zap.Error(err))
+	}
+}
+
+// humaContext lets recordingContext embed huma.Context without the embedded field name colliding
+// with the interface's Context method.
+type humaContext = huma.Context
+
+// recordingContext replays the buffered request body to the handler and captures the headers and
+// body it writes.
+type recordingContext struct {
+	humaContext
+	body   io.Reader
+	header http.Header
+	buf    bytes.Buffer
+}
+
+func (c *recordingContext) BodyReader() io.Reader {
+	return c.body
+}
+
+func (c *recordingContext) SetHeader(name, value string) {
+	if c.header != nil {
+		c.header.Set(name, value)
+	}
+	c.humaContext.SetHeader(name, value)
+}
+
+func (c *recordingContext) AppendHeader(name, value string) {
+	if c.header != nil {
+		c.header.Add(name, value)
+	}
+	c.humaContext.AppendHeader(name, value)
+}
+
+func (c *recordingContext) BodyWriter() io.Writer {
+	return io.MultiWriter(c.humaContext.BodyWriter(), &c.buf)
+}
+
+// Unwrap lets adapter helpers such as humachi.Unwrap reach the router context.
+func (c *recordingContext) Unwrap() huma.Context {
+	return c.humaContext
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
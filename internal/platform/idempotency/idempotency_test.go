@@ -0,0 +1,344 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5"
+
+	"github.com/janisto/huma-playground/internal/platform/auth"
+)
+
+type createInput struct {
+	Body struct {
+		Name string `json:"name"`
+	}
+}
+
+type createOutput struct {
+	Location string `header:"Location"`
+	Body     struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Reserve(context.Context, string, Record) (Record, bool, error) {
+	return Record{}, false, errors.New("store unavailable")
+}
+
+func (failingStore) Complete(context.Context, string, Record) error {
+	return nil
+}
+
+func (failingStore) Release(context.Context, string) error {
+	return nil
+}
+
+// tokenVerifier accepts any token and uses it as the UID.
+type tokenVerifier struct{}
+
+func (tokenVerifier) Verify(_ context.Context, token string) (*auth.FirebaseUser, error) {
+	return &auth.FirebaseUser{UID: token}, nil
+}
+
+type testAPI struct {
+	handler http.Handler
+	api     huma.API
+	calls   atomic.Int64
+	// release, when set, blocks the handler until it is closed.
+	release chan struct{}
+	started chan struct{}
+	status  atomic.Int64
+	// lockedOperation is the operation the replayer last asked for a lock TTL.
+	lockedOperation atomic.Value
+}
+
+func newTestAPI(store Store) *testAPI {
+	ta := &testAPI{}
+	router := chi.NewRouter()
+	ta.api = humachi.New(router, huma.DefaultConfig("IdempotencyTest", "test"))
+	replayer := New(store, time.Hour, func(op *huma.Operation) time.Duration {
+		ta.lockedOperation.Store(op.OperationID)
+		return time.Minute
+	})
+	replayer.DocumentRequests(ta.api)
+	ta.api.UseMiddleware(auth.NewAuthMiddleware(ta.api, tokenVerifier{}), replayer.Middleware(ta.api))
+	huma.Register(ta.api, huma.Operation{
+		OperationID:   "create-thing",
+		Method:        http.MethodPost,
+		Path:          "/things",
+		DefaultStatus: http.StatusCreated,
+		MaxBodyBytes:  64,
+		Security:      []map[string][]string{{auth.BearerAuthScheme: {}}},
+	}, func(_ context.Context, input *createInput) (*createOutput, error) {
+		if ta.started != nil {
+			ta.started <- struct{}{}
+			<-ta.release
+		}
+		n := ta.calls.Add(1)
+		if status := ta.status.Load(); status != 0 {
+			return nil, huma.NewError(int(status), "failed")
+		}
+		out := &createOutput{Location: "/things/1"}
+		out.Body.ID = n
+		out.Body.Name = input.Body.Name
+		return out, nil
+	})
+	ta.handler = router
+	return ta
+}
+
+func (ta *testAPI) post(t *testing.T, key, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/things", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer user-1")
+	if key != "" {
+		req.Header.Set(HeaderName, key)
+	}
+	resp := httptest.NewRecorder()
+	ta.handler.ServeHTTP(resp, req)
+	return resp
+}
+
+func decodeProblem(t *testing.T, resp *httptest.ResponseRecorder) huma.ErrorModel {
+	t.Helper()
+	var problem huma.ErrorModel
+	if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	return problem
+}
+
+func TestMiddlewareReplaysFirstResponse(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	first := ta.post(t, "key-1", `{"name":"a"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", first.Code, first.Body.String())
+	}
+	retry := ta.post(t, "key-1", `{"name":"a"}`)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("expected replayed 201, got %d: %s", retry.Code, retry.Body.String())
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("expected replayed body %q, got %q", first.Body.String(), retry.Body.String())
+	}
+	if got := retry.Header().Get("Location"); got != "/things/1" {
+		t.Errorf("expected replayed Location, got %q", got)
+	}
+	if got := retry.Header().Get("Content-Type"); got != first.Header().Get("Content-Type") {
+		t.Errorf("expected replayed Content-Type, got %q", got)
+	}
+	if retry.Header().Get(ReplayedHeader) != "true" || first.Header().Get(ReplayedHeader) != "" {
+		t.Error("expected only the retry to be marked as replayed")
+	}
+	if ta.calls.Load() != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", ta.calls.Load())
+	}
+}
+
+// reservingStore records the pending records reserved in a MemoryStore.
+type reservingStore struct {
+	*MemoryStore
+	reserved []Record
+}
+
+func (s *reservingStore) Reserve(ctx context.Context, key string, pending Record) (Record, bool, error) {
+	s.reserved = append(s.reserved, pending)
+	return s.MemoryStore.Reserve(ctx, key, pending)
+}
+
+func TestMiddlewareLocksKeyForOperationLockTTL(t *testing.T) {
+	store := &reservingStore{MemoryStore: NewMemoryStore()}
+	ta := newTestAPI(store)
+
+	before := time.Now()
+	if resp := ta.post(t, "key-1", `{"name":"a"}`); resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	after := time.Now()
+	if got := ta.lockedOperation.Load(); got != "create-thing" {
+		t.Fatalf("expected the lock TTL of create-thing, got %v", got)
+	}
+	if len(store.reserved) != 1 {
+		t.Fatalf("expected one reservation, got %d", len(store.reserved))
+	}
+	if expires := store.reserved[0].ExpiresAt; expires.Before(before.Add(time.Minute)) ||
+		expires.After(after.Add(time.Minute)) {
+		t.Fatalf("expected the lock to expire a minute after the request, got %s", expires.Sub(before))
+	}
+}
+
+func TestMiddlewareRejectsKeyReuseWithDifferentBody(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	ta.post(t, "key-1", `{"name":"a"}`)
+	resp := ta.post(t, "key-1", `{"name":"b"}`)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if problem := decodeProblem(t, resp); !strings.Contains(problem.Detail, "different request") {
+		t.Errorf("unexpected problem %#v", problem)
+	}
+}
+
+func TestMiddlewareRejectsKeyReuseWithDifferentAccept(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	if resp := ta.post(t, "key-1", `{"name":"a"}`); resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/things", strings.NewReader(`{"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/cbor")
+	req.Header.Set("Authorization", "Bearer user-1")
+	req.Header.Set(HeaderName, "key-1")
+	resp := httptest.NewRecorder()
+	ta.handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if ta.calls.Load() != 1 {
+		t.Fatalf("expected one handler call, got %d", ta.calls.Load())
+	}
+}
+
+func TestMiddlewareRejectsOversizedBodyWithoutStoringIt(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	oversized := `{"name":"` + strings.Repeat("a", 64) + `"}`
+	resp := ta.post(t, "key-1", oversized)
+	if resp.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if withoutKey := ta.post(t, "", oversized); withoutKey.Body.String() != resp.Body.String() {
+		t.Errorf(
+			"expected the same problem as without a key, got %q and %q",
+			resp.Body.String(),
+			withoutKey.Body.String(),
+		)
+	}
+	// The key was never reserved, so it still works for a request that fits.
+	if resp := ta.post(t, "key-1", `{"name":"a"}`); resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestMiddlewareRejectsRetryWhileInFlight(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+	ta.started = make(chan struct{})
+	ta.release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	var first *httptest.ResponseRecorder
+	wg.Go(func() {
+		first = ta.post(t, "key-1", `{"name":"a"}`)
+	})
+	<-ta.started
+	resp := ta.post(t, "key-1", `{"name":"a"}`)
+	close(ta.release)
+	wg.Wait()
+
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if first.Code != http.StatusCreated {
+		t.Fatalf("expected first request to succeed, got %d", first.Code)
+	}
+}
+
+func TestMiddlewareReleasesKeyAfterServerError(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	ta.status.Store(http.StatusServiceUnavailable)
+	if resp := ta.post(t, "key-1", `{"name":"a"}`); resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", resp.Code)
+	}
+	ta.status.Store(0)
+	if resp := ta.post(t, "key-1", `{"name":"a"}`); resp.Code != http.StatusCreated {
+		t.Fatalf("expected retry to run the handler, got %d", resp.Code)
+	}
+	if ta.calls.Load() != 2 {
+		t.Fatalf("expected handler to run twice, ran %d times", ta.calls.Load())
+	}
+}
+
+func TestMiddlewareKeysAreScopedToUser(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	ta.post(t, "key-1", `{"name":"a"}`)
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/things", strings.NewReader(`{"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer user-2")
+	req.Header.Set(HeaderName, "key-1")
+	resp := httptest.NewRecorder()
+	ta.handler.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated || resp.Header().Get(ReplayedHeader) != "" {
+		t.Fatalf("expected another user's key to be independent, got %d", resp.Code)
+	}
+}
+
+func TestMiddlewarePassesThroughWithoutKey(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	for range 2 {
+		if resp := ta.post(t, "", `{"name":"a"}`); resp.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.Code)
+		}
+	}
+	if ta.calls.Load() != 2 {
+		t.Fatalf("expected handler to run for each request, ran %d times", ta.calls.Load())
+	}
+}
+
+func TestMiddlewareRejectsInvalidKey(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	if resp := ta.post(t, strings.Repeat("k", maxKeyLength+1), `{}`); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.Code)
+	}
+}
+
+func TestMiddlewareFailsClosedWhenStoreFails(t *testing.T) {
+	ta := newTestAPI(failingStore{})
+
+	if resp := ta.post(t, "key-1", `{"name":"a"}`); resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", resp.Code)
+	}
+	if ta.calls.Load() != 0 {
+		t.Fatal("expected handler not to run")
+	}
+}
+
+func TestDocumentRequestsAddsHeaderAndResponses(t *testing.T) {
+	ta := newTestAPI(NewMemoryStore())
+
+	op := ta.api.OpenAPI().Paths["/things"].Post
+	var found bool
+	for _, param := range op.Parameters {
+		found = found || (param.Name == HeaderName && param.In == "header")
+	}
+	if !found {
+		t.Error("expected Idempotency-Key header parameter")
+	}
+	for _, status := range []string{"409", "422"} {
+		if op.Responses[status] == nil {
+			t.Errorf("expected %s response", status)
+		}
+	}
+}
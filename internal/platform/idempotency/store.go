@@ -0,0 +1,91 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops expired records.
+const sweepInterval = time.Minute
+
+// Record is the state of one idempotency key.
+type Record struct {
+	// Fingerprint identifies the request that claimed the key.
+	Fingerprint string
+	// Completed is false while the first request is in flight.
+	Completed bool
+	Status    int
+	Header    http.Header
+	Body      []byte
+	// ExpiresAt ends the in-flight lock or, once completed, the replay window.
+	ExpiresAt time.Time
+}
+
+// Store holds idempotency records. Implementations must make Reserve atomic so concurrent retries
+// cannot both claim a key.
+type Store interface {
+	// Reserve claims key with pending unless an unexpired record exists, which it returns with
+	// reserved false.
+	Reserve(ctx context.Context, key string, pending Record) (existing Record, reserved bool, err error)
+	// Complete replaces the pending record with the stored response.
+	Complete(ctx context.Context, key string, record Record) error
+	// Release removes a pending record so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryStore is a process-local Store. Keys are only deduplicated per instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]Record
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record), now: time.Now}
+}
+
+// Reserve claims key unless an unexpired record exists.
+func (s *MemoryStore) Reserve(_ context.Context, key string, pending Record) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	if existing, ok := s.records[key]; ok && now.Before(existing.ExpiresAt) {
+		return existing, false, nil
+	}
+	s.records[key] = pending
+	return Record{}, true, nil
+}
+
+// Complete stores the response for key.
+func (s *MemoryStore) Complete(_ context.Context, key string, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = record
+	return nil
+}
+
+// Release removes key.
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
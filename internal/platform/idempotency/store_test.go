@@ -0,0 +1,92 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStoreReservesOnce(t *testing.T) {
+	store, clock := newTestStore()
+	pending := Record{Fingerprint: "a", ExpiresAt: clock.now.Add(time.Minute)}
+
+	if _, reserved, err := store.Reserve(t.Context(), "key", pending); err != nil || !reserved {
+		t.Fatalf("expected first reservation, got reserved=%v err=%v", reserved, err)
+	}
+	existing, reserved, err := store.Reserve(t.Context(), "key", Record{Fingerprint: "b"})
+	if err != nil || reserved {
+		t.Fatalf("expected key to be taken, got reserved=%v err=%v", reserved, err)
+	}
+	if existing.Fingerprint != "a" || existing.Completed {
+		t.Fatalf("expected pending record, got %#v", existing)
+	}
+}
+
+func TestMemoryStoreReturnsCompletedRecord(t *testing.T) {
+	store, clock := newTestStore()
+	_, _, _ = store.Reserve(t.Context(), "key", Record{Fingerprint: "a", ExpiresAt: clock.now.Add(time.Minute)})
+	record := Record{
+		Fingerprint: "a",
+		Completed:   true,
+		Status:      201,
+		Body:        []byte("{}"),
+		ExpiresAt:   clock.now.Add(time.Hour),
+	}
+	if err := store.Complete(t.Context(), "key", record); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	existing, reserved, _ := store.Reserve(t.Context(), "key", Record{Fingerprint: "a"})
+	if reserved || !existing.Completed || existing.Status != 201 || string(existing.Body) != "{}" {
+		t.Fatalf("expected completed record, got reserved=%v %#v", reserved, existing)
+	}
+}
+
+func TestMemoryStoreExpiresRecords(t *testing.T) {
+	store, clock := newTestStore()
+	_, _, _ = store.Reserve(t.Context(), "key", Record{Fingerprint: "a", ExpiresAt: clock.now.Add(time.Minute)})
+
+	clock.now = clock.now.Add(time.Minute)
+	if _, reserved, _ := store.Reserve(t.Context(), "key", Record{Fingerprint: "b"}); !reserved {
+		t.Fatal("expected expired lock to be reclaimed")
+	}
+}
+
+func TestMemoryStoreRelease(t *testing.T) {
+	store, clock := newTestStore()
+	pending := Record{Fingerprint: "a", ExpiresAt: clock.now.Add(time.Minute)}
+	_, _, _ = store.Reserve(t.Context(), "key", pending)
+
+	if err := store.Release(t.Context(), "key"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, reserved, _ := store.Reserve(t.Context(), "key", pending); !reserved {
+		t.Fatal("expected released key to be reservable")
+	}
+}
+
+func TestMemoryStoreSweepsExpiredRecords(t *testing.T) {
+	store, clock := newTestStore()
+	_, _, _ = store.Reserve(t.Context(), "idle", Record{ExpiresAt: clock.now.Add(time.Minute)})
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	_, _, _ = store.Reserve(t.Context(), "active", Record{ExpiresAt: clock.now.Add(time.Minute)})
+
+	if _, ok := store.records["idle"]; ok {
+		t.Fatal("expected expired record to be swept")
+	}
+}
@@ -0,0 +1,135 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+)
+
+const (
+	// MaxBodyBytesKey is the huma.Operation Metadata key for an int64 request body limit in bytes
+	// that replaces the default for that operation. Huma applies it to the decoded body.
+	MaxBodyBytesKey = "limits.maxBodyBytes"
+
+	// TimeoutKey is the huma.Operation Metadata key for a time.Duration handler timeout that
+	// replaces the default for that operation.
+	TimeoutKey = "limits.timeout"
+)
+
+// Limits bounds the work a single request may cause.
+type Limits struct {
+	MaxBodyBytes int64
+	Timeout      time.Duration
+}
+
+// Operations applies default Limits to every Huma operation, honoring the overrides an operation
+// declares in its Metadata.
+type Operations struct {
+	defaults   Limits
+	writeGrace time.Duration
+	ceiling    Limits
+}
+
+// New creates operation limits. writeGrace is the time a response may take to write after the
+// handler's timeout; it extends the server write deadline for operations with a longer timeout.
+func New(defaults Limits, writeGrace time.Duration) *Operations {
+	return &Operations{defaults: defaults, writeGrace: writeGrace, ceiling: defaults}
+}
+
+// Register applies the limits as operations are added to api. Call it before operations are
+// registered. Malformed overrides panic, like other operation registration errors.
+func (o *Operations) Register(api huma.API) {
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(_ *huma.OpenAPI, op *huma.Operation) {
+		limits := o.For(op)
+		if op.RequestBody != nil || op.MaxBodyBytes != 0 {
+			op.MaxBodyBytes = limits.MaxBodyBytes
+		}
+		o.ceiling.MaxBodyBytes = max(o.ceiling.MaxBodyBytes, limits.MaxBodyBytes)
+		o.ceiling.Timeout = max(o.ceiling.Timeout, limits.Timeout)
+	})
+}
+
+// For returns the limits of op: its overrides, or the defaults.
+func (o *Operations) For(op *huma.Operation) Limits {
+	limits := o.defaults
+	if value, ok := op.Metadata[MaxBodyBytesKey]; ok {
+		maxBytes, ok := value.(int64)
+		if !ok || maxBytes <= 0 {
+			panic(fmt.Sprintf("%s %s: %s must be a positive int64, got %#v",
+				op.Method, op.Path, MaxBodyBytesKey, value))
+		}
+		limits.MaxBodyBytes = maxBytes
+	}
+	if value, ok := op.Metadata[TimeoutKey]; ok {
+		timeout, ok := value.(time.Duration)
+		if !ok || timeout <= 0 {
+			panic(fmt.Sprintf("%s %s: %s must be a positive time.Duration, got %#v",
+				op.Method, op.Path, TimeoutKey, value))
+		}
+		limits.Timeout = timeout
+	}
+	return limits
+}
+
+// Max returns the largest limits of any registered operation, for transport-level bounds that run
+// before routing and must admit every operation.
+func (o *Operations) Max() Limits {
+	return o.ceiling
+}
+
+// backstopKey holds the request context a Backstop bounded, so Middleware can lift the bound.
+type backstopKey struct{}
+
+// Backstop bounds every request's context by the default timeout before routing, covering handlers
+// outside Huma and middleware that runs before Middleware. Middleware lifts it for operations with a
+// longer timeout, so only those operations run past the default.
+func (o *Operations) Backstop() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), backstopKey{}, r.Context())
+			ctx, cancel := context.WithTimeout(ctx, o.defaults.Timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Middleware bounds each handler's context by its operation timeout. An operation whose timeout
+// exceeds the default also has its write deadline pushed back, so the response can still be sent.
+func (o *Operations) Middleware() func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		parent := ctx.Context()
+		timeout := o.For(ctx.Operation()).Timeout
+		if timeout > o.defaults.Timeout {
+			_, w := humachi.Unwrap(ctx)
+			// Writers that cannot move their deadline keep the server's WriteTimeout.
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + o.writeGrace))
+			var release context.CancelFunc
+			parent, release = liftBackstop(parent)
+			defer release()
+		}
+		timeoutCtx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		next(huma.WithContext(ctx, timeoutCtx))
+	}
+}
+
+// liftBackstop returns ctx without the Backstop deadline, keeping its values and still canceled when
+// the request ends. Call release once the returned context is no longer used.
+func liftBackstop(ctx context.Context) (context.Context, context.CancelFunc) {
+	request, ok := ctx.Value(backstopKey{}).(context.Context)
+	if !ok {
+		return ctx, func() {}
+	}
+	lifted, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(request, func() { cancel(context.Cause(request)) })
+	return lifted, func() {
+		stop()
+		cancel(nil)
+	}
+}
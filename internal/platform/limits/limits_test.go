@@ -0,0 +1,185 @@
+package limits
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5"
+)
+
+var testDefaults = Limits{MaxBodyBytes: 64, Timeout: time.Second}
+
+type echoInput struct {
+	Body struct {
+		Name string `json:"name"`
+	}
+}
+
+type deadlineOutput struct {
+	Body struct {
+		Remaining time.Duration `json:"remaining"`
+	}
+}
+
+func newTestAPI(t *testing.T, operations *Operations) http.Handler {
+	t.Helper()
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("LimitsTest", "test"))
+	operations.Register(api)
+	api.UseMiddleware(operations.Middleware())
+	handler := func(ctx context.Context, _ *echoInput) (*deadlineOutput, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Error("expected a handler deadline")
+		}
+		out := &deadlineOutput{}
+		out.Body.Remaining = time.Until(deadline)
+		return out, nil
+	}
+	huma.Register(api, huma.Operation{OperationID: "post-default", Method: http.MethodPost, Path: "/default"}, handler)
+	huma.Register(api, huma.Operation{
+		OperationID: "post-upload",
+		Method:      http.MethodPost,
+		Path:        "/upload",
+		Metadata:    map[string]any{MaxBodyBytesKey: int64(256), TimeoutKey: 3 * time.Second},
+	}, handler)
+	return router
+}
+
+func post(t *testing.T, handler http.Handler, path string, size int) *httptest.ResponseRecorder {
+	t.Helper()
+	body := `{"name":"` + strings.Repeat("a", size) + `"}`
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestOperationsApplyDefaultsAndOverrides(t *testing.T) {
+	operations := New(testDefaults, time.Second)
+	handler := newTestAPI(t, operations)
+
+	if resp := post(t, handler, "/default", 10); resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := post(t, handler, "/default", 100); resp.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected the default body limit to apply, got %d", resp.Code)
+	}
+	if resp := post(t, handler, "/upload", 100); resp.Code != http.StatusOK {
+		t.Fatalf("expected the override to admit a larger body, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := post(t, handler, "/upload", 300); resp.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected the override body limit to apply, got %d", resp.Code)
+	}
+
+	if got := operations.Max(); got.MaxBodyBytes != 256 || got.Timeout != 3*time.Second {
+		t.Fatalf("unexpected ceiling %#v", got)
+	}
+}
+
+func TestOperationsBoundHandlerContext(t *testing.T) {
+	handler := newTestAPI(t, New(testDefaults, time.Second))
+	for path, want := range map[string]time.Duration{"/default": time.Second, "/upload": 3 * time.Second} {
+		resp := post(t, handler, path, 1)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, resp.Code)
+		}
+		var out struct {
+			Remaining time.Duration `json:"remaining"`
+		}
+		if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+			t.Fatalf("%s: decode response: %v", path, err)
+		}
+		if remaining := out.Remaining; remaining > want || remaining < want-500*time.Millisecond {
+			t.Fatalf("%s: expected a deadline about %s away, got %s", path, want, remaining)
+		}
+	}
+}
+
+func TestBackstopBoundsRequestsOutsideOperations(t *testing.T) {
+	backstop := New(Limits{Timeout: time.Millisecond}, time.Second).Backstop()
+	handler := backstop(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		if !errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+			t.Errorf("unexpected context error: %v", r.Context().Err())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil))
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.Code)
+	}
+}
+
+func TestBackstopYieldsToLongerOperationTimeout(t *testing.T) {
+	operations := New(testDefaults, time.Second)
+	handler := operations.Backstop()(newTestAPI(t, operations))
+	for path, want := range map[string]time.Duration{"/default": time.Second, "/upload": 3 * time.Second} {
+		resp := post(t, handler, path, 1)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, resp.Code)
+		}
+		var out struct {
+			Remaining time.Duration `json:"remaining"`
+		}
+		if err := json.Unmarshal(resp.Body.Bytes(), &out); err != nil {
+			t.Fatalf("%s: decode response: %v", path, err)
+		}
+		if remaining := out.Remaining; remaining > want || remaining < want-500*time.Millisecond {
+			t.Fatalf("%s: expected a deadline about %s away, got %s", path, want, remaining)
+		}
+	}
+}
+
+func TestLiftedBackstopEndsWithRequest(t *testing.T) {
+	operations := New(testDefaults, time.Second)
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("LimitsTest", "test"))
+	operations.Register(api)
+	api.UseMiddleware(operations.Middleware())
+	requestCtx, cancelRequest := context.WithCancel(t.Context())
+	huma.Register(api, huma.Operation{
+		OperationID: "get-stream",
+		Method:      http.MethodGet,
+		Path:        "/stream",
+		Metadata:    map[string]any{TimeoutKey: time.Minute},
+	}, func(ctx context.Context, _ *struct{}) (*struct{}, error) {
+		cancelRequest()
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Error("expected the handler context to end with the request")
+		}
+		return nil, nil
+	})
+	req := httptest.NewRequestWithContext(requestCtx, http.MethodGet, "/stream", nil)
+	operations.Backstop()(router).ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestOperationsRejectMalformedOverrides(t *testing.T) {
+	for name, metadata := range map[string]map[string]any{
+		"untyped body limit": {MaxBodyBytesKey: 1024},
+		"negative body":      {MaxBodyBytesKey: int64(-1)},
+		"untyped timeout":    {TimeoutKey: "5s"},
+		"zero timeout":       {TimeoutKey: time.Duration(0)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected registration to panic")
+				}
+			}()
+			New(testDefaults, time.Second).For(&huma.Operation{Method: http.MethodPost, Path: "/", Metadata: metadata})
+		})
+	}
+}
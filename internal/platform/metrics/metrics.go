@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// OutcomeSuccess is the outcome recorded for dependency calls that return no error.
+const OutcomeSuccess = "success"
+
+// Metrics holds the application's Prometheus collectors and their registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	inFlight        *prometheus.GaugeVec
+
+	dependencyCalls    *prometheus.CounterVec
+	dependencyDuration *prometheus.HistogramVec
+}
+
+// New creates a registry with HTTP, dependency, Go runtime, and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_server_requests_total",
+			Help: "Completed API requests by operation, path template, and status class.",
+		}, []string{"operation", "route", "status_class"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_server_request_duration_seconds",
+			Help:    "API request latency by operation, path template, and status class.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation", "route", "status_class"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_server_requests_in_flight",
+			Help: "API requests currently being served by operation and path template.",
+		}, []string{"operation", "route"}),
+		dependencyCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dependency_requests_total",
+			Help: "Calls to external dependencies by dependency, operation, and outcome.",
+		}, []string{"dependency", "operation", "outcome"}),
+		dependencyDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "dependency_request_duration_seconds",
+			Help:    "External dependency call latency by dependency and operation.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"dependency", "operation"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.inFlight,
+		m.dependencyCalls,
+		m.dependencyDuration,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text or OpenMetrics format, as negotiated.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+		Registry:          m.registry,
+	})
+}
+
+// Middleware records request count, latency, and in-flight requests for each Huma operation.
+// Labels use the OperationID and path template, never the raw path, to keep cardinality bounded.
+func (m *Metrics) Middleware() func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		var operation, route string
+		if op := ctx.Operation(); op != nil {
+			operation, route = op.OperationID, op.Path
+		}
+		inFlight := m.inFlight.WithLabelValues(operation, route)
+		inFlight.Inc()
+		start := time.Now()
+		completed := false
+		defer func() {
+			inFlight.Dec()
+			status := ctx.Status()
+			if !completed {
+				status = http.StatusInternalServerError
+			}
+			class := statusClass(status)
+			m.requests.WithLabelValues(operation, route, class).Inc()
+			m.requestDuration.WithLabelValues(operation, route, class).Observe(time.Since(start).Seconds())
+		}()
+
+		next(ctx)
+		completed = true
+	}
+}
+
+// ObserveDependency records one call to an external dependency.
+func (m *Metrics) ObserveDependency(dependency, operation, outcome string, duration time.Duration) {
+	m.dependencyCalls.WithLabelValues(dependency, operation, outcome).Inc()
+	m.dependencyDuration.WithLabelValues(dependency, operation).Observe(duration.Seconds())
+}
+
+// Observe runs call and records it as a dependency call. classify maps a non-nil error to a
+// bounded outcome label; calls that return no error are recorded as OutcomeSuccess.
+func Observe[T any](
+	m *Metrics,
+	dependency, operation string,
+	classify func(error) string,
+	call func() (T, error),
+) (T, error) {
+	start := time.Now()
+	result, err := call()
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = classify(err)
+	}
+	m.ObserveDependency(dependency, operation, outcome, time.Since(start))
+	return result, err
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
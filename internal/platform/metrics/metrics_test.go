@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type pingOutput struct {
+	Body struct {
+		OK bool `json:"ok"`
+	}
+}
+
+func newTestAPI(m *Metrics) http.Handler {
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("MetricsTest", "test"))
+	api.UseMiddleware(m.Middleware())
+	huma.Register(api, huma.Operation{
+		OperationID: "get-ping",
+		Method:      http.MethodGet,
+		Path:        "/ping/{id}",
+	}, func(_ context.Context, input *struct {
+		ID string `path:"id"`
+	},
+	) (*pingOutput, error) {
+		if input.ID == "missing" {
+			return nil, huma.Error404NotFound("not found")
+		}
+		if input.ID == "panic" {
+			panic("boom")
+		}
+		return &pingOutput{}, nil
+	})
+	return router
+}
+
+func serve(t *testing.T, handler http.Handler, path string) {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestMiddlewareRecordsREDMetricsByOperation(t *testing.T) {
+	m := New()
+	handler := newTestAPI(m)
+
+	serve(t, handler, "/ping/1")
+	serve(t, handler, "/ping/2")
+	serve(t, handler, "/ping/missing")
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("get-ping", "/ping/{id}", "2xx")); got != 2 {
+		t.Errorf("expected 2 successful requests, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("get-ping", "/ping/{id}", "4xx")); got != 1 {
+		t.Errorf("expected 1 client error, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.requestDuration); got != 2 {
+		t.Errorf("expected 2 duration series, got %d", got)
+	}
+	if got := testutil.ToFloat64(m.inFlight.WithLabelValues("get-ping", "/ping/{id}")); got != 0 {
+		t.Errorf("expected no in-flight requests, got %v", got)
+	}
+}
+
+func TestMiddlewareRecordsPanicsAsServerErrors(t *testing.T) {
+	m := New()
+	handler := newTestAPI(m)
+
+	func() {
+		defer func() { _ = recover() }()
+		serve(t, handler, "/ping/panic")
+	}()
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("get-ping", "/ping/{id}", "5xx")); got != 1 {
+		t.Errorf("expected panic to be recorded as 5xx, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.inFlight.WithLabelValues("get-ping", "/ping/{id}")); got != 0 {
+		t.Errorf("expected in-flight gauge to be released, got %v", got)
+	}
+}
+
+func TestObserveRecordsOutcome(t *testing.T) {
+	m := New()
+	errMissing := errors.New("missing")
+	classify := func(err error) string {
+		if errors.Is(err, errMissing) {
+			return "not_found"
+		}
+		return "error"
+	}
+
+	if got, err := Observe(m, "github", "get_owner", classify, func() (string, error) {
+		return "octocat", nil
+	}); err != nil || got != "octocat" {
+		t.Fatalf("expected result to pass through, got %q, %v", got, err)
+	}
+	if _, err := Observe(m, "github", "get_owner", classify, func() (string, error) {
+		return "", errMissing
+	}); !errors.Is(err, errMissing) {
+		t.Fatalf("expected error to pass through, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(m.dependencyCalls.WithLabelValues("github", "get_owner", OutcomeSuccess)); got != 1 {
+		t.Errorf("expected 1 successful call, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.dependencyCalls.WithLabelValues("github", "get_owner", "not_found")); got != 1 {
+		t.Errorf("expected 1 not_found call, got %v", got)
+	}
+}
+
+func TestObserveDependencyRecordsDuration(t *testing.T) {
+	m := New()
+	m.ObserveDependency("firestore", "get_profile", OutcomeSuccess, 250*time.Millisecond)
+
+	expected := `
+# HELP dependency_request_duration_seconds External dependency call latency by dependency and operation.
+# TYPE dependency_request_duration_seconds histogram
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="0.005"} 0
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="0.01"} 0
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="0.025"} 0
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="0.05"} 0
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="0.1"} 0
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="0.25"} 1
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="0.5"} 1
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="1"} 1
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="2.5"} 1
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="5"} 1
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="10"} 1
+dependency_request_duration_seconds_bucket{dependency="firestore",operation="get_profile",le="+Inf"} 1
+dependency_request_duration_seconds_sum{dependency="firestore",operation="get_profile"} 0.25
+dependency_request_duration_seconds_count{dependency="firestore",operation="get_profile"} 1
+`
+	if err := testutil.CollectAndCompare(m.dependencyDuration, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandlerNegotiatesOpenMetrics(t *testing.T) {
+	m := New()
+	m.ObserveDependency("github", "get_owner", OutcomeSuccess, time.Millisecond)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	resp := httptest.NewRecorder()
+	m.Handler().ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	if ct := resp.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("expected OpenMetrics content type, got %q", ct)
+	}
+	body := resp.Body.String()
+	for _, want := range []string{"dependency_requests_total", "go_goroutines", "# EOF"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics output", want)
+		}
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := map[int]string{0: "unknown", 200: "2xx", 204: "2xx", 304: "3xx", 429: "4xx", 503: "5xx", 600: "unknown"}
+	for status, want := range tests {
+		if got := statusClass(status); got != want {
+			t.Errorf("statusClass(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
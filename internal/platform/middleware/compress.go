@@ -0,0 +1,273 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressionEncodings lists supported content codings in server preference order, used to break
+// ties between equal client q-values.
+var compressionEncodings = []string{"zstd", "br", "gzip"}
+
+// encoder is the streaming interface shared by the gzip, brotli, and zstd writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+type zstdEncoder struct {
+	*zstd.Encoder
+}
+
+func (e zstdEncoder) Reset(w io.Writer) {
+	e.Encoder.Reset(w)
+}
+
+var encoderPools = map[string]*sync.Pool{
+	"gzip": {New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}},
+	"br": {New: func() any {
+		return brotli.NewWriterLevel(io.Discard, 4)
+	}},
+	"zstd": {New: func() any {
+		w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		return zstdEncoder{w}
+	}},
+}
+
+// Compress negotiates a zstd, br, or gzip content coding from Accept-Encoding and compresses
+// responses of at least minSize bytes. Smaller bodies, HEAD requests, bodiless statuses, partial
+// content, responses that already carry a Content-Encoding or Cache-Control: no-transform, and
+// already-compressed media types are sent unchanged. Vary: Accept-Encoding is added to every response.
+//
+// Headers are only committed once minSize bytes are buffered, the handler flushes, or it returns, so
+// a Recoverer outside this middleware can still replace a response that panics before then.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			AddVary(w.Header(), "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Values("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+			cw.done = true
+		})
+	}
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	minSize     int
+	status      int
+	wroteHeader bool
+	committed   bool
+	// done reports that the handler returned rather than panicked.
+	done bool
+	buf  []byte
+	enc  encoder
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.committed {
+		if w.enc != nil {
+			return w.enc.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minSize {
+		if err := w.commit(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush commits the response so streamed bodies reach the client, compressing them only if the
+// buffered prefix already met the size threshold.
+func (w *compressWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.committed {
+		if err := w.commit(); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// commit writes the status and headers, starting the encoder when the response qualifies.
+func (w *compressWriter) commit() error {
+	w.committed = true
+	header := w.ResponseWriter.Header()
+	if len(w.buf) >= w.minSize && w.compressible(header) {
+		if header.Get("Content-Type") == "" {
+			// net/http would otherwise sniff the compressed bytes.
+			header.Set("Content-Type", http.DetectContentType(w.buf))
+		}
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.enc = encoderPools[w.encoding].Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close flushes a response that stayed below the threshold and finishes the encoder. If the handler
+// panicked, nothing buffered is written, so the Recoverer can respond instead, and the encoder is
+// dropped rather than pooled, since it may hold part of a stream.
+func (w *compressWriter) close() {
+	if !w.done {
+		w.buf, w.enc = nil, nil
+		return
+	}
+	if !w.wroteHeader {
+		return
+	}
+	if !w.committed {
+		if err := w.commit(); err != nil {
+			return
+		}
+	}
+	if w.enc == nil {
+		return
+	}
+	_ = w.enc.Close()
+	w.enc.Reset(io.Discard)
+	encoderPools[w.encoding].Put(w.enc)
+	w.enc = nil
+}
+
+func (w *compressWriter) compressible(header http.Header) bool {
+	switch {
+	case w.status < http.StatusOK,
+		w.status == http.StatusNoContent,
+		w.status == http.StatusPartialContent,
+		w.status == http.StatusNotModified:
+		return false
+	case header.Get("Content-Encoding") != "", header.Get("Content-Range") != "":
+		return false
+	case strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-transform"):
+		return false
+	}
+	return !alreadyCompressed(header.Get("Content-Type"))
+}
+
+// alreadyCompressed reports whether contentType is a media type whose encoding is already compressed.
+func alreadyCompressed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	switch major {
+	case "image":
+		return mediaType != "image/svg+xml" && mediaType != "image/bmp"
+	case "audio", "video":
+		return true
+	}
+	switch mediaType {
+	case "application/gzip", "application/x-gzip", "application/zip", "application/zstd",
+		"application/x-bzip2", "application/x-xz", "application/x-7z-compressed", "application/x-rar-compressed",
+		"application/pdf", "application/wasm", "font/woff", "font/woff2":
+		return true
+	}
+	return false
+}
+
+// negotiateEncoding returns the supported coding with the highest q-value, or "" when the client
+// accepts none of them. A coding the client does not list takes the q-value of "*", if present.
+func negotiateEncoding(values []string) string {
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for _, value := range values {
+		for item := range strings.SplitSeq(value, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding == "" {
+				continue
+			}
+			q := 1.0
+			if name, raw, ok := strings.Cut(strings.TrimSpace(params), "="); ok &&
+				strings.EqualFold(strings.TrimSpace(name), "q") {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+				if err != nil || parsed < 0 || parsed > 1 {
+					continue
+				}
+				q = parsed
+			}
+			if coding == "*" {
+				wildcard = q
+			} else {
+				weights[coding] = q
+			}
+		}
+	}
+	best, bestQ := "", 0.0
+	for _, coding := range compressionEncodings {
+		q, ok := weights[coding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+	return best
+}
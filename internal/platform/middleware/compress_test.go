@@ -0,0 +1,279 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const testMinSize = 64
+
+var largeBody = strings.Repeat(`{"name":"item","value":42},`, 20)
+
+func serveCompressed(t *testing.T, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/test", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	resp := httptest.NewRecorder()
+	Compress(testMinSize)(handler).ServeHTTP(resp, req)
+	return resp
+}
+
+func writeBody(contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.Header().Set("Content-Length", "999")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func decode(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("gzip reader: %v", err)
+		}
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("zstd reader: %v", err)
+		}
+		defer zr.Close()
+		reader = zr
+	default:
+		t.Fatalf("unexpected encoding %q", encoding)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decode %s: %v", encoding, err)
+	}
+	return string(decoded)
+}
+
+func TestCompressNegotiatesEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		accept string
+		want   string
+	}{
+		{accept: "gzip", want: "gzip"},
+		{accept: "br", want: "br"},
+		{accept: "zstd", want: "zstd"},
+		{accept: "gzip, deflate, br, zstd", want: "zstd"},
+		{accept: "gzip;q=1, br;q=0.8, zstd;q=0.5", want: "gzip"},
+		{accept: "*", want: "zstd"},
+		{accept: "*;q=0.5, gzip", want: "gzip"},
+		{accept: "zstd;q=0, *", want: "br"},
+	} {
+		t.Run(tc.accept, func(t *testing.T) {
+			resp := serveCompressed(t, tc.accept, writeBody("application/json", largeBody))
+			if got := resp.Header().Get("Content-Encoding"); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+			if resp.Header().Get("Content-Length") != "" {
+				t.Error("expected Content-Length to be removed")
+			}
+			if got := decode(t, tc.want, resp.Body.Bytes()); got != largeBody {
+				t.Fatalf("unexpected decoded body %q", got)
+			}
+		})
+	}
+}
+
+func TestCompressSkipsUnacceptableEncodings(t *testing.T) {
+	for _, accept := range []string{"", "identity", "deflate", "gzip;q=0", "*;q=0", "gzip;q=bad"} {
+		resp := serveCompressed(t, accept, writeBody("application/json", largeBody))
+		if got := resp.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Accept-Encoding %q: expected no coding, got %q", accept, got)
+		}
+		if resp.Body.String() != largeBody {
+			t.Errorf("Accept-Encoding %q: expected unchanged body", accept)
+		}
+	}
+}
+
+func TestCompressAddsVary(t *testing.T) {
+	for _, accept := range []string{"", "gzip"} {
+		resp := serveCompressed(t, accept, func(w http.ResponseWriter, _ *http.Request) {
+			AddVary(w.Header(), "Accept")
+			_, _ = w.Write([]byte("small"))
+		})
+		if got := resp.Header().Values("Vary"); len(got) != 2 || got[0] != "Accept-Encoding" || got[1] != "Accept" {
+			t.Fatalf("Accept-Encoding %q: unexpected Vary %v", accept, got)
+		}
+	}
+}
+
+func TestCompressSkipsSmallBodies(t *testing.T) {
+	resp := serveCompressed(t, "gzip", writeBody("application/json", `{"ok":true}`))
+	if resp.Header().Get("Content-Encoding") != "" || resp.Body.String() != `{"ok":true}` {
+		t.Fatalf("expected small body to be sent unchanged, got %q", resp.Header().Get("Content-Encoding"))
+	}
+	if resp.Header().Get("Content-Length") != "999" {
+		t.Error("expected Content-Length to be preserved")
+	}
+}
+
+func TestCompressSkipsIneligibleResponses(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"image": writeBody("image/png", largeBody),
+		"zip":   writeBody("application/zip", largeBody),
+		"already encoded": func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write([]byte(largeBody))
+		},
+		"no-transform": func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Cache-Control", "no-store, no-transform")
+			_, _ = w.Write([]byte(largeBody))
+		},
+		"partial content": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(largeBody))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := serveCompressed(t, "gzip", handler)
+			if got := resp.Header().Get("Content-Encoding"); got != "" && got != "gzip" {
+				t.Fatalf("unexpected Content-Encoding %q", got)
+			}
+			if resp.Body.String() != largeBody {
+				t.Fatal("expected body to be sent unchanged")
+			}
+		})
+	}
+}
+
+func TestCompressKeepsSVG(t *testing.T) {
+	resp := serveCompressed(t, "gzip", writeBody("image/svg+xml", largeBody))
+	if resp.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("expected SVG to be compressed")
+	}
+}
+
+func TestCompressSniffsContentTypeBeforeEncoding(t *testing.T) {
+	resp := serveCompressed(t, "gzip", writeBody("", "<html>"+largeBody))
+	if got := resp.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Fatalf("expected sniffed Content-Type, got %q", got)
+	}
+}
+
+func TestCompressPreservesStatusAndWeakensETag(t *testing.T) {
+	resp := serveCompressed(t, "gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(largeBody))
+	})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.Code)
+	}
+	if got := resp.Header().Get("ETag"); got != `W/"v1"` {
+		t.Fatalf("expected weak ETag, got %q", got)
+	}
+}
+
+func TestCompressSkipsHead(t *testing.T) {
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodHead, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp := httptest.NewRecorder()
+	Compress(testMinSize)(writeBody("application/json", largeBody)).ServeHTTP(resp, req)
+	if resp.Header().Get("Content-Encoding") != "" {
+		t.Fatal("expected HEAD response not to be encoded")
+	}
+}
+
+func TestCompressFlushStreamsEncodedChunks(t *testing.T) {
+	resp := serveCompressed(t, "gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(largeBody))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("flush: %v", err)
+		}
+		if !recorderFlushed(w) {
+			t.Error("expected underlying writer to be flushed")
+		}
+		_, _ = w.Write([]byte("tail"))
+	})
+	if resp.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("expected streamed response to be compressed")
+	}
+	if got := decode(t, "gzip", resp.Body.Bytes()); got != largeBody+"tail" {
+		t.Fatalf("unexpected decoded body %q", got)
+	}
+}
+
+// recorderFlushed reports whether the recorder under a compressWriter has been flushed.
+func recorderFlushed(w http.ResponseWriter) bool {
+	recorder, ok := w.(*compressWriter).ResponseWriter.(*httptest.ResponseRecorder)
+	return ok && recorder.Flushed
+}
+
+func TestCompressFlushBelowThresholdSendsIdentity(t *testing.T) {
+	resp := serveCompressed(t, "gzip", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("event: ping\n\n"))
+		_ = http.NewResponseController(w).Flush()
+		_, _ = w.Write([]byte(largeBody))
+	})
+	if resp.Header().Get("Content-Encoding") != "" || resp.Body.String() != "event: ping\n\n"+largeBody {
+		t.Fatal("expected a stream committed below the threshold to stay uncompressed")
+	}
+}
+
+func TestCompressDoesNotCommitBufferedResponseOnPanic(t *testing.T) {
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	handler := Compress(testMinSize)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("partial"))
+		panic("boom")
+	}))
+	func() {
+		defer func() { _ = recover() }()
+		handler.ServeHTTP(resp, req)
+	}()
+	if resp.Body.Len() != 0 || resp.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected nothing to be written, got %q", resp.Body.String())
+	}
+}
+
+func TestCompressDropsEncoderOnPanic(t *testing.T) {
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	var cw *compressWriter
+	handler := Compress(testMinSize)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		cw = w.(*compressWriter)
+		_, _ = w.Write([]byte(largeBody))
+		panic("boom")
+	}))
+	func() {
+		defer func() { _ = recover() }()
+		handler.ServeHTTP(resp, req)
+	}()
+	if cw.enc != nil {
+		t.Fatal("expected the encoder to be dropped")
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(resp.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	if _, err := io.ReadAll(gz); err == nil {
+		t.Fatal("expected the interrupted stream to stay unfinished")
+	}
+}
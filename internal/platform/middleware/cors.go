@@ -21,14 +21,20 @@ func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
 		AllowedHeaders: []string{
 			"Accept",
 			"Authorization",
+			"Content-Encoding",
 			"Content-Type",
+			"Idempotency-Key",
 			"X-Request-ID",
 			"traceparent",
 			"tracestate",
 		},
 		ExposedHeaders: []string{
+			"Idempotent-Replayed",
 			"Link",
 			"Location",
+			"Pagination",
+			"RateLimit",
+			"RateLimit-Policy",
 			"Retry-After",
 			"X-RateLimit-Reset",
 			"X-Request-ID",
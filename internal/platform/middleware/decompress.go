@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxDecoderWindow caps the zstd window a request may demand, bounding decoder memory independently
+// of the decompressed size limit.
+const maxDecoderWindow = 8 << 20
+
+// ProblemWriter writes a Problem Details response for a request rejected before it reaches Huma.
+type ProblemWriter func(w http.ResponseWriter, r *http.Request, status int, detail string)
+
+// DecompressRequests decodes request bodies sent with Content-Encoding gzip, zstd, or br so handlers
+// see the plain representation. At most maxBytes decompressed bytes are accepted, so a small
+// compressed body cannot expand past the limit applied to uncompressed requests. It must run after
+// chimiddleware.RequestSize, which still bounds the compressed bytes read from the connection.
+//
+// Unsupported or stacked codings are rejected with 415 and an Accept-Encoding header, corrupt
+// bodies with 400, and bodies that exceed maxBytes once decoded with 413.
+func DecompressRequests(maxBytes int64, reject ProblemWriter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			coding := strings.ToLower(strings.TrimSpace(strings.Join(r.Header.Values("Content-Encoding"), ",")))
+			if coding == "" || coding == "identity" {
+				r.Header.Del("Content-Encoding")
+				next.ServeHTTP(w, r)
+				return
+			}
+			decoder, err := newDecoder(coding, r.Body)
+			switch {
+			case errors.Is(err, errUnsupportedCoding):
+				w.Header().Set("Accept-Encoding", strings.Join(compressionEncodings, ", "))
+				reject(w, r, http.StatusUnsupportedMediaType,
+					fmt.Sprintf("unsupported Content-Encoding %q; use one of %s",
+						coding, strings.Join(compressionEncodings, ", ")))
+				return
+			case err != nil:
+				rejectBody(w, r, reject, coding, err)
+				return
+			}
+			body, err := io.ReadAll(io.LimitReader(decoder, maxBytes+1))
+			_ = decoder.Close()
+			if err == nil && int64(len(body)) > maxBytes {
+				err = &http.MaxBytesError{Limit: maxBytes}
+			}
+			if err != nil {
+				rejectBody(w, r, reject, coding, err)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+			r.Header.Del("Content-Encoding")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+var errUnsupportedCoding = errors.New("unsupported content coding")
+
+func newDecoder(coding string, body io.Reader) (io.ReadCloser, error) {
+	switch coding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "br":
+		return io.NopCloser(brotli.NewReader(body)), nil
+	case "zstd":
+		decoder, err := zstd.NewReader(body,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(maxDecoderWindow),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, errUnsupportedCoding
+	}
+}
+
+func rejectBody(w http.ResponseWriter, r *http.Request, reject ProblemWriter, coding string, err error) {
+	if maxErr, ok := errors.AsType[*http.MaxBytesError](err); ok {
+		reject(w, r, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("request body is too large limit=%d bytes", maxErr.Limit))
+		return
+	}
+	reject(w, r, http.StatusBadRequest, fmt.Sprintf("request body is not valid %s data", coding))
+}
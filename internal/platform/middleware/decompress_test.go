@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const testMaxBytes = 1024
+
+type rejection struct {
+	status int
+	detail string
+}
+
+func encode(t *testing.T, coding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch coding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		encoder, err := zstd.NewWriter(&buf)
+		if err != nil {
+			t.Fatalf("zstd writer: %v", err)
+		}
+		w = encoder
+	default:
+		t.Fatalf("unexpected coding %q", coding)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close encoder: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func serveDecompressed(t *testing.T, coding string, body []byte) (string, *rejection, *httptest.ResponseRecorder) {
+	t.Helper()
+	var got string
+	var rejected *rejection
+	handler := DecompressRequests(
+		testMaxBytes,
+		func(w http.ResponseWriter, _ *http.Request, status int, detail string) {
+			rejected = &rejection{status: status, detail: detail}
+			w.WriteHeader(status)
+		},
+	)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Encoding") != "" {
+				t.Error("expected Content-Encoding to be removed")
+			}
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if r.ContentLength != int64(len(data)) {
+				t.Errorf("expected ContentLength %d, got %d", len(data), r.ContentLength)
+			}
+			got = string(data)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/test", bytes.NewReader(body))
+	if coding != "" {
+		req.Header.Set("Content-Encoding", coding)
+	}
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return got, rejected, resp
+}
+
+func TestDecompressRequestsDecodesSupportedCodings(t *testing.T) {
+	payload := `{"name":"` + strings.Repeat("a", 200) + `"}`
+	for _, coding := range []string{"gzip", "br", "zstd"} {
+		t.Run(coding, func(t *testing.T) {
+			got, rejected, _ := serveDecompressed(t, coding, encode(t, coding, []byte(payload)))
+			if rejected != nil {
+				t.Fatalf("unexpected rejection %#v", rejected)
+			}
+			if got != payload {
+				t.Fatalf("expected decoded payload, got %q", got)
+			}
+		})
+	}
+}
+
+func TestDecompressRequestsPassesIdentityThrough(t *testing.T) {
+	for _, coding := range []string{"", "identity"} {
+		got, rejected, _ := serveDecompressed(t, coding, []byte(`{"ok":true}`))
+		if rejected != nil || got != `{"ok":true}` {
+			t.Fatalf("coding %q: expected body unchanged, got %q %#v", coding, got, rejected)
+		}
+	}
+}
+
+func TestDecompressRequestsRejectsUnsupportedCoding(t *testing.T) {
+	for _, coding := range []string{"deflate", "gzip, br", "compress"} {
+		_, rejected, resp := serveDecompressed(t, coding, []byte("data"))
+		if rejected == nil || rejected.status != http.StatusUnsupportedMediaType {
+			t.Fatalf("coding %q: expected 415, got %#v", coding, rejected)
+		}
+		if got := resp.Header().Get("Accept-Encoding"); got != "zstd, br, gzip" {
+			t.Errorf("coding %q: unexpected Accept-Encoding %q", coding, got)
+		}
+	}
+}
+
+func TestDecompressRequestsRejectsCorruptBody(t *testing.T) {
+	for _, coding := range []string{"gzip", "zstd"} {
+		_, rejected, _ := serveDecompressed(t, coding, []byte("not compressed"))
+		if rejected == nil || rejected.status != http.StatusBadRequest {
+			t.Fatalf("coding %q: expected 400, got %#v", coding, rejected)
+		}
+	}
+}
+
+func TestDecompressRequestsLimitsDecodedSize(t *testing.T) {
+	bomb := encode(t, "gzip", bytes.Repeat([]byte{0}, 64*testMaxBytes))
+	if len(bomb) >= testMaxBytes {
+		t.Fatalf("expected a small compressed body, got %d bytes", len(bomb))
+	}
+	_, rejected, _ := serveDecompressed(t, "gzip", bomb)
+	if rejected == nil || rejected.status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %#v", rejected)
+	}
+
+	got, rejected, _ := serveDecompressed(t, "gzip", encode(t, "gzip", bytes.Repeat([]byte{'a'}, testMaxBytes)))
+	if rejected != nil || len(got) != testMaxBytes {
+		t.Fatalf("expected a body at the limit to be accepted, got %d bytes %#v", len(got), rejected)
+	}
+}
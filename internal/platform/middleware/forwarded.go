@@ -1,6 +1,11 @@
 package middleware
 
-import "net/http"
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
 
 var untrustedForwardingHeaders = []string{
 	"Forwarded",
@@ -10,10 +15,74 @@ var untrustedForwardingHeaders = []string{
 	"X-Real-IP",
 }
 
-// IgnoreForwardedHeaders enforces the absence of a trusted-proxy boundary.
-func IgnoreForwardedHeaders() func(http.Handler) http.Handler {
+// ForwardingHeader names the family of forwarding headers that trusted proxies write.
+type ForwardingHeader int
+
+const (
+	// HeaderXForwarded is X-Forwarded-For with -Proto and -Host, which Cloud Run, Google Cloud load
+	// balancers, and most L7 proxies append.
+	HeaderXForwarded ForwardingHeader = iota
+	// HeaderForwarded is RFC 7239 Forwarded.
+	HeaderForwarded
+)
+
+// TrustedProxies describes the proxies allowed to report the original client, scheme, and host.
+// The zero value trusts no proxy.
+type TrustedProxies struct {
+	// Prefixes are networks whose addresses are trusted proxies wherever they appear in the chain.
+	Prefixes []netip.Prefix
+	// Hops trusts the nearest Hops proxies regardless of address, for platforms such as Cloud Run
+	// whose front ends have no stable address range.
+	Hops int
+	// Header is the only family read. Proxies pass the other family through from the client
+	// unchanged, so reading it would let clients choose their own address.
+	Header ForwardingHeader
+}
+
+// Enabled reports whether any proxy is trusted.
+func (p TrustedProxies) Enabled() bool {
+	return p.Hops > 0 || len(p.Prefixes) > 0
+}
+
+// trusts reports whether the hop at index i, counted from the peer at 0, is a trusted proxy.
+func (p TrustedProxies) trusts(i int, addr netip.Addr) bool {
+	if i < p.Hops {
+		return true
+	}
+	for _, prefix := range p.Prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedHop is one proxy's report of the request it received.
+type forwardedHop struct {
+	addr  netip.Addr
+	proto string
+	host  string
+}
+
+// ForwardedHeaders resolves the client address, scheme, and host from the header family the trusted
+// proxies write, walking the chain from the peer and stopping at the first untrusted hop. The result
+// replaces RemoteAddr, URL.Scheme, and Host, so chi's ClientIPFromRemoteAddr and Huma schema links
+// see the original request. Forwarding headers are always removed afterwards, so nothing downstream
+// can read unverified values.
+func ForwardedHeaders(proxies TrustedProxies) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if proxies.Enabled() {
+				if hop, ok := resolveClient(proxies, r); ok {
+					r.RemoteAddr = net.JoinHostPort(hop.addr.String(), "0")
+					if hop.proto == "http" || hop.proto == "https" {
+						r.URL.Scheme = hop.proto
+					}
+					if hop.host != "" {
+						r.Host = hop.host
+					}
+				}
+			}
 			for _, header := range untrustedForwardingHeaders {
 				r.Header.Del(header)
 			}
@@ -21,3 +90,136 @@ func IgnoreForwardedHeaders() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// resolveClient returns the hop that reached the outermost trusted proxy. It reports false when the
+// peer itself is untrusted or the chain does not parse.
+func resolveClient(proxies TrustedProxies, r *http.Request) (forwardedHop, bool) {
+	peer, ok := parseNodeAddr(r.RemoteAddr)
+	if !ok || !proxies.trusts(0, peer) {
+		return forwardedHop{}, false
+	}
+	var hops []forwardedHop
+	if proxies.Header == HeaderForwarded {
+		hops, ok = parseForwarded(r.Header.Values("Forwarded"))
+	} else {
+		hops, ok = parseXForwarded(r.Header)
+	}
+	if !ok || len(hops) == 0 {
+		return forwardedHop{}, false
+	}
+	// hops[len-1] was reported by the peer, so it is hop 1 counted from the peer.
+	for i := len(hops) - 1; i > 0; i-- {
+		if !proxies.trusts(len(hops)-i, hops[i].addr) {
+			return hops[i], true
+		}
+	}
+	return hops[0], true
+}
+
+// parseForwarded parses RFC 7239 elements. Any element without a usable for= address invalidates
+// the header, because skipping it would misalign the chain.
+func parseForwarded(values []string) ([]forwardedHop, bool) {
+	var hops []forwardedHop
+	for _, value := range values {
+		for element := range strings.SplitSeq(value, ",") {
+			if strings.TrimSpace(element) == "" {
+				continue
+			}
+			var hop forwardedHop
+			for pair := range strings.SplitSeq(element, ";") {
+				key, raw, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if !found {
+					return nil, false
+				}
+				val := strings.Trim(strings.TrimSpace(raw), `"`)
+				switch strings.ToLower(key) {
+				case "for":
+					addr, ok := parseNodeAddr(val)
+					if !ok {
+						return nil, false
+					}
+					hop.addr = addr
+				case "proto":
+					hop.proto = strings.ToLower(val)
+				case "host":
+					hop.host = validHost(val)
+				}
+			}
+			if !hop.addr.IsValid() {
+				return nil, false
+			}
+			hops = append(hops, hop)
+		}
+	}
+	return hops, true
+}
+
+// parseXForwarded pairs X-Forwarded-Proto and X-Forwarded-Host with X-Forwarded-For entry by entry,
+// for proxies that append to all three. Many proxies append only to X-Forwarded-For, passing the
+// client's own -Proto and -Host through, so a list whose length differs from X-Forwarded-For cannot
+// be attributed to the hops and is ignored.
+func parseXForwarded(header http.Header) ([]forwardedHop, bool) {
+	forwardedFor := splitList(header.Values("X-Forwarded-For"))
+	protos := alignedList(header.Values("X-Forwarded-Proto"), len(forwardedFor))
+	hosts := alignedList(header.Values("X-Forwarded-Host"), len(forwardedFor))
+	hops := make([]forwardedHop, len(forwardedFor))
+	for i, entry := range forwardedFor {
+		addr, ok := parseNodeAddr(entry)
+		if !ok {
+			return nil, false
+		}
+		hops[i] = forwardedHop{addr: addr}
+		if protos != nil {
+			hops[i].proto = strings.ToLower(protos[i])
+		}
+		if hosts != nil {
+			hops[i].host = validHost(hosts[i])
+		}
+	}
+	return hops, true
+}
+
+// alignedList returns the entries of values when there are n of them, one per X-Forwarded-For hop,
+// or nil otherwise.
+func alignedList(values []string, n int) []string {
+	entries := splitList(values)
+	if len(entries) != n {
+		return nil
+	}
+	return entries
+}
+
+func splitList(values []string) []string {
+	var entries []string
+	for _, value := range values {
+		for entry := range strings.SplitSeq(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+// parseNodeAddr accepts an IP address with an optional port, IPv6 in brackets when a port is present.
+// Obfuscated and "unknown" RFC 7239 identifiers are rejected.
+func parseNodeAddr(node string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(node); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(node, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// validHost returns host when it contains only host name, IP literal, and port characters, else "".
+func validHost(host string) string {
+	for _, c := range host {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune(".-_:[]", c)) {
+			return ""
+		}
+	}
+	return host
+}
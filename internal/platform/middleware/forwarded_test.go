@@ -3,28 +3,173 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 )
 
-func TestIgnoreForwardedHeaders(t *testing.T) {
-	handler := IgnoreForwardedHeaders()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+type forwardedRequest struct {
+	remoteAddr string
+	scheme     string
+	host       string
+}
+
+func serveForwarded(t *testing.T, proxies TrustedProxies, remoteAddr string, headers http.Header) forwardedRequest {
+	t.Helper()
+	var got forwardedRequest
+	handler := ForwardedHeaders(proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		for _, header := range untrustedForwardingHeaders {
 			if value := r.Header.Get(header); value != "" {
 				t.Errorf("expected %s to be removed, got %q", header, value)
 			}
 		}
-		if r.Host != "api.example.com" {
-			t.Errorf("expected Host to be preserved, got %q", r.Host)
-		}
+		got = forwardedRequest{remoteAddr: r.RemoteAddr, scheme: r.URL.Scheme, host: r.Host}
 		w.WriteHeader(http.StatusNoContent)
 	}))
-	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "https://api.example.com/probe", nil)
-	for _, header := range untrustedForwardingHeaders {
-		request.Header.Set(header, "attacker.example")
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "http://api.internal/probe", nil)
+	request.RemoteAddr = remoteAddr
+	for name, values := range headers {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
 	}
 	response := httptest.NewRecorder()
 	handler.ServeHTTP(response, request)
 	if response.Code != http.StatusNoContent {
 		t.Fatalf("expected 204, got %d", response.Code)
 	}
+	return got
+}
+
+func TestForwardedHeadersIgnoredWithoutTrustedProxies(t *testing.T) {
+	headers := http.Header{}
+	for _, header := range untrustedForwardingHeaders {
+		headers.Set(header, "attacker.example")
+	}
+	headers.Set("Forwarded", "for=203.0.113.9;host=attacker.example")
+	headers.Set("X-Forwarded-For", "203.0.113.9")
+
+	got := serveForwarded(t, TrustedProxies{}, "10.0.0.1:1234", headers)
+	if got.remoteAddr != "10.0.0.1:1234" || got.host != "api.internal" {
+		t.Fatalf("expected request to be unchanged, got %#v", got)
+	}
+}
+
+func TestForwardedHeadersIgnoredFromUntrustedPeer(t *testing.T) {
+	proxies := TrustedProxies{Prefixes: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+	headers := http.Header{"X-Forwarded-For": {"203.0.113.9"}, "X-Forwarded-Host": {"attacker.example"}}
+
+	got := serveForwarded(t, proxies, "198.51.100.7:1234", headers)
+	if got.remoteAddr != "198.51.100.7:1234" || got.host != "api.internal" {
+		t.Fatalf("expected untrusted peer to be ignored, got %#v", got)
+	}
+}
+
+func TestForwardedHeadersUsesXForwardedFromTrustedPrefixes(t *testing.T) {
+	proxies := TrustedProxies{Prefixes: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+	headers := http.Header{
+		// The leftmost entry is client-controlled and must not be selected.
+		"X-Forwarded-For":   {"192.0.2.66, 203.0.113.9, 10.1.1.1"},
+		"X-Forwarded-Proto": {"http, https", "http"},
+		"X-Forwarded-Host":  {"evil.example, api.example.com, api.internal"},
+	}
+
+	got := serveForwarded(t, proxies, "10.0.0.1:1234", headers)
+	want := forwardedRequest{remoteAddr: "203.0.113.9:0", scheme: "https", host: "api.example.com"}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestForwardedHeadersIgnoresHostAndProtoNotAppendedPerHop(t *testing.T) {
+	// The proxy appended only to X-Forwarded-For; -Host and -Proto came from the client.
+	headers := http.Header{
+		"X-Forwarded-For":   {"192.0.2.66, 203.0.113.9"},
+		"X-Forwarded-Host":  {"evil.example"},
+		"X-Forwarded-Proto": {"https"},
+	}
+
+	got := serveForwarded(t, TrustedProxies{Hops: 1}, "169.254.1.1:1234", headers)
+	want := forwardedRequest{remoteAddr: "203.0.113.9:0", scheme: "http", host: "api.internal"}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestForwardedHeadersTrustsHopCount(t *testing.T) {
+	headers := http.Header{"X-Forwarded-For": {"192.0.2.66, 203.0.113.9"}}
+
+	got := serveForwarded(t, TrustedProxies{Hops: 1}, "169.254.1.1:1234", headers)
+	if got.remoteAddr != "203.0.113.9:0" {
+		t.Fatalf("expected the address appended by the trusted hop, got %q", got.remoteAddr)
+	}
+	got = serveForwarded(t, TrustedProxies{Hops: 2}, "169.254.1.1:1234", headers)
+	if got.remoteAddr != "192.0.2.66:0" {
+		t.Fatalf("expected two trusted hops to reach the first address, got %q", got.remoteAddr)
+	}
+}
+
+func TestForwardedHeadersReadsRFC7239WhenConfigured(t *testing.T) {
+	headers := http.Header{
+		"Forwarded": {
+			`for=192.0.2.66;host=spoofed.example, for="[2001:db8::1]:4711";proto=https;host=api.example.com`,
+		},
+		"X-Forwarded-For": {"198.51.100.1"},
+	}
+
+	got := serveForwarded(t, TrustedProxies{Hops: 1, Header: HeaderForwarded}, "10.0.0.1:1234", headers)
+	want := forwardedRequest{remoteAddr: "[2001:db8::1]:0", scheme: "https", host: "api.example.com"}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestForwardedHeadersIgnoresClientForwardedBehindXForwardedProxy(t *testing.T) {
+	// The proxy appended only X-Forwarded-For and passed the client's Forwarded header through.
+	headers := http.Header{
+		"Forwarded":       {"for=203.0.113.66;host=evil.example;proto=https"},
+		"X-Forwarded-For": {"198.51.100.7"},
+	}
+
+	got := serveForwarded(t, TrustedProxies{Hops: 1}, "10.0.0.1:1234", headers)
+	want := forwardedRequest{remoteAddr: "198.51.100.7:0", scheme: "http", host: "api.internal"}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	// Likewise, a proxy that writes Forwarded does not vouch for X-Forwarded-*.
+	headers = http.Header{
+		"Forwarded":         {"for=198.51.100.7"},
+		"X-Forwarded-For":   {"203.0.113.66"},
+		"X-Forwarded-Host":  {"evil.example"},
+		"X-Forwarded-Proto": {"https"},
+	}
+	got = serveForwarded(t, TrustedProxies{Hops: 1, Header: HeaderForwarded}, "10.0.0.1:1234", headers)
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestForwardedHeadersRejectsMalformedValues(t *testing.T) {
+	for name, headers := range map[string]http.Header{
+		"obfuscated node":   {"Forwarded": {"for=_hidden;proto=https"}},
+		"unknown node":      {"Forwarded": {"for=unknown"}},
+		"missing for":       {"Forwarded": {"proto=https"}},
+		"invalid XFF entry": {"X-Forwarded-For": {"203.0.113.9, not-an-ip"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			proxies := TrustedProxies{Hops: 1}
+			if headers.Get("Forwarded") != "" {
+				proxies.Header = HeaderForwarded
+			}
+			got := serveForwarded(t, proxies, "10.0.0.1:1234", headers)
+			if got.remoteAddr != "10.0.0.1:1234" {
+				t.Fatalf("expected malformed chain to be ignored, got %#v", got)
+			}
+		})
+	}
+
+	headers := http.Header{"X-Forwarded-For": {"203.0.113.9"}, "X-Forwarded-Host": {"evil.example/path"}}
+	if got := serveForwarded(t, TrustedProxies{Hops: 1}, "10.0.0.1:1234", headers); got.host != "api.internal" {
+		t.Fatalf("expected invalid forwarded host to be ignored, got %q", got.host)
+	}
 }
@@ -0,0 +1,226 @@
+// Package money provides an amount of an ISO 4217 currency held exactly in minor units.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var (
+	// ErrInvalidCurrency indicates a code that is not an uppercase ISO 4217 currency.
+	ErrInvalidCurrency = errors.New("money: expected an uppercase ISO 4217 currency code")
+
+	// ErrInvalidAmount indicates an amount that is malformed, out of range, or more precise than the
+	// currency's minor unit.
+	ErrInvalidAmount = errors.New("money: invalid amount")
+)
+
+// Money is an amount in the minor unit of a currency, such as cents for USD or fils for KWD. The
+// zero value has no currency and is only useful as a placeholder.
+type Money struct {
+	minor int64
+	unit  currency.Unit
+}
+
+// New returns minor units of the currency code.
+func New(minor int64, code string) (Money, error) {
+	unit, err := parseCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, unit: unit}, nil
+}
+
+// MustNew is like New but panics on an invalid currency. It is intended for fixed data.
+func MustNew(minor int64, code string) Money {
+	m, err := New(minor, code)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Minor returns the amount in minor units.
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Currency returns the ISO 4217 code, or "" for the zero value.
+func (m Money) Currency() string {
+	if m.IsZero() {
+		return ""
+	}
+	return m.unit.String()
+}
+
+// IsZero reports whether m is the zero value.
+func (m Money) IsZero() bool {
+	return m.unit == currency.Unit{}
+}
+
+// Exponent returns the number of minor-unit digits of the currency: 2 for USD, 0 for JPY, 3 for KWD.
+func (m Money) Exponent() int {
+	scale, _ := currency.Standard.Rounding(m.unit)
+	return scale
+}
+
+// Amount returns the amount as a decimal string with exactly Exponent fraction digits, such as "29.99".
+func (m Money) Amount() string {
+	exp := m.Exponent()
+	digits := strconv.FormatUint(absUint(m.minor), 10)
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if m.minor < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// String returns the amount followed by the currency code, such as "29.99 USD".
+func (m Money) String() string {
+	return m.Amount() + " " + m.Currency()
+}
+
+// Format returns the amount with its currency symbol using the number conventions of tag, such as
+// "€ 1.234,50" for German. It is meant for display; clients should compute with Amount and Currency.
+func (m Money) Format(tag language.Tag) string {
+	value := float64(m.minor) / math.Pow10(m.Exponent())
+	return message.NewPrinter(tag).Sprint(currency.Symbol(m.unit.Amount(value)))
+}
+
+// Parse reads a decimal amount of the currency code, such as "29.99" and "USD". The amount may omit
+// trailing fraction digits but may not be more precise than the currency's minor unit.
+func Parse(amount, code string) (Money, error) {
+	unit, err := parseCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	m := Money{unit: unit}
+	whole, fraction, hasFraction := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	exp := m.Exponent()
+	if !digitsOnly(whole) || (hasFraction && !digitsOnly(fraction)) || len(fraction) > exp {
+		return Money{}, fmt.Errorf("%w %q for %s", ErrInvalidAmount, amount, code)
+	}
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", exp-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w %q for %s", ErrInvalidAmount, amount, code)
+	}
+	if strings.HasPrefix(amount, "-") {
+		minor = -minor
+	}
+	m.minor = minor
+	return m, nil
+}
+
+// wireMoney is the serialized form shared by JSON and CBOR.
+type wireMoney struct {
+	Amount   string `json:"amount"   cbor:"amount"`
+	Currency string `json:"currency" cbor:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(wireMoney{Amount: m.Amount(), Currency: m.Currency()})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var w wireMoney
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	return m.fromWire(w)
+}
+
+func (m Money) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(wireMoney{Amount: m.Amount(), Currency: m.Currency()})
+}
+
+func (m *Money) UnmarshalCBOR(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("money: empty CBOR data")
+	}
+	var w wireMoney
+	if err := cbor.Unmarshal(data, &w); err != nil {
+		return fmt.Errorf("money: decode CBOR: %w", err)
+	}
+	return m.fromWire(w)
+}
+
+func (m *Money) fromWire(w wireMoney) error {
+	parsed, err := Parse(w.Amount, w.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Schema describes the serialized form to Huma.
+func (Money) Schema(huma.Registry) *huma.Schema {
+	return &huma.Schema{
+		Type:        huma.TypeObject,
+		Description: "Monetary amount in an ISO 4217 currency",
+		Properties: map[string]*huma.Schema{
+			"amount": {
+				Type: huma.TypeString,
+				Description: "Decimal amount with at most as many fraction digits as the currency's " +
+					"minor unit",
+				Pattern:  `^-?\d+(\.\d+)?$`,
+				Examples: []any{"29.99"},
+			},
+			"currency": {
+				Type:        huma.TypeString,
+				Description: "ISO 4217 currency code",
+				Pattern:     "^[A-Z]{3}$",
+				Examples:    []any{"USD"},
+			},
+		},
+		Required:             []string{"amount", "currency"},
+		AdditionalProperties: false,
+	}
+}
+
+// parseCurrency accepts uppercase ISO 4217 codes. XXX is assigned but means "no currency", so it
+// cannot carry an amount.
+func parseCurrency(code string) (currency.Unit, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil || unit == (currency.Unit{}) || unit.String() != code {
+		return currency.Unit{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	return unit, nil
+}
+
+func digitsOnly(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
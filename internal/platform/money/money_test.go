@@ -0,0 +1,135 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/text/language"
+)
+
+func TestExponentFollowsCurrency(t *testing.T) {
+	for _, tc := range []struct {
+		minor    int64
+		code     string
+		exponent int
+		amount   string
+	}{
+		{2999, "USD", 2, "29.99"},
+		{5, "EUR", 2, "0.05"},
+		{-150, "USD", 2, "-1.50"},
+		{1200, "JPY", 0, "1200"},
+		{12345, "KWD", 3, "12.345"},
+		{7, "BHD", 3, "0.007"},
+		{0, "ISK", 0, "0"},
+	} {
+		m := MustNew(tc.minor, tc.code)
+		if m.Exponent() != tc.exponent || m.Amount() != tc.amount {
+			t.Errorf("%d %s: expected exponent %d amount %s, got %d %s",
+				tc.minor, tc.code, tc.exponent, tc.amount, m.Exponent(), m.Amount())
+		}
+		parsed, err := Parse(tc.amount, tc.code)
+		if err != nil || parsed != m {
+			t.Errorf("parse %s %s: expected %v, got %v %v", tc.amount, tc.code, m, parsed, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for amount, want := range map[string]int64{"29.9": 2990, "29": 2900, "-0.01": -1, "007.50": 750} {
+		m, err := Parse(amount, "USD")
+		if err != nil || m.Minor() != want {
+			t.Errorf("parse %q: expected %d, got %d %v", amount, want, m.Minor(), err)
+		}
+	}
+	for _, tc := range []struct {
+		amount, code string
+		err          error
+	}{
+		{"1.999", "USD", ErrInvalidAmount},
+		{"1.5", "JPY", ErrInvalidAmount},
+		{"1.", "USD", ErrInvalidAmount},
+		{".5", "USD", ErrInvalidAmount},
+		{"1e3", "USD", ErrInvalidAmount},
+		{"+1", "USD", ErrInvalidAmount},
+		{"", "USD", ErrInvalidAmount},
+		{"99999999999999999999", "USD", ErrInvalidAmount},
+		{"1.00", "usd", ErrInvalidCurrency},
+		{"1.00", "ZZZ", ErrInvalidCurrency},
+		{"1.00", "XXX", ErrInvalidCurrency},
+		{"1.00", "", ErrInvalidCurrency},
+	} {
+		if _, err := Parse(tc.amount, tc.code); !errors.Is(err, tc.err) {
+			t.Errorf("parse %q %q: expected %v, got %v", tc.amount, tc.code, tc.err, err)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(MustNew(12345, "KWD"))
+	if err != nil {
+		t.Fatalf("marshal JSON: %v", err)
+	}
+	if got, want := string(data), `{"amount":"12.345","currency":"KWD"}`; got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	var m Money
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("unmarshal JSON: %v", err)
+	}
+	if m != MustNew(12345, "KWD") {
+		t.Fatalf("unexpected round trip: %v", m)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"1.5","currency":"JPY"}`), &m); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestCBORRoundTripMatchesJSON(t *testing.T) {
+	data, err := cbor.Marshal(MustNew(1200, "JPY"))
+	if err != nil {
+		t.Fatalf("marshal CBOR: %v", err)
+	}
+	var fields map[string]string
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("decode map: %v", err)
+	}
+	if fields["amount"] != "1200" || fields["currency"] != "JPY" || len(fields) != 2 {
+		t.Fatalf("unexpected CBOR fields: %v", fields)
+	}
+	var m Money
+	if err := cbor.Unmarshal(data, &m); err != nil {
+		t.Fatalf("unmarshal CBOR: %v", err)
+	}
+	if m != MustNew(1200, "JPY") {
+		t.Fatalf("unexpected round trip: %v", m)
+	}
+}
+
+func TestFormatUsesLocaleConventions(t *testing.T) {
+	for _, tc := range []struct {
+		money Money
+		tag   language.Tag
+		want  string
+	}{
+		{MustNew(123450, "EUR"), language.AmericanEnglish, "€ 1,234.50"},
+		{MustNew(123450, "EUR"), language.German, "€ 1.234,50"},
+		{MustNew(1200, "JPY"), language.AmericanEnglish, "¥ 1,200"},
+		{MustNew(12345, "KWD"), language.Finnish, "KWD 12,345"},
+	} {
+		if got := tc.money.Format(tc.tag); got != tc.want {
+			t.Errorf("%v in %s: expected %q, got %q", tc.money, tc.tag, tc.want, got)
+		}
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var m Money
+	if !m.IsZero() || m.Currency() != "" || m.Minor() != 0 {
+		t.Fatalf("unexpected zero value: %q %d", m.Currency(), m.Minor())
+	}
+	if _, err := New(100, "usd"); !errors.Is(err, ErrInvalidCurrency) {
+		t.Fatalf("expected ErrInvalidCurrency, got %v", err)
+	}
+}
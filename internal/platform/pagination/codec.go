@@ -0,0 +1,201 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrExpiredCursor indicates a signed cursor is past its expiry.
+	ErrExpiredCursor = errors.New("cursor expired; restart from the first page")
+
+	// ErrCursorFilterMismatch indicates a signed cursor was issued for different filters.
+	ErrCursorFilterMismatch = errors.New("cursor does not match the current filters")
+)
+
+const (
+	// signedCursorVersion prefixes signed cursors. Unsigned cursors are Base64 and never contain a dot.
+	signedCursorVersion = "v1"
+
+	// minKeySize is the shortest accepted HMAC-SHA256 secret.
+	minKeySize = 32
+)
+
+// Key is a cursor signing key. The ID is embedded in each cursor so a rotated key keeps verifying the
+// cursors it signed.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// CodecConfig configures cursor signing.
+type CodecConfig struct {
+	// Keys verify cursors, and the first also signs new ones. Keep a retired key listed after its
+	// replacement until the cursors it signed have expired.
+	Keys []Key
+
+	// TTL bounds how long a cursor is accepted after it was issued. Zero means cursors do not expire.
+	TTL time.Duration
+
+	// AcceptUnsigned also decodes the legacy unsigned format, without expiry or filter binding, so
+	// cursors issued before signing was enabled keep working while clients migrate.
+	AcceptUnsigned bool
+}
+
+// Codec issues and verifies HMAC-signed cursors bound to the filters they were issued for.
+type Codec struct {
+	signing        Key
+	keys           map[string][]byte
+	ttl            time.Duration
+	acceptUnsigned bool
+	now            func() time.Time
+}
+
+// NewCodec validates cfg and creates a codec.
+func NewCodec(cfg CodecConfig) (*Codec, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("at least one cursor signing key is required")
+	}
+	if cfg.TTL < 0 {
+		return nil, errors.New("cursor TTL must not be negative")
+	}
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		if !validKeyID(key.ID) {
+			return nil, fmt.Errorf("cursor key ID %q must be 1 to 32 letters, digits, '-' or '_'", key.ID)
+		}
+		if len(key.Secret) < minKeySize {
+			return nil, fmt.Errorf("cursor key %q must be at least %d bytes", key.ID, minKeySize)
+		}
+		if _, ok := keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate cursor key ID %q", key.ID)
+		}
+		keys[key.ID] = key.Secret
+	}
+	return &Codec{
+		signing:        cfg.Keys[0],
+		keys:           keys,
+		ttl:            cfg.TTL,
+		acceptUnsigned: cfg.AcceptUnsigned,
+		now:            time.Now,
+	}, nil
+}
+
+// signedCursor is the signed payload. Short field names keep cursors compact in URLs.
+type signedCursor struct {
+	Type      string `json:"t"`
+	Value     string `json:"v"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	Filter    string `json:"f,omitempty"`
+	Before    bool   `json:"b,omitempty"`
+}
+
+// Encode signs cursor and binds it to filter, the query parameters that select the result set. Leave
+// the cursor out of filter. Keyset cursors should leave the page size out too, so clients may change
+// it between pages; cursors holding a page number or offset must include it, since their position
+// depends on it.
+func (c *Codec) Encode(cursor Cursor, filter url.Values) string {
+	now := c.now()
+	payload := signedCursor{
+		Type:     cursor.Type,
+		Value:    cursor.Value,
+		IssuedAt: now.Unix(),
+		Filter:   filterFingerprint(filter),
+		Before:   cursor.Before,
+	}
+	if c.ttl > 0 {
+		payload.ExpiresAt = now.Add(c.ttl).Unix()
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		// A struct of strings and integers always marshals.
+		panic(fmt.Sprintf("marshal cursor: %v", err))
+	}
+	signed := signedCursorVersion + "." + c.signing.ID + "." + base64.RawURLEncoding.EncodeToString(data)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(c.signing.Secret, signed))
+}
+
+// Decode verifies s and checks that it was issued for filter. An empty s decodes to the zero Cursor.
+// Errors are ErrInvalidCursor, ErrExpiredCursor, or ErrCursorFilterMismatch, whose messages are safe to
+// return to clients.
+func (c *Codec) Decode(s string, filter url.Values) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	version, rest, signed := strings.Cut(s, ".")
+	if !signed {
+		if !c.acceptUnsigned {
+			return Cursor{}, ErrInvalidCursor
+		}
+		return DecodeCursor(s)
+	}
+	keyID, rest, ok := strings.Cut(rest, ".")
+	if !ok || version != signedCursorVersion {
+		return Cursor{}, ErrInvalidCursor
+	}
+	body, encodedMAC, ok := strings.Cut(rest, ".")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	secret, ok := c.keys[keyID]
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, sign(secret, s[:len(s)-len(encodedMAC)-1])) {
+		return Cursor{}, ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var payload signedCursor
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if payload.ExpiresAt != 0 && !c.now().Before(time.Unix(payload.ExpiresAt, 0)) {
+		return Cursor{}, ErrExpiredCursor
+	}
+	if payload.Filter != filterFingerprint(filter) {
+		return Cursor{}, ErrCursorFilterMismatch
+	}
+	return Cursor{Type: payload.Type, Value: payload.Value, Before: payload.Before}, nil
+}
+
+func sign(secret []byte, data string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// filterFingerprint returns a short digest of filter, or "" when there is none. url.Values.Encode
+// sorts keys, so parameter order does not matter.
+func filterFingerprint(filter url.Values) string {
+	if len(filter) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(filter.Encode()))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func validKeyID(id string) bool {
+	if id == "" || len(id) > 32 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
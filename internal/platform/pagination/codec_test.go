@@ -0,0 +1,184 @@
+package pagination
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(id string) Key {
+	return Key{ID: id, Secret: bytes.Repeat([]byte(id[:1]), minKeySize)}
+}
+
+func testCodec(t *testing.T) *Codec {
+	t.Helper()
+	codec, err := NewCodec(CodecConfig{Keys: []Key{testKey("k1")}})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	return codec
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	codec := testCodec(t)
+	filter := url.Values{"category": {"tools"}}
+	for _, cursor := range []Cursor{
+		{Type: "item", Value: "item-010"},
+		{Type: "item", Value: ""},
+		{Type: "item", Value: "item-011", Before: true},
+		{Type: "item", Before: true},
+		{Type: "event", Value: "a.b:c/d+e=f"},
+	} {
+		encoded := codec.Encode(cursor, filter)
+		if strings.ContainsAny(encoded, "+/=") {
+			t.Errorf("expected a URL-safe cursor, got %q", encoded)
+		}
+		decoded, err := codec.Decode(encoded, filter)
+		if err != nil {
+			t.Fatalf("decode %q: %v", encoded, err)
+		}
+		if decoded != cursor {
+			t.Fatalf("expected %+v, got %+v", cursor, decoded)
+		}
+	}
+	if decoded, err := codec.Decode("", filter); err != nil || decoded != (Cursor{}) {
+		t.Fatalf("expected an empty cursor, got %+v %v", decoded, err)
+	}
+}
+
+func TestCodecRejectsTamperedCursors(t *testing.T) {
+	codec := testCodec(t)
+	encoded := codec.Encode(Cursor{Type: "item", Value: "item-010"}, nil)
+	parts := strings.Split(encoded, ".")
+	forged, err := NewCodec(CodecConfig{Keys: []Key{{ID: "k1", Secret: bytes.Repeat([]byte("x"), minKeySize)}}})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	for name, input := range map[string]string{
+		"altered payload": strings.Join([]string{parts[0], parts[1], parts[2] + "A", parts[3]}, "."),
+		"altered MAC":     strings.Join([]string{parts[0], parts[1], parts[2], "AAAA"}, "."),
+		"unknown key":     strings.Join([]string{parts[0], "k9", parts[2], parts[3]}, "."),
+		"unknown version": strings.Join([]string{"v9", parts[1], parts[2], parts[3]}, "."),
+		"missing MAC":     strings.Join(parts[:3], "."),
+		"forged secret":   forged.Encode(Cursor{Type: "item", Value: "item-999"}, nil),
+		"legacy format":   Cursor{Type: "item", Value: "item-999"}.Encode(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := codec.Decode(input, nil); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCodecBindsFilters(t *testing.T) {
+	codec := testCodec(t)
+	tools := url.Values{"category": {"tools"}}
+	encoded := codec.Encode(Cursor{Type: "item", Value: "item-010"}, tools)
+	for _, filter := range []url.Values{nil, {"category": {"power"}}, {"category": {"tools"}, "sort": {"name"}}} {
+		if _, err := codec.Decode(encoded, filter); !errors.Is(err, ErrCursorFilterMismatch) {
+			t.Fatalf("filter %v: expected ErrCursorFilterMismatch, got %v", filter, err)
+		}
+	}
+	unfiltered := codec.Encode(Cursor{Type: "item", Value: "item-010"}, url.Values{})
+	if _, err := codec.Decode(unfiltered, tools); !errors.Is(err, ErrCursorFilterMismatch) {
+		t.Fatalf("expected an unfiltered cursor to be rejected under a filter, got %v", err)
+	}
+}
+
+func TestCodecExpiresCursors(t *testing.T) {
+	codec, err := NewCodec(CodecConfig{Keys: []Key{testKey("k1")}, TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	issued := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	codec.now = func() time.Time { return issued }
+	encoded := codec.Encode(Cursor{Type: "item", Value: "item-010"}, nil)
+
+	codec.now = func() time.Time { return issued.Add(59 * time.Minute) }
+	if _, err := codec.Decode(encoded, nil); err != nil {
+		t.Fatalf("expected a fresh cursor to decode, got %v", err)
+	}
+	codec.now = func() time.Time { return issued.Add(time.Hour) }
+	if _, err := codec.Decode(encoded, nil); !errors.Is(err, ErrExpiredCursor) {
+		t.Fatalf("expected ErrExpiredCursor, got %v", err)
+	}
+}
+
+func TestCodecRotatesKeys(t *testing.T) {
+	old, err := NewCodec(CodecConfig{Keys: []Key{testKey("k1")}})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	rotated, err := NewCodec(CodecConfig{Keys: []Key{testKey("k2"), testKey("k1")}})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	cursor := Cursor{Type: "item", Value: "item-010"}
+	if decoded, err := rotated.Decode(old.Encode(cursor, nil), nil); err != nil || decoded != cursor {
+		t.Fatalf("expected the retired key to keep verifying, got %+v %v", decoded, err)
+	}
+	encoded := rotated.Encode(cursor, nil)
+	if !strings.HasPrefix(encoded, "v1.k2.") {
+		t.Fatalf("expected the first key to sign, got %q", encoded)
+	}
+	if _, err := old.Decode(encoded, nil); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected an unknown key to be rejected, got %v", err)
+	}
+}
+
+func TestCodecAcceptsUnsignedCursorsDuringMigration(t *testing.T) {
+	codec, err := NewCodec(CodecConfig{Keys: []Key{testKey("k1")}, AcceptUnsigned: true})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	cursor := Cursor{Type: "item", Value: "item-010"}
+	decoded, err := codec.Decode(cursor.Encode(), url.Values{"category": {"tools"}})
+	if err != nil || decoded != cursor {
+		t.Fatalf("expected the legacy cursor to decode, got %+v %v", decoded, err)
+	}
+	if _, err := codec.Decode("!!!invalid!!!", nil); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+	if encoded := codec.Encode(cursor, nil); !strings.HasPrefix(encoded, signedCursorVersion+".") {
+		t.Fatalf("expected new cursors to be signed, got %q", encoded)
+	}
+}
+
+func TestNewCodecValidatesKeys(t *testing.T) {
+	for name, cfg := range map[string]CodecConfig{
+		"no keys":       {},
+		"short secret":  {Keys: []Key{{ID: "k1", Secret: []byte("short")}}},
+		"empty ID":      {Keys: []Key{{Secret: testKey("k1").Secret}}},
+		"ID with dot":   {Keys: []Key{{ID: "k.1", Secret: testKey("k1").Secret}}},
+		"duplicate ID":  {Keys: []Key{testKey("k1"), testKey("k1")}},
+		"negative TTL":  {Keys: []Key{testKey("k1")}, TTL: -time.Second},
+		"long key name": {Keys: []Key{{ID: strings.Repeat("k", 33), Secret: testKey("k1").Secret}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewCodec(cfg); err == nil {
+				t.Fatal("expected configuration error")
+			}
+		})
+	}
+}
+
+func FuzzCodecDecode(f *testing.F) {
+	codec, err := NewCodec(CodecConfig{Keys: []Key{testKey("k1")}, AcceptUnsigned: true})
+	if err != nil {
+		f.Fatalf("new codec: %v", err)
+	}
+	f.Add("")
+	f.Add("v1.k1..")
+	f.Add(codec.Encode(Cursor{Type: "item", Value: "item-001"}, nil))
+	f.Fuzz(func(t *testing.T, input string) {
+		_, err := codec.Decode(input, nil)
+		if err != nil && !errors.Is(err, ErrInvalidCursor) && !errors.Is(err, ErrExpiredCursor) &&
+			!errors.Is(err, ErrCursorFilterMismatch) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
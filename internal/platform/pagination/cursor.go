@@ -11,18 +11,20 @@ var ErrInvalidCursor = errors.New("invalid cursor format")
 
 // Cursor represents a pagination position.
 type Cursor struct {
-	Type  string // resource type identifier
-	Value string // last seen value (ID, timestamp, etc.)
+	Type   string // resource type identifier
+	Value  string // last seen value (ID, timestamp, etc.)
+	Before bool   // page backward, ending just before Value; with an empty Value, the last page
 }
 
-// Encode returns a URL-safe opaque Base64 representation.
+// Encode returns the legacy unsigned URL-safe Base64 representation. Clients can forge it, so cursors
+// sent to clients come from Codec.Encode. The legacy format has no direction, so Before is dropped.
 func (c Cursor) Encode() string {
 	return base64.RawURLEncoding.EncodeToString(
 		[]byte(c.Type + ":" + c.Value),
 	)
 }
 
-// DecodeCursor parses a URL-safe Base64 cursor string.
+// DecodeCursor parses a legacy unsigned URL-safe Base64 cursor string.
 func DecodeCursor(s string) (Cursor, error) {
 	if s == "" {
 		return Cursor{}, nil
@@ -0,0 +1,321 @@
+// Package firestorepage pages through ordered Firestore queries by keyset, producing the same
+// pagination.Result and Link headers as the in-memory paginator.
+package firestorepage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/api/iterator"
+
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+)
+
+// SortKey is one field of the page order.
+type SortKey struct {
+	Path      string
+	Direction firestore.Direction
+}
+
+// matchBatchSize is the fewest documents a query with Match reads at a time, so sparse matches do not
+// cost a round trip per page.
+const matchBatchSize = 100
+
+// Query is a filtered Firestore query to page through in SortKeys order. The document ID is appended
+// as a final tiebreaker so every position is unique. As with any Firestore ordering, documents missing
+// a sort field are not returned, and deployed databases need a composite index covering the filters,
+// the sort keys, and __name__.
+type Query[T any] struct {
+	Base     firestore.Query
+	SortKeys []SortKey
+	// IDDirection orders by document ID alone when SortKeys is empty; it defaults to ascending.
+	IDDirection firestore.Direction
+	Decode      func(*firestore.DocumentSnapshot) (T, error)
+	// Match, when set, keeps only the items it accepts, for conditions Firestore cannot express such as
+	// substring search. A page then reads documents in batches until it fills, so a selective Match
+	// reads many documents per page.
+	Match func(T) bool
+}
+
+// position is the keyset a cursor resumes from: the sort values and document ID of the item just
+// outside the requested page. The cursor's Before flag selects the page preceding it rather than the
+// page following.
+type position struct {
+	Values []string `json:"k"`
+	ID     string   `json:"id"`
+}
+
+// Paginate fetches the page following cursor, or the page preceding it for a prev cursor; a prev
+// cursor without a position is the last page. It reads limit+1 documents to learn whether another
+// page exists, so limit must be positive. Result.Total is left zero; counting a collection is a
+// separate aggregation query.
+//
+// Cursors are signed by codec and bound to query, which is also preserved in the Link header. A cursor
+// of another type than cursorType, or one that does not describe a position in q, returns
+// pagination.ErrInvalidCursor.
+func Paginate[T any](
+	ctx context.Context,
+	codec *pagination.Codec,
+	q Query[T],
+	cursor pagination.Cursor,
+	limit int,
+	cursorType string,
+	baseURL string,
+	query url.Values,
+) (pagination.Result[T], error) {
+	if (cursor.Value != "" || cursor.Before) && cursor.Type != cursorType {
+		return pagination.Result[T]{}, pagination.ErrInvalidCursor
+	}
+	var from position
+	if cursor.Value != "" {
+		if err := json.Unmarshal([]byte(cursor.Value), &from); err != nil || from.ID == "" ||
+			len(from.Values) != len(q.SortKeys) {
+			return pagination.Result[T]{}, pagination.ErrInvalidCursor
+		}
+	}
+
+	ordered := q.Base
+	last := cmp.Or(q.IDDirection, firestore.Asc)
+	for _, key := range q.SortKeys {
+		ordered = ordered.OrderBy(key.Path, direction(key.Direction, cursor.Before))
+		last = key.Direction
+	}
+	ordered = ordered.OrderBy(firestore.DocumentID, direction(last, cursor.Before))
+	if cursor.Value != "" {
+		values := make([]any, 0, len(from.Values)+1)
+		for _, encoded := range from.Values {
+			value, err := decodeValue(encoded)
+			if err != nil {
+				return pagination.Result[T]{}, pagination.ErrInvalidCursor
+			}
+			values = append(values, value)
+		}
+		ordered = ordered.StartAfter(append(values, from.ID)...)
+	}
+
+	matches, err := read(ctx, q, ordered, limit+1)
+	if err != nil {
+		return pagination.Result[T]{}, err
+	}
+	more := len(matches) > limit
+	matches = matches[:min(len(matches), limit)]
+	if cursor.Before {
+		slices.Reverse(matches)
+	}
+	docs := make([]*firestore.DocumentSnapshot, len(matches))
+	items := make([]T, len(matches))
+	for i, m := range matches {
+		docs[i], items[i] = m.doc, m.item
+	}
+
+	// Going forward, a next page exists when the extra document was read, and a prev page when the
+	// request resumed from a cursor. Going backward, the roles swap.
+	hasNext, hasPrev := more, cursor.Value != ""
+	if cursor.Before {
+		hasNext, hasPrev = cursor.Value != "", more
+	}
+
+	var links pagination.Links
+	if cursor.Value != "" || cursor.Before {
+		links.Self = codec.Encode(
+			pagination.Cursor{Type: cursorType, Value: cursor.Value, Before: cursor.Before},
+			query,
+		)
+	}
+	// An empty page past either end still links back to the first page.
+	links.First = hasPrev || (len(docs) == 0 && cursor.Value != "")
+	if len(docs) > 0 {
+		if hasNext {
+			if links.Next, err = encodeCursor(codec, q.SortKeys, docs[len(docs)-1], false, cursorType, query); err != nil {
+				return pagination.Result[T]{}, err
+			}
+			links.Last = codec.Encode(pagination.Cursor{Type: cursorType, Before: true}, query)
+		}
+		if hasPrev {
+			if links.Prev, err = encodeCursor(codec, q.SortKeys, docs[0], true, cursorType, query); err != nil {
+				return pagination.Result[T]{}, err
+			}
+		}
+	}
+
+	linkQuery := maps.Clone(query)
+	if linkQuery == nil {
+		linkQuery = url.Values{}
+	}
+	linkQuery.Set("limit", strconv.Itoa(limit))
+	return pagination.Result[T]{
+		Items:      items,
+		Links:      links,
+		LinkHeader: links.Header(baseURL, linkQuery),
+		NextCursor: links.Next,
+		PrevCursor: links.Prev,
+		Page: pagination.Page{
+			Limit:   limit,
+			Count:   len(items),
+			HasNext: links.Next != "",
+			HasPrev: links.Prev != "",
+		},
+	}, nil
+}
+
+// Count returns how many documents of q's base query Match accepts. Without Match it is a single
+// aggregation query; with Match it reads every document the base query selects.
+func Count[T any](ctx context.Context, q Query[T]) (int, error) {
+	if q.Match == nil {
+		result, err := q.Base.NewAggregationQuery().WithCount("count").Get(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("count: %w", err)
+		}
+		value, ok := result["count"].(*firestorepb.Value)
+		if !ok {
+			return 0, errors.New("count: missing aggregation result")
+		}
+		return int(value.GetIntegerValue()), nil
+	}
+
+	count := 0
+	iter := q.Base.Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return count, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("count: %w", err)
+		}
+		item, err := q.Decode(doc)
+		if err != nil {
+			return 0, fmt.Errorf("decode %s: %w", doc.Ref.ID, err)
+		}
+		if q.Match(item) {
+			count++
+		}
+	}
+}
+
+type match[T any] struct {
+	doc  *firestore.DocumentSnapshot
+	item T
+}
+
+// read returns up to want documents of ordered that q.Match accepts, with their decoded items.
+func read[T any](ctx context.Context, q Query[T], ordered firestore.Query, want int) ([]match[T], error) {
+	batch := want
+	if q.Match != nil {
+		batch = max(want, matchBatchSize)
+	}
+	var matches []match[T]
+	next := ordered
+	for {
+		docs, err := next.Limit(batch).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("query page: %w", err)
+		}
+		for _, doc := range docs {
+			item, err := q.Decode(doc)
+			if err != nil {
+				return nil, fmt.Errorf("decode %s: %w", doc.Ref.ID, err)
+			}
+			if q.Match == nil || q.Match(item) {
+				matches = append(matches, match[T]{doc: doc, item: item})
+			}
+		}
+		if len(matches) >= want || len(docs) < batch {
+			return matches, nil
+		}
+		next = ordered.StartAfter(docs[len(docs)-1])
+	}
+}
+
+func direction(d firestore.Direction, reverse bool) firestore.Direction {
+	if !reverse {
+		return d
+	}
+	if d == firestore.Desc {
+		return firestore.Asc
+	}
+	return firestore.Desc
+}
+
+func encodeCursor(
+	codec *pagination.Codec,
+	keys []SortKey,
+	doc *firestore.DocumentSnapshot,
+	before bool,
+	cursorType string,
+	query url.Values,
+) (string, error) {
+	at := position{Values: make([]string, 0, len(keys)), ID: doc.Ref.ID}
+	for _, key := range keys {
+		value, err := doc.DataAt(key.Path)
+		if err != nil {
+			return "", fmt.Errorf("read sort key of %s: %w", doc.Ref.ID, err)
+		}
+		encoded, err := encodeValue(value)
+		if err != nil {
+			return "", fmt.Errorf("sort key %s of %s: %w", key.Path, doc.Ref.ID, err)
+		}
+		at.Values = append(at.Values, encoded)
+	}
+	data, err := json.Marshal(at)
+	if err != nil {
+		return "", fmt.Errorf("marshal position: %w", err)
+	}
+	return codec.Encode(pagination.Cursor{Type: cursorType, Value: string(data), Before: before}, query), nil
+}
+
+// encodeValue tags a sort value with its type so it decodes to the same Firestore value; JSON alone
+// would turn integers into floats and timestamps into strings.
+func encodeValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "n:", nil
+	case string:
+		return "s:" + v, nil
+	case int64:
+		return "i:" + strconv.FormatInt(v, 10), nil
+	case float64:
+		return "f:" + strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return "b:" + strconv.FormatBool(v), nil
+	case time.Time:
+		return "t:" + v.UTC().Format(time.RFC3339Nano), nil
+	default:
+		return "", fmt.Errorf("unsupported sort value type %T", value)
+	}
+}
+
+func decodeValue(encoded string) (any, error) {
+	kind, raw, ok := strings.Cut(encoded, ":")
+	if !ok {
+		return nil, errors.New("untagged sort value")
+	}
+	switch kind {
+	case "n":
+		return nil, nil
+	case "s":
+		return raw, nil
+	case "i":
+		return strconv.ParseInt(raw, 10, 64)
+	case "f":
+		return strconv.ParseFloat(raw, 64)
+	case "b":
+		return strconv.ParseBool(raw)
+	case "t":
+		return time.Parse(time.RFC3339Nano, raw)
+	default:
+		return nil, fmt.Errorf("unknown sort value type %q", kind)
+	}
+}
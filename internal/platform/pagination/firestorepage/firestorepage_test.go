@@ -0,0 +1,273 @@
+package firestorepage
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/testutil"
+)
+
+const testCollection = "pagination_items"
+
+type testItem struct {
+	ID       string    `firestore:"-"`
+	Category string    `firestore:"category"`
+	Price    int64     `firestore:"price"`
+	Created  time.Time `firestore:"created"`
+}
+
+func decodeTestItem(doc *firestore.DocumentSnapshot) (testItem, error) {
+	var item testItem
+	if err := doc.DataTo(&item); err != nil {
+		return testItem{}, err
+	}
+	item.ID = doc.Ref.ID
+	return item, nil
+}
+
+func setupFirestore(t *testing.T) *firestore.Client {
+	t.Helper()
+	testutil.SkipIfEmulatorUnavailable(t)
+	testutil.SetupEmulator(t)
+	testutil.ClearFirestore(t)
+
+	client, err := firestore.NewClient(t.Context(), testutil.ProjectID)
+	if err != nil {
+		t.Fatalf("failed to create Firestore client: %v", err)
+	}
+	t.Cleanup(func() {
+		testutil.ClearFirestore(t)
+		if err := client.Close(); err != nil {
+			t.Errorf("close Firestore client: %v", err)
+		}
+	})
+	return client
+}
+
+// seed writes 23 items whose prices repeat, so the document ID has to break ties.
+func seed(t *testing.T, client *firestore.Client) []testItem {
+	t.Helper()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := make([]testItem, 0, 23)
+	for i := range 23 {
+		item := testItem{
+			ID:       fmt.Sprintf("item-%03d", i),
+			Category: []string{"tools", "power"}[i%2],
+			Price:    int64(i%5) * 100,
+			Created:  base.Add(time.Duration(i) * time.Hour),
+		}
+		if _, err := client.Collection(testCollection).Doc(item.ID).Set(t.Context(), item); err != nil {
+			t.Fatalf("seed %s: %v", item.ID, err)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func ids(items []testItem) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.ID)
+	}
+	return out
+}
+
+func TestPaginateWalksForwardAndBackward(t *testing.T) {
+	client := setupFirestore(t)
+	items := seed(t, client)
+	codec := testutil.Cursors()
+	ctx := t.Context()
+
+	// Category ascending, then price descending; ties on both fall back to the document ID, which
+	// follows the direction of the last key.
+	slices.SortFunc(items, func(a, b testItem) int {
+		if c := strings.Compare(a.Category, b.Category); c != 0 {
+			return c
+		}
+		if a.Price != b.Price {
+			return int(b.Price - a.Price)
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	q := Query[testItem]{
+		Base:     client.Collection(testCollection).Query,
+		SortKeys: []SortKey{{Path: "category", Direction: firestore.Asc}, {Path: "price", Direction: firestore.Desc}},
+		Decode:   decodeTestItem,
+	}
+
+	var pages [][]string
+	var prevCursors []string
+	next := pagination.Cursor{}
+	for {
+		result, err := Paginate(ctx, codec, q, next, 5, "test", "/items", nil)
+		if err != nil {
+			t.Fatalf("page %d: %v", len(pages), err)
+		}
+		pages = append(pages, ids(result.Items))
+		prevCursors = append(prevCursors, result.PrevCursor)
+		if (len(pages) == 1) != (result.PrevCursor == "") {
+			t.Fatalf("page %d: unexpected prev cursor %q", len(pages), result.PrevCursor)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		if !strings.Contains(result.LinkHeader, `rel="next"`) || !strings.Contains(result.LinkHeader, "limit=5") {
+			t.Fatalf("unexpected Link header %q", result.LinkHeader)
+		}
+		if next, err = codec.Decode(result.NextCursor, nil); err != nil {
+			t.Fatalf("decode next cursor: %v", err)
+		}
+	}
+	if got, want := slices.Concat(pages...), ids(items); !slices.Equal(got, want) {
+		t.Fatalf("expected forward order\n%v\ngot\n%v", want, got)
+	}
+	if len(pages) != 5 || len(pages[4]) != 3 {
+		t.Fatalf("expected four full pages and one of three, got %v", pages)
+	}
+
+	// Each prev cursor must lead back to exactly the page before it.
+	for i := len(pages) - 1; i > 0; i-- {
+		cursor, err := codec.Decode(prevCursors[i], nil)
+		if err != nil {
+			t.Fatalf("decode prev cursor: %v", err)
+		}
+		result, err := Paginate(ctx, codec, q, cursor, 5, "test", "/items", nil)
+		if err != nil {
+			t.Fatalf("prev of page %d: %v", i, err)
+		}
+		if got := ids(result.Items); !slices.Equal(got, pages[i-1]) {
+			t.Fatalf("prev of page %d: expected %v, got %v", i, pages[i-1], got)
+		}
+		if result.NextCursor == "" || (i == 1) != (result.PrevCursor == "") {
+			t.Fatalf("prev of page %d: unexpected cursors next=%q prev=%q", i, result.NextCursor, result.PrevCursor)
+		}
+	}
+
+	// The last page holds the final five items, even though forward pages end with three.
+	last, err := Paginate(ctx, codec, q, pagination.Cursor{Type: "test", Before: true}, 5, "test", "/items", nil)
+	if err != nil {
+		t.Fatalf("last page: %v", err)
+	}
+	all := slices.Concat(pages...)
+	if got := ids(last.Items); !slices.Equal(got, all[len(all)-5:]) {
+		t.Fatalf("last page: expected %v, got %v", all[len(all)-5:], got)
+	}
+	if last.NextCursor != "" || last.PrevCursor == "" || !last.Page.HasPrev {
+		t.Fatalf("last page: unexpected cursors next=%q prev=%q", last.NextCursor, last.PrevCursor)
+	}
+}
+
+func TestPaginateAppliesFilters(t *testing.T) {
+	client := setupFirestore(t)
+	seed(t, client)
+	codec := testutil.Cursors()
+	filter := url.Values{"category": {"tools"}}
+	q := Query[testItem]{
+		Base:     client.Collection(testCollection).Where("category", "==", "tools"),
+		SortKeys: []SortKey{{Path: "created", Direction: firestore.Asc}},
+		Decode:   decodeTestItem,
+	}
+
+	first, err := Paginate(t.Context(), codec, q, pagination.Cursor{}, 10, "test", "/items", filter)
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if got := ids(first.Items); len(got) != 10 || got[0] != "item-000" || got[9] != "item-018" {
+		t.Fatalf("unexpected first page %v", got)
+	}
+	if !strings.Contains(first.LinkHeader, "category=tools") {
+		t.Fatalf("expected filters in Link header, got %q", first.LinkHeader)
+	}
+	if _, err := codec.Decode(first.NextCursor, nil); !errors.Is(err, pagination.ErrCursorFilterMismatch) {
+		t.Fatalf("expected the cursor to be bound to the filter, got %v", err)
+	}
+	cursor, err := codec.Decode(first.NextCursor, filter)
+	if err != nil {
+		t.Fatalf("decode next cursor: %v", err)
+	}
+	second, err := Paginate(t.Context(), codec, q, cursor, 10, "test", "/items", filter)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if got := ids(second.Items); !slices.Equal(got, []string{"item-020", "item-022"}) || second.NextCursor != "" {
+		t.Fatalf("unexpected last page %v next=%q", got, second.NextCursor)
+	}
+}
+
+func TestPaginateRejectsMalformedPositions(t *testing.T) {
+	q := Query[testItem]{
+		SortKeys: []SortKey{{Path: "price", Direction: firestore.Desc}},
+		Decode:   decodeTestItem,
+	}
+	for name, value := range map[string]string{
+		"not JSON":        "item-010",
+		"missing ID":      `{"k":["i:100"]}`,
+		"too few values":  `{"k":[],"id":"item-010"}`,
+		"too many values": `{"k":["i:100","s:x"],"id":"item-010"}`,
+		"untagged value":  `{"k":["100"],"id":"item-010"}`,
+		"unknown type":    `{"k":["x:100"],"id":"item-010"}`,
+		"bad integer":     `{"k":["i:abc"],"id":"item-010"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			cursor := pagination.Cursor{Type: "test", Value: value}
+			_, err := Paginate(t.Context(), testutil.Cursors(), q, cursor, 5, "test", "/items", nil)
+			if !errors.Is(err, pagination.ErrInvalidCursor) {
+				t.Fatalf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPaginateRejectsOtherCursorTypes(t *testing.T) {
+	q := Query[testItem]{
+		SortKeys: []SortKey{{Path: "price", Direction: firestore.Desc}},
+		Decode:   decodeTestItem,
+	}
+	for name, cursor := range map[string]pagination.Cursor{
+		"next":    {Type: "other", Value: `{"k":["i:100"],"id":"item-010"}`},
+		"last":    {Type: "other", Before: true},
+		"untyped": {Value: `{"k":["i:100"],"id":"item-010"}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Paginate(t.Context(), testutil.Cursors(), q, cursor, 5, "test", "/items", nil)
+			if !errors.Is(err, pagination.ErrInvalidCursor) {
+				t.Fatalf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSortValuesRoundTrip(t *testing.T) {
+	for _, value := range []any{
+		nil,
+		"",
+		"a:b",
+		int64(-42),
+		1.5,
+		true,
+		time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC),
+	} {
+		encoded, err := encodeValue(value)
+		if err != nil {
+			t.Fatalf("encode %#v: %v", value, err)
+		}
+		decoded, err := decodeValue(encoded)
+		if err != nil {
+			t.Fatalf("decode %q: %v", encoded, err)
+		}
+		if decoded != value {
+			t.Fatalf("expected %#v, got %#v", value, decoded)
+		}
+	}
+	if _, err := encodeValue([]byte("raw")); err == nil {
+		t.Fatal("expected unsupported sort values to be rejected")
+	}
+}
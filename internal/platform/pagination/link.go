@@ -4,23 +4,103 @@ import (
 	"fmt"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 )
 
+// Link is one RFC 8288 relation of a page. An empty Cursor links the first page.
+type Link struct {
+	Rel    string
+	Cursor string
+}
+
+// Links holds the cursors of the current page and the pages around it. Empty Next, Prev, and Last
+// cursors omit those relations, and First is linked only when set. Self is always linked; its empty
+// cursor is the first page.
+type Links struct {
+	Self  string
+	First bool
+	Prev  string
+	Next  string
+	Last  string
+}
+
+// Header constructs the RFC 8288 Link header, preserving existing query params.
+func (l Links) Header(baseURL string, query url.Values) string {
+	links := []Link{{Rel: "self", Cursor: l.Self}}
+	if l.First {
+		links = append(links, Link{Rel: "first"})
+	}
+	if l.Prev != "" {
+		links = append(links, Link{Rel: "prev", Cursor: l.Prev})
+	}
+	if l.Next != "" {
+		links = append(links, Link{Rel: "next", Cursor: l.Next})
+	}
+	if l.Last != "" {
+		links = append(links, Link{Rel: "last", Cursor: l.Last})
+	}
+	return BuildLinks(baseURL, query, links...)
+}
+
 // BuildLinkHeader constructs RFC 8288 Link header, preserving existing query params.
 func BuildLinkHeader(baseURL string, query url.Values, nextCursor, prevCursor string) string {
-	var links []string
+	var links []Link
 	if nextCursor != "" {
-		q := cloneValues(query)
-		q.Set("cursor", nextCursor)
-		links = append(links, fmt.Sprintf("<%s?%s>; rel=\"next\"", baseURL, q.Encode()))
+		links = append(links, Link{Rel: "next", Cursor: nextCursor})
 	}
 	if prevCursor != "" {
+		links = append(links, Link{Rel: "prev", Cursor: prevCursor})
+	}
+	return BuildLinks(baseURL, query, links...)
+}
+
+// BuildLinks constructs an RFC 8288 Link header with one link per relation, in the given order. The
+// query is preserved in every link; the cursor parameter is replaced, or removed for the first page.
+func BuildLinks(baseURL string, query url.Values, links ...Link) string {
+	out := make([]string, 0, len(links))
+	for _, link := range links {
 		q := cloneValues(query)
-		q.Set("cursor", prevCursor)
-		links = append(links, fmt.Sprintf("<%s?%s>; rel=\"prev\"", baseURL, q.Encode()))
+		if link.Cursor != "" {
+			q.Set("cursor", link.Cursor)
+		} else {
+			q.Del("cursor")
+		}
+		target := baseURL
+		if encoded := q.Encode(); encoded != "" {
+			target += "?" + encoded
+		}
+		out = append(out, fmt.Sprintf("<%s>; rel=\"%s\"", target, link.Rel))
+	}
+	return strings.Join(out, ", ")
+}
+
+// Page describes a returned page for clients that prefer metadata to parsing Link headers. Total is
+// nil when counting the results would cost an extra query.
+type Page struct {
+	Limit   int  `json:"limit"           doc:"Maximum items per page"                     example:"20"`
+	Count   int  `json:"count"           doc:"Number of items in this page"               example:"20"`
+	Total   *int `json:"total,omitempty" doc:"Number of items across all pages, if known" example:"45"`
+	HasNext bool `json:"hasNext"         doc:"Whether a next page exists"                 example:"true"`
+	HasPrev bool `json:"hasPrev"         doc:"Whether a previous page exists"             example:"false"`
+}
+
+// Header returns p as an RFC 9651 structured field dictionary, such as
+// "limit=20, count=20, total=45, next, prev=?0".
+func (p Page) Header() string {
+	members := []string{"limit=" + strconv.Itoa(p.Limit), "count=" + strconv.Itoa(p.Count)}
+	if p.Total != nil {
+		members = append(members, "total="+strconv.Itoa(*p.Total))
+	}
+	return strings.Join(append(members, sfBool("next", p.HasNext), sfBool("prev", p.HasPrev)), ", ")
+}
+
+// sfBool serializes a boolean dictionary member; a bare key is true.
+func sfBool(key string, value bool) string {
+	if value {
+		return key
 	}
-	return strings.Join(links, ", ")
+	return key + "=?0"
 }
 
 func cloneValues(v url.Values) url.Values {
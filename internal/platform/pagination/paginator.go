@@ -2,31 +2,38 @@ package pagination
 
 import (
 	"net/url"
+	"slices"
 	"strconv"
 )
 
-// Result holds the outcome of a pagination operation.
+// Result holds the outcome of a pagination operation. LinkHeader renders Links with the query the
+// cursors are bound to; callers that link other parameters as well can render Links themselves.
 type Result[T any] struct {
 	Items      []T
 	Total      int
+	Links      Links
 	LinkHeader string
 	NextCursor string
 	PrevCursor string
+	Page       Page
 }
 
 // Paginate applies cursor-based pagination to a slice of items.
 //
 // Parameters:
+//   - codec: Signs the next and prev cursors, binding them to query
 //   - items: The full slice of items to paginate
 //   - cursor: The decoded cursor from the request
 //   - limit: Maximum items per page
 //   - cursorType: Type identifier for cursor validation (e.g., "item", "user")
 //   - getID: Function to extract the ID from an item
 //   - baseURL: Base URL path for Link header (e.g., "/items")
-//   - query: Additional query parameters to preserve in links
+//   - query: Filter parameters to preserve in links and bind to the cursors
 //
-// Returns a Result containing the page of items and pagination metadata.
+// Returns a Result containing the page of items and pagination metadata. A cursor whose ID is not
+// found restarts from the first page.
 func Paginate[T any](
+	codec *Codec,
 	items []T,
 	cursor Cursor,
 	limit int,
@@ -35,48 +42,119 @@ func Paginate[T any](
 	baseURL string,
 	query url.Values,
 ) Result[T] {
-	total := len(items)
-
-	startIdx := 0
+	idx := -1
 	if cursor.Value != "" {
-		for i, item := range items {
-			if getID(item) == cursor.Value {
-				startIdx = i + 1
-				break
-			}
-		}
+		idx = slices.IndexFunc(items, func(item T) bool { return getID(item) == cursor.Value })
+	}
+	startIdx, endIdx := 0, min(limit, len(items))
+	switch {
+	case cursor.Before && cursor.Value == "":
+		startIdx, endIdx = max(len(items)-limit, 0), len(items)
+	case idx < 0:
+		// No cursor, or an unknown ID: the first page.
+	case cursor.Before:
+		startIdx, endIdx = max(idx-limit, 0), idx
+	default:
+		startIdx, endIdx = idx+1, min(idx+1+limit, len(items))
 	}
+	return page(codec, items, startIdx, endIdx, limit, cursor, cursorType, getID, baseURL, query)
+}
 
-	endIdx := min(startIdx+limit, total)
+// PaginateAfter applies keyset pagination to items already in their final order. Cursors hold the
+// position of an item, typically its sort key and ID, and a page resumes at the first item for which
+// after reports true. Unlike Paginate, the page stays correct when the cursor's item has since moved
+// or been removed.
+//
+// A prev cursor selects the items before its position: those for which after reports false, other
+// than the item at the position itself.
+func PaginateAfter[T any](
+	codec *Codec,
+	items []T,
+	cursor Cursor,
+	limit int,
+	cursorType string,
+	position func(T) string,
+	after func(T) bool,
+	baseURL string,
+	query url.Values,
+) Result[T] {
+	startIdx, endIdx := 0, min(limit, len(items))
+	switch {
+	case cursor.Before && cursor.Value == "":
+		startIdx, endIdx = max(len(items)-limit, 0), len(items)
+	case cursor.Value == "":
+		// The first page.
+	default:
+		idx := slices.IndexFunc(items, after)
+		if idx < 0 {
+			idx = len(items)
+		}
+		if !cursor.Before {
+			startIdx, endIdx = idx, min(idx+limit, len(items))
+			break
+		}
+		if idx > 0 && position(items[idx-1]) == cursor.Value {
+			idx--
+		}
+		startIdx, endIdx = max(idx-limit, 0), idx
+	}
+	return page(codec, items, startIdx, endIdx, limit, cursor, cursorType, position, baseURL, query)
+}
 
+// page builds the result for items[startIdx:endIdx]. Next and prev cursors name the items just
+// inside the page, so each neighbour is exactly the limit items beyond them; the last page is a
+// prev cursor without a position.
+func page[T any](
+	codec *Codec,
+	items []T,
+	startIdx int,
+	endIdx int,
+	limit int,
+	cursor Cursor,
+	cursorType string,
+	getID func(T) string,
+	baseURL string,
+	query url.Values,
+) Result[T] {
+	total := len(items)
 	pageItems := items[startIdx:endIdx]
 
-	var nextCursor, prevCursor string
-
+	var links Links
+	if cursor.Value != "" || cursor.Before {
+		links.Self = codec.Encode(Cursor{Type: cursorType, Value: cursor.Value, Before: cursor.Before}, query)
+	}
 	if endIdx < total && len(pageItems) > 0 {
-		nextCursor = Cursor{Type: cursorType, Value: getID(pageItems[len(pageItems)-1])}.Encode()
+		links.Next = codec.Encode(Cursor{Type: cursorType, Value: getID(pageItems[len(pageItems)-1])}, query)
+		links.Last = codec.Encode(Cursor{Type: cursorType, Before: true}, query)
+	}
+	if startIdx > 0 || (len(pageItems) == 0 && endIdx < total) {
+		links.First = true
 	}
-
 	if startIdx > 0 {
-		if startIdx <= limit {
-			prevCursor = Cursor{Type: cursorType, Value: ""}.Encode()
-		} else {
-			prevLastIdx := startIdx - 1
-			prevCursor = Cursor{Type: cursorType, Value: getID(items[prevLastIdx-limit])}.Encode()
+		prev := Cursor{Type: cursorType, Before: true}
+		if startIdx < total {
+			prev.Value = getID(items[startIdx])
 		}
+		links.Prev = codec.Encode(prev, query)
 	}
 
 	q := cloneValues(query)
 	if limit > 0 {
 		q.Set("limit", strconv.Itoa(limit))
 	}
-	linkHeader := BuildLinkHeader(baseURL, q, nextCursor, prevCursor)
 
 	return Result[T]{
 		Items:      pageItems,
 		Total:      total,
-		LinkHeader: linkHeader,
-		NextCursor: nextCursor,
-		PrevCursor: prevCursor,
+		Links:      links,
+		LinkHeader: links.Header(baseURL, q),
+		NextCursor: links.Next,
+		PrevCursor: links.Prev,
+		Page: Page{
+			Limit:   limit,
+			Count:   len(pageItems),
+			HasNext: links.Next != "",
+			HasPrev: links.Prev != "",
+		},
 	}
 }
@@ -3,6 +3,7 @@ package pagination
 import (
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -16,6 +17,7 @@ func TestPaginateFirstPage(t *testing.T) {
 	items := makeTestItems(30)
 
 	result := Paginate(
+		testCodec(t),
 		items,
 		Cursor{},
 		10,
@@ -47,6 +49,7 @@ func TestPaginateMiddlePage(t *testing.T) {
 
 	cursor := Cursor{Type: "test", Value: "item-010"}
 	result := Paginate(
+		testCodec(t),
 		items,
 		cursor,
 		10,
@@ -75,6 +78,7 @@ func TestPaginateLastPage(t *testing.T) {
 
 	cursor := Cursor{Type: "test", Value: "item-020"}
 	result := Paginate(
+		testCodec(t),
 		items,
 		cursor,
 		10,
@@ -102,6 +106,7 @@ func TestPaginateEmptyItems(t *testing.T) {
 	var items []testItem
 
 	result := Paginate(
+		testCodec(t),
 		items,
 		Cursor{},
 		10,
@@ -132,6 +137,7 @@ func TestPaginateWithQueryParams(t *testing.T) {
 	query.Set("category", "electronics")
 
 	result := Paginate(
+		testCodec(t),
 		items,
 		Cursor{},
 		10,
@@ -157,6 +163,7 @@ func TestPaginateCursorNotFound(t *testing.T) {
 
 	cursor := Cursor{Type: "test", Value: "nonexistent"}
 	result := Paginate(
+		testCodec(t),
 		items,
 		cursor,
 		10,
@@ -179,6 +186,7 @@ func TestPaginatePrevCursorFirstPage(t *testing.T) {
 
 	cursor := Cursor{Type: "test", Value: "item-010"}
 	result := Paginate(
+		testCodec(t),
 		items,
 		cursor,
 		10,
@@ -192,12 +200,29 @@ func TestPaginatePrevCursorFirstPage(t *testing.T) {
 		t.Fatal("expected prev cursor for page 2")
 	}
 
-	prevDecoded, err := DecodeCursor(result.PrevCursor)
+	prevDecoded, err := testCodec(t).Decode(result.PrevCursor, nil)
 	if err != nil {
 		t.Fatalf("failed to decode prev cursor: %v", err)
 	}
-	if prevDecoded.Value != "" {
-		t.Fatalf("expected empty prev cursor value for going back to page 1, got %s", prevDecoded.Value)
+	if !prevDecoded.Before || prevDecoded.Value != "item-011" {
+		t.Fatalf("expected a backward prev cursor before item-011, got %+v", prevDecoded)
+	}
+
+	prev := Paginate(
+		testCodec(t),
+		items,
+		prevDecoded,
+		10,
+		"test",
+		func(i testItem) string { return i.ID },
+		"/items",
+		nil,
+	)
+	if len(prev.Items) != 10 || prev.Items[0].ID != "item-001" || prev.Items[9].ID != "item-010" {
+		t.Fatalf("expected page 1 to be item-001 through item-010, got %+v", prev.Items)
+	}
+	if prev.PrevCursor != "" {
+		t.Fatalf("expected no prev cursor on page 1, got %s", prev.PrevCursor)
 	}
 }
 
@@ -206,6 +231,7 @@ func TestPaginatePrevCursorThirdPage(t *testing.T) {
 
 	cursor := Cursor{Type: "test", Value: "item-020"}
 	result := Paginate(
+		testCodec(t),
 		items,
 		cursor,
 		10,
@@ -219,19 +245,117 @@ func TestPaginatePrevCursorThirdPage(t *testing.T) {
 		t.Fatal("expected prev cursor for page 3")
 	}
 
-	prevDecoded, err := DecodeCursor(result.PrevCursor)
+	prevDecoded, err := testCodec(t).Decode(result.PrevCursor, nil)
 	if err != nil {
 		t.Fatalf("failed to decode prev cursor: %v", err)
 	}
-	if prevDecoded.Value != "item-010" {
-		t.Fatalf("expected prev cursor to point to item-010, got %s", prevDecoded.Value)
+	if !prevDecoded.Before || prevDecoded.Value != "item-021" {
+		t.Fatalf("expected a backward prev cursor before item-021, got %+v", prevDecoded)
+	}
+}
+
+func TestPaginateBackwardIsExactForUnalignedPages(t *testing.T) {
+	items := makeTestItems(30)
+	getID := func(i testItem) string { return i.ID }
+
+	// The client paged forward with limit 7 and then went back with limit 5.
+	cursor := Cursor{Type: "test", Value: "item-008", Before: true}
+	result := Paginate(testCodec(t), items, cursor, 5, "test", getID, "/items", nil)
+
+	if len(result.Items) != 5 || result.Items[0].ID != "item-003" || result.Items[4].ID != "item-007" {
+		t.Fatalf("expected item-003 through item-007, got %+v", result.Items)
+	}
+	next, err := testCodec(t).Decode(result.NextCursor, nil)
+	if err != nil || next.Before || next.Value != "item-007" {
+		t.Fatalf("expected a forward next cursor after item-007, got %+v %v", next, err)
+	}
+	prev, err := testCodec(t).Decode(result.PrevCursor, nil)
+	if err != nil || !prev.Before || prev.Value != "item-003" {
+		t.Fatalf("expected a backward prev cursor before item-003, got %+v %v", prev, err)
+	}
+
+	result = Paginate(testCodec(t), items, prev, 5, "test", getID, "/items", nil)
+	if len(result.Items) != 2 || result.Items[0].ID != "item-001" || result.PrevCursor != "" {
+		t.Fatalf("expected the short first page item-001 and item-002, got %+v", result)
+	}
+}
+
+func TestPaginateLastCursor(t *testing.T) {
+	items := makeTestItems(25)
+	getID := func(i testItem) string { return i.ID }
+
+	first := Paginate(testCodec(t), items, Cursor{}, 10, "test", getID, "/items", nil)
+	if !strings.Contains(first.LinkHeader, `rel="last"`) {
+		t.Fatalf("expected a last link, got %q", first.LinkHeader)
+	}
+
+	last := Paginate(testCodec(t), items, Cursor{Type: "test", Before: true}, 10, "test", getID, "/items", nil)
+	if len(last.Items) != 10 || last.Items[0].ID != "item-016" || last.Items[9].ID != "item-025" {
+		t.Fatalf("expected the last 10 items, got %+v", last.Items)
+	}
+	if last.NextCursor != "" || strings.Contains(last.LinkHeader, `rel="last"`) {
+		t.Fatalf("expected no next or last link on the last page, got %q", last.LinkHeader)
+	}
+	if !last.Page.HasPrev || last.Page.HasNext || last.Page.Count != 10 {
+		t.Fatalf("unexpected page metadata %+v", last.Page)
+	}
+}
+
+func TestPaginateLinkRelations(t *testing.T) {
+	items := makeTestItems(30)
+	getID := func(i testItem) string { return i.ID }
+	query := url.Values{"filter": {"active"}}
+
+	first := Paginate(testCodec(t), items, Cursor{}, 10, "test", getID, "/items", query)
+	if rels := linkRels(first.LinkHeader); !slices.Equal(rels, []string{"self", "next", "last"}) {
+		t.Fatalf("expected self, next, and last on the first page, got %v in %q", rels, first.LinkHeader)
+	}
+	if !strings.Contains(first.LinkHeader, `</items?filter=active&limit=10>; rel="self"`) {
+		t.Fatalf("expected the first page to link itself without a cursor, got %q", first.LinkHeader)
+	}
+
+	middle := Paginate(testCodec(t), items, Cursor{Type: "test", Value: "item-010"}, 10, "test", getID, "/items", query)
+	rels := linkRels(middle.LinkHeader)
+	if !slices.Equal(rels, []string{"self", "first", "prev", "next", "last"}) {
+		t.Fatalf("expected every relation on a middle page, got %v in %q", rels, middle.LinkHeader)
+	}
+	if !strings.Contains(middle.LinkHeader, `</items?filter=active&limit=10>; rel="first"`) {
+		t.Fatalf("expected the first link to drop the cursor, got %q", middle.LinkHeader)
+	}
+}
+
+func TestPageHeader(t *testing.T) {
+	total := 45
+	tests := []struct {
+		page Page
+		want string
+	}{
+		{Page{Limit: 20, Count: 20, Total: &total, HasNext: true}, "limit=20, count=20, total=45, next, prev=?0"},
+		{Page{Limit: 20, Count: 5, HasPrev: true}, "limit=20, count=5, next=?0, prev"},
+	}
+	for _, tt := range tests {
+		if got := tt.page.Header(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func linkRels(header string) []string {
+	var rels []string
+	for part := range strings.SplitSeq(header, ", ") {
+		_, rel, ok := strings.Cut(part, `rel="`)
+		if ok {
+			rels = append(rels, strings.TrimSuffix(rel, `"`))
+		}
 	}
+	return rels
 }
 
 func TestPaginateLimitLargerThanItems(t *testing.T) {
 	items := makeTestItems(5)
 
 	result := Paginate(
+		testCodec(t),
 		items,
 		Cursor{},
 		20,
@@ -252,6 +376,84 @@ func TestPaginateLimitLargerThanItems(t *testing.T) {
 	}
 }
 
+func TestPaginateAfterResumesPastRemovedItem(t *testing.T) {
+	items := makeTestItems(30)
+	// item-010 ended the previous page and has since been removed.
+	items = slices.Delete(items, 9, 10)
+
+	cursor := Cursor{Type: "test", Value: "item-010"}
+	result := PaginateAfter(
+		testCodec(t),
+		items,
+		cursor,
+		10,
+		"test",
+		func(i testItem) string { return i.ID },
+		func(i testItem) bool { return i.ID > cursor.Value },
+		"/items",
+		nil,
+	)
+
+	if len(result.Items) != 10 || result.Items[0].ID != "item-011" || result.Items[9].ID != "item-020" {
+		t.Fatalf("expected item-011 through item-020, got %+v", result.Items)
+	}
+	next, err := testCodec(t).Decode(result.NextCursor, nil)
+	if err != nil || next.Value != "item-020" {
+		t.Fatalf("expected next cursor at item-020, got %+v %v", next, err)
+	}
+	if result.PrevCursor == "" {
+		t.Fatal("expected prev cursor for page 2")
+	}
+}
+
+func TestPaginateAfterPastEnd(t *testing.T) {
+	items := makeTestItems(5)
+
+	result := PaginateAfter(
+		testCodec(t),
+		items,
+		Cursor{Type: "test", Value: "item-999"},
+		10,
+		"test",
+		func(i testItem) string { return i.ID },
+		func(i testItem) bool { return i.ID > "item-999" },
+		"/items",
+		nil,
+	)
+
+	if len(result.Items) != 0 || result.NextCursor != "" {
+		t.Fatalf("expected an empty last page, got %+v", result)
+	}
+	prev, err := testCodec(t).Decode(result.PrevCursor, nil)
+	if err != nil || !prev.Before || prev.Value != "" {
+		t.Fatalf("expected a prev cursor to the last page, got %+v %v", prev, err)
+	}
+}
+
+func TestPaginateAfterBackward(t *testing.T) {
+	items := makeTestItems(30)
+	position := func(i testItem) string { return i.ID }
+
+	for _, tc := range []struct {
+		name  string
+		value string
+		first string
+	}{
+		{"existing item", "item-016", "item-011"},
+		// item-016 started the page and has since been removed.
+		{"removed item", "item-016-removed", "item-012"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor := Cursor{Type: "test", Value: tc.value, Before: true}
+			result := PaginateAfter(testCodec(t), items, cursor, 5, "test", position,
+				func(i testItem) bool { return i.ID > tc.value }, "/items", nil)
+			if len(result.Items) != 5 || result.Items[0].ID != tc.first {
+				t.Fatalf("expected 5 items from %s, got %+v", tc.first, result.Items)
+			}
+		})
+	}
+}
+
 func makeTestItems(count int) []testItem {
 	items := make([]testItem, count)
 	for i := range count {
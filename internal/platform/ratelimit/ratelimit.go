@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
+
+	"github.com/janisto/huma-playground/internal/platform/auth"
+)
+
+// PolicyFunc selects the policy for an operation. A policy that is not Enabled leaves the
+// operation unlimited.
+type PolicyFunc func(op *huma.Operation) Policy
+
+// Limiter enforces per-client rate-limit policies on Huma operations.
+type Limiter struct {
+	store      Store
+	policy     PolicyFunc
+	authPolicy Policy
+}
+
+// New creates a limiter that keeps its buckets in store. authPolicy limits requests to protected
+// operations per client IP before their tokens are verified; a policy that is not Enabled skips it.
+func New(store Store, policy PolicyFunc, authPolicy Policy) *Limiter {
+	return &Limiter{store: store, policy: policy, authPolicy: authPolicy}
+}
+
+// Middleware takes a token for each request and answers 429 Problem Details with Retry-After when
+// the client's bucket is empty. Clients are keyed by Firebase UID when authenticated and by the
+// client IP otherwise, so it must run after authentication and after chi's ClientIP middleware.
+// Every limited response carries the draft IETF RateLimit and RateLimit-Policy headers.
+//
+// If the store fails, the request is allowed: losing rate limiting is preferable to losing the API.
+func (l *Limiter) Middleware(api huma.API) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		l.take(api, ctx, next, l.policy(ctx.Operation()), clientKey(ctx))
+	}
+}
+
+// AuthMiddleware applies the auth policy to protected operations by client IP. It must run before
+// authentication, so floods of invalid or expired tokens are answered with 429 instead of each
+// costing a token verification. Authenticated requests then also pass through Middleware.
+func (l *Limiter) AuthMiddleware(api huma.API) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if !auth.IsProtected(ctx.Operation()) {
+			next(ctx)
+			return
+		}
+		l.take(api, ctx, next, l.authPolicy, "ip:"+chimiddleware.GetClientIP(ctx.Context()))
+	}
+}
+
+func (l *Limiter) take(api huma.API, ctx huma.Context, next func(huma.Context), policy Policy, key string) {
+	if !policy.Enabled() {
+		next(ctx)
+		return
+	}
+
+	decision, err := l.store.Take(ctx.Context(), policy.Name+"|"+key, policy)
+	if err != nil {
+		obs.Logger(ctx.Context()).Warn("rate limit store failed; allowing request",
+			zap.String("policy", policy.Name), zap.Error(err))
+		next(ctx)
+		return
+	}
+
+	ctx.SetHeader("RateLimit-Policy", fmt.Sprintf("%q;q=%d;w=%d",
+		policy.Name, policy.Limit, wholeSeconds(policy.Window)))
+	ctx.SetHeader("RateLimit", fmt.Sprintf("%q;r=%d;t=%d",
+		policy.Name, decision.Remaining, wholeSeconds(decision.Reset)))
+	if decision.Allowed {
+		next(ctx)
+		return
+	}
+
+	obs.Logger(ctx.Context()).Info("rate limit exceeded", zap.String("policy", policy.Name))
+	ctx.SetHeader("Retry-After", strconv.FormatInt(max(wholeSeconds(decision.RetryAfter), 1), 10))
+	if err := huma.WriteErr(api, ctx, http.StatusTooManyRequests, "rate limit exceeded"); err != nil {
+		obs.Logger(ctx.Context()).Error("write rate limit error", zap.Error(err))
+	}
+}
+
+// DocumentResponses adds the 429 response to every operation either middleware applies to. Call it before
+// operations are registered.
+func (l *Limiter) DocumentResponses(api huma.API) {
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if !l.policy(op).Enabled() && !(l.authPolicy.Enabled() && auth.IsProtected(op)) {
+			return
+		}
+		if op.Responses == nil {
+			op.Responses = map[string]*huma.Response{}
+		}
+		status := strconv.Itoa(http.StatusTooManyRequests)
+		response := op.Responses[status]
+		if response == nil {
+			errType := reflect.TypeOf(huma.NewError(http.StatusTooManyRequests, ""))
+			response = &huma.Response{
+				Description: http.StatusText(http.StatusTooManyRequests),
+				Content: map[string]*huma.MediaType{
+					"application/problem+json": {Schema: oapi.Components.Schemas.Schema(errType, true, "")},
+				},
+			}
+			op.Responses[status] = response
+		}
+		if response.Headers == nil {
+			response.Headers = map[string]*huma.Param{}
+		}
+		response.Headers["Retry-After"] = &huma.Param{
+			Description: "Seconds until the request may be retried.",
+			Schema:      &huma.Schema{Type: huma.TypeInteger},
+		}
+	})
+}
+
+func clientKey(ctx huma.Context) string {
+	if user := auth.UserFromContext(ctx.Context()); user != nil {
+		return "user:" + user.UID
+	}
+	return "ip:" + chimiddleware.GetClientIP(ctx.Context())
+}
+
+func wholeSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,213 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/janisto/huma-playground/internal/platform/auth"
+)
+
+type pingOutput struct {
+	Body struct {
+		OK bool `json:"ok"`
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Policy) (Decision, error) {
+	return Decision{}, errors.New("store unavailable")
+}
+
+// tokenVerifier accepts any token but "invalid" and uses it as the UID. It counts verifications.
+type tokenVerifier struct {
+	calls *atomic.Int64
+}
+
+func (v tokenVerifier) Verify(_ context.Context, token string) (*auth.FirebaseUser, error) {
+	if v.calls != nil {
+		v.calls.Add(1)
+	}
+	if token == "invalid" {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.FirebaseUser{UID: token}, nil
+}
+
+func newTestAPI(limiter *Limiter) (http.Handler, huma.API) {
+	return newTestAPIWithVerifier(limiter, tokenVerifier{})
+}
+
+func newTestAPIWithVerifier(limiter *Limiter, verifier auth.Verifier) (http.Handler, huma.API) {
+	router := chi.NewRouter()
+	router.Use(chimiddleware.ClientIPFromRemoteAddr)
+	api := humachi.New(router, huma.DefaultConfig("RateLimitTest", "test"))
+	limiter.DocumentResponses(api)
+	api.UseMiddleware(limiter.AuthMiddleware(api), auth.NewAuthMiddleware(api, verifier), limiter.Middleware(api))
+	for _, op := range []huma.Operation{
+		{OperationID: "get-ping", Method: http.MethodGet, Path: "/ping"},
+		{
+			OperationID: "get-me",
+			Method:      http.MethodGet,
+			Path:        "/me",
+			Security:    []map[string][]string{{auth.BearerAuthScheme: {}}},
+		},
+		{OperationID: "get-open", Method: http.MethodGet, Path: "/open"},
+	} {
+		huma.Register(api, op, func(context.Context, *struct{}) (*pingOutput, error) {
+			return &pingOutput{}, nil
+		})
+	}
+	return router, api
+}
+
+func testPolicy(op *huma.Operation) Policy {
+	if op.OperationID == "get-open" {
+		return Policy{}
+	}
+	return Policy{Name: "default", Limit: 2, Window: time.Minute}
+}
+
+func serve(t *testing.T, handler http.Handler, path, remoteAddr, uid string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if uid != "" {
+		req.Header.Set("Authorization", "Bearer "+uid)
+	}
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestMiddlewareSetsRateLimitHeaders(t *testing.T) {
+	handler, _ := newTestAPI(New(NewMemoryStore(), testPolicy, Policy{}))
+
+	resp := serve(t, handler, "/ping", "192.0.2.1:1234", "")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+	if got := resp.Header().Get("RateLimit-Policy"); got != `"default";q=2;w=60` {
+		t.Errorf("unexpected RateLimit-Policy %q", got)
+	}
+	if got := resp.Header().Get("RateLimit"); got != `"default";r=1;t=30` {
+		t.Errorf("unexpected RateLimit %q", got)
+	}
+}
+
+func TestMiddlewareRejectsWithProblemDetails(t *testing.T) {
+	handler, _ := newTestAPI(New(NewMemoryStore(), testPolicy, Policy{}))
+
+	serve(t, handler, "/ping", "192.0.2.1:1234", "")
+	serve(t, handler, "/ping", "192.0.2.1:1234", "")
+	resp := serve(t, handler, "/ping", "192.0.2.1:1234", "")
+	if resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.Code)
+	}
+	if got := resp.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("expected Retry-After 30, got %q", got)
+	}
+	if got := resp.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("expected Problem Details, got %q", got)
+	}
+	var problem huma.ErrorModel
+	if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Status != http.StatusTooManyRequests || problem.Detail != "rate limit exceeded" {
+		t.Errorf("unexpected problem %#v", problem)
+	}
+}
+
+func TestMiddlewareKeysByUserThenClientIP(t *testing.T) {
+	handler, _ := newTestAPI(New(NewMemoryStore(), testPolicy, Policy{}))
+
+	for range 2 {
+		serve(t, handler, "/ping", "192.0.2.1:1234", "")
+	}
+	if resp := serve(t, handler, "/ping", "192.0.2.1:1234", ""); resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected IP bucket to be exhausted, got %d", resp.Code)
+	}
+	if resp := serve(t, handler, "/ping", "192.0.2.2:1234", ""); resp.Code != http.StatusOK {
+		t.Fatalf("expected another IP to have its own bucket, got %d", resp.Code)
+	}
+	// An authenticated user behind the exhausted IP is keyed by UID instead.
+	if resp := serve(t, handler, "/me", "192.0.2.1:1234", "user-1"); resp.Code != http.StatusOK {
+		t.Fatalf("expected user bucket to be independent of IP, got %d", resp.Code)
+	}
+}
+
+func TestAuthMiddlewareLimitsTokensBeforeVerification(t *testing.T) {
+	var calls atomic.Int64
+	authPolicy := Policy{Name: "auth", Limit: 2, Window: time.Minute}
+	handler, _ := newTestAPIWithVerifier(New(NewMemoryStore(), testPolicy, authPolicy), tokenVerifier{calls: &calls})
+
+	for range 2 {
+		if resp := serve(t, handler, "/me", "192.0.2.1:1234", "invalid"); resp.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", resp.Code)
+		}
+	}
+	resp := serve(t, handler, "/me", "192.0.2.1:1234", "invalid")
+	if resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected invalid tokens to be limited, got %d", resp.Code)
+	}
+	if got := resp.Header().Get("RateLimit-Policy"); got != `"auth";q=2;w=60` {
+		t.Errorf("unexpected RateLimit-Policy %q", got)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected the limited request not to be verified, got %d verifications", calls.Load())
+	}
+	// The auth bucket is per IP and applies only to protected operations.
+	if resp := serve(t, handler, "/me", "192.0.2.2:1234", "user-1"); resp.Code != http.StatusOK {
+		t.Fatalf("expected another IP to have its own bucket, got %d", resp.Code)
+	}
+	if resp := serve(t, handler, "/ping", "192.0.2.1:1234", ""); resp.Code != http.StatusOK {
+		t.Fatalf("expected unprotected operations to skip the auth bucket, got %d", resp.Code)
+	}
+}
+
+func TestMiddlewareSkipsDisabledPolicies(t *testing.T) {
+	handler, _ := newTestAPI(New(NewMemoryStore(), testPolicy, Policy{}))
+
+	for range 3 {
+		resp := serve(t, handler, "/open", "192.0.2.1:1234", "")
+		if resp.Code != http.StatusOK || resp.Header().Get("RateLimit") != "" {
+			t.Fatalf("expected unlimited response, got %d", resp.Code)
+		}
+	}
+}
+
+func TestMiddlewareFailsOpenWhenStoreFails(t *testing.T) {
+	handler, _ := newTestAPI(New(failingStore{}, testPolicy, Policy{}))
+
+	if resp := serve(t, handler, "/ping", "192.0.2.1:1234", ""); resp.Code != http.StatusOK {
+		t.Fatalf("expected request to be allowed, got %d", resp.Code)
+	}
+}
+
+func TestDocumentResponsesAddsTooManyRequests(t *testing.T) {
+	_, api := newTestAPI(New(NewMemoryStore(), testPolicy, Policy{}))
+
+	ping := api.OpenAPI().Paths["/ping"].Get
+	response := ping.Responses["429"]
+	if response == nil {
+		t.Fatal("expected 429 response on limited operation")
+	}
+	if response.Content["application/problem+json"] == nil || response.Headers["Retry-After"] == nil {
+		t.Errorf("expected problem content and Retry-After header, got %#v", response)
+	}
+	if open := api.OpenAPI().Paths["/open"].Get; open.Responses["429"] != nil {
+		t.Error("expected no 429 response on unlimited operation")
+	}
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops buckets that have refilled, bounding memory to
+// recently active clients.
+const sweepInterval = time.Minute
+
+// Policy is a token bucket: Limit requests may burst at once, refilling at Limit per Window.
+type Policy struct {
+	// Name identifies the policy in RateLimit headers and keeps its buckets separate from other policies.
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// Enabled reports whether the policy limits requests.
+func (p Policy) Enabled() bool {
+	return p.Limit > 0 && p.Window > 0
+}
+
+// Decision is the outcome of taking one token from a bucket.
+type Decision struct {
+	Allowed   bool
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until a denied request would be allowed.
+	RetryAfter time.Duration
+}
+
+// Store holds token buckets. Implementations backed by a shared database can replace MemoryStore
+// when the API runs as several instances.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Decision, error)
+}
+
+// MemoryStore is a process-local Store. Limits are per instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	window  time.Duration
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take refills the bucket for key by the time elapsed since its last use and takes one token.
+func (s *MemoryStore) Take(_ context.Context, key string, policy Policy) (Decision, error) {
+	capacity := float64(policy.Limit)
+	rate := capacity / policy.Window.Seconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity}
+		s.buckets[key] = b
+	} else {
+		b.tokens = min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	}
+	b.updated = now
+	b.window = policy.Window
+
+	var decision Decision
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = seconds((capacity - b.tokens) / rate)
+	return decision, nil
+}
+
+// sweep removes buckets idle for a full window; they would be full, which is the same as absent.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.window {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
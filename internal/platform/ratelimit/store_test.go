@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStoreAllowsBurstThenDenies(t *testing.T) {
+	store, _ := newTestStore()
+	policy := Policy{Name: "default", Limit: 3, Window: time.Minute}
+
+	for i := range 3 {
+		decision, err := store.Take(t.Context(), "client", policy)
+		if err != nil {
+			t.Fatalf("take: %v", err)
+		}
+		if !decision.Allowed || decision.Remaining != 2-i {
+			t.Fatalf("request %d: unexpected decision %#v", i, decision)
+		}
+	}
+
+	decision, err := store.Take(t.Context(), "client", policy)
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if decision.Allowed || decision.Remaining != 0 {
+		t.Fatalf("expected denial, got %#v", decision)
+	}
+	if decision.RetryAfter != 20*time.Second {
+		t.Errorf("expected one token after 20s, got %v", decision.RetryAfter)
+	}
+	if decision.Reset != time.Minute {
+		t.Errorf("expected full bucket after 1m, got %v", decision.Reset)
+	}
+}
+
+func TestMemoryStoreRefillsOverTime(t *testing.T) {
+	store, clock := newTestStore()
+	policy := Policy{Name: "default", Limit: 2, Window: time.Minute}
+
+	for range 2 {
+		_, _ = store.Take(t.Context(), "client", policy)
+	}
+	clock.now = clock.now.Add(30 * time.Second)
+	if decision, _ := store.Take(t.Context(), "client", policy); !decision.Allowed {
+		t.Fatalf("expected one refilled token, got %#v", decision)
+	}
+	if decision, _ := store.Take(t.Context(), "client", policy); decision.Allowed {
+		t.Fatalf("expected bucket to be empty again, got %#v", decision)
+	}
+}
+
+func TestMemoryStoreKeepsKeysSeparate(t *testing.T) {
+	store, _ := newTestStore()
+	policy := Policy{Name: "default", Limit: 1, Window: time.Minute}
+
+	if decision, _ := store.Take(t.Context(), "a", policy); !decision.Allowed {
+		t.Fatal("expected first client to be allowed")
+	}
+	if decision, _ := store.Take(t.Context(), "b", policy); !decision.Allowed {
+		t.Fatal("expected second client to have its own bucket")
+	}
+}
+
+func TestMemoryStoreSweepsRefilledBuckets(t *testing.T) {
+	store, clock := newTestStore()
+	policy := Policy{Name: "default", Limit: 1, Window: time.Minute}
+
+	_, _ = store.Take(t.Context(), "idle", policy)
+	clock.now = clock.now.Add(2 * time.Minute)
+	_, _ = store.Take(t.Context(), "active", policy)
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Fatal("expected idle bucket to be swept")
+	}
+	if len(store.buckets) != 1 {
+		t.Fatalf("expected only the active bucket, got %d", len(store.buckets))
+	}
+}
+
+func TestPolicyEnabled(t *testing.T) {
+	if (Policy{Limit: 0, Window: time.Minute}).Enabled() {
+		t.Error("expected zero limit to be disabled")
+	}
+	if !(Policy{Limit: 1, Window: time.Minute}).Enabled() {
+		t.Error("expected positive limit to be enabled")
+	}
+}
@@ -111,6 +111,15 @@ func MethodNotAllowedHandler(api huma.API) http.HandlerFunc {
 	}
 }
 
+// Problem returns a writer for Problem Details responses produced by Chi-level middleware.
+func Problem(api huma.API) appmiddleware.ProblemWriter {
+	return func(w http.ResponseWriter, r *http.Request, status int, detail string) {
+		if err := writeProblem(api, w, r, status, detail); err != nil {
+			obs.Logger(r.Context()).Error("write problem response", zap.Error(err))
+		}
+	}
+}
+
 func writeProblem(api huma.API, w http.ResponseWriter, r *http.Request, status int, detail string) error {
 	appmiddleware.AddVary(w.Header(), "Accept", "Origin")
 	ctx := humachi.NewContext(&huma.Operation{}, r, w)
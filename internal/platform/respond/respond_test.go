@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -14,6 +15,8 @@ import (
 	"github.com/fxamacker/cbor/v2"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
+
+	appmiddleware "github.com/janisto/huma-playground/internal/platform/middleware"
 )
 
 func testAPI() huma.API {
@@ -136,3 +139,49 @@ func TestRecovererAbortsPartiallyWrittenResponse(t *testing.T) {
 	}()
 	handler.ServeHTTP(response, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil))
 }
+
+func TestRecovererReplacesBufferedCompressedResponse(t *testing.T) {
+	api := testAPI()
+	handler := Recoverer(
+		api,
+	)(
+		appmiddleware.Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("partial"))
+			panic("boom")
+		})),
+	)
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	if response.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", response.Code)
+	}
+	if response.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected an unencoded problem, got %q", response.Header().Get("Content-Encoding"))
+	}
+	if !strings.Contains(response.Body.String(), "internal server error") {
+		t.Fatalf("unexpected body %q", response.Body.String())
+	}
+}
+
+func TestRecovererAbortsCommittedCompressedResponse(t *testing.T) {
+	api := testAPI()
+	handler := Recoverer(api)(appmiddleware.Compress(8)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Repeat("streamed", 4)))
+		panic("boom")
+	})))
+	request := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	defer func() {
+		recovered := recover()
+		recoveredErr, ok := recovered.(error)
+		if !ok || !errors.Is(recoveredErr, http.ErrAbortHandler) {
+			t.Fatalf("expected http.ErrAbortHandler, got %v", recovered)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+}
@@ -0,0 +1,280 @@
+// Package stream lets list operations write every record as newline-delimited JSON or a CBOR
+// sequence when the client asks for one, instead of a single paginated body.
+package stream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/negotiation"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/janisto/huma-observability/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// NDJSON is the media type of newline-delimited JSON records.
+	NDJSON = "application/x-ndjson"
+	// CBORSeq is the RFC 8742 media type of concatenated CBOR records.
+	CBORSeq = "application/cbor-seq"
+
+	// RecordKey is the huma.Operation Metadata key for the reflect.Type of the records a list
+	// operation streams. Operations without it are never streamed.
+	RecordKey = "stream.record"
+)
+
+// offered lists the media types negotiated for streaming operations. JSON comes first so it wins
+// ties; a client opts in by preferring a streaming type.
+var offered = []string{"application/json", "application/cbor", NDJSON, CBORSeq}
+
+// bodyTypes maps each streaming type to the single-body type used for errors written before the
+// stream starts.
+var bodyTypes = map[string]string{NDJSON: "application/json", CBORSeq: "application/cbor"}
+
+// cborEncMode matches the encoding of Huma's CBOR format, so streamed records decode like
+// application/cbor bodies.
+var cborEncMode, _ = cbor.EncOptions{
+	Sort:          cbor.SortCanonical,
+	ShortestFloat: cbor.ShortestFloat16,
+	NaNConvert:    cbor.NaNConvert7e00,
+	InfConvert:    cbor.InfConvertFloat16,
+	IndefLength:   cbor.IndefLengthForbidden,
+	Time:          cbor.TimeUnixDynamic,
+	TimeTag:       cbor.EncTagRequired,
+}.EncMode()
+
+type writerKey struct{}
+
+// Negotiate returns NDJSON or CBORSeq when accept prefers it to JSON and CBOR, or "" otherwise.
+func Negotiate(accept string) string {
+	switch mediaType := negotiation.SelectQValueFast(accept, offered); mediaType {
+	case NDJSON, CBORSeq:
+		return mediaType
+	default:
+		return ""
+	}
+}
+
+// Writer writes records to a streamed response. The status and Content-Type are sent with the first
+// record, so a handler can still fail with an ordinary error response before writing one.
+type Writer struct {
+	ctx       huma.Context
+	mediaType string
+	buf       bytes.Buffer
+	claimed   bool
+	started   bool
+	records   int
+	failed    int
+}
+
+// FromContext returns the writer of a request the client asked to stream. A handler that gets one
+// must write its records to it and return a nil output; the stream then ends when the handler returns,
+// even if it wrote no records.
+func FromContext(ctx context.Context) (*Writer, bool) {
+	w, ok := ctx.Value(writerKey{}).(*Writer)
+	if ok {
+		w.claimed = true
+	}
+	return w, ok
+}
+
+// Write encodes one record.
+func (w *Writer) Write(record any) error {
+	w.start()
+	w.buf.Reset()
+	var err error
+	if w.mediaType == NDJSON {
+		enc := json.NewEncoder(&w.buf)
+		enc.SetEscapeHTML(false)
+		err = enc.Encode(record)
+	} else {
+		err = cborEncMode.NewEncoder(&w.buf).Encode(record)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := w.ctx.BodyWriter().Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	w.records++
+	return nil
+}
+
+func (w *Writer) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.ctx.SetHeader("Content-Type", w.mediaType)
+	w.ctx.SetStatus(http.StatusOK)
+}
+
+// Flush sends the records written so far to the client. Call it after each batch, such as an
+// upstream page, so a long export arrives incrementally.
+func (w *Writer) Flush() {
+	if !w.started {
+		return
+	}
+	if rw, ok := w.ctx.BodyWriter().(http.ResponseWriter); ok {
+		_ = http.NewResponseController(rw).Flush()
+	}
+}
+
+// Pages writes the records of every page fetch returns, starting with the page at cursor and
+// flushing after each one, until a page has no next cursor. fetch receives ctx, so the request
+// timeout bounds the whole stream.
+func Pages[T any](
+	ctx context.Context,
+	w *Writer,
+	cursor string,
+	fetch func(ctx context.Context, cursor string) (records []T, next string, err error),
+) error {
+	for {
+		records, next, err := fetch(ctx, cursor)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Middleware gives operations with a RecordKey a Writer when the client prefers NDJSON or a CBOR
+// sequence. Until the first record is written, responses fall back to JSON or CBOR respectively, so
+// validation and upstream errors are ordinary Problem Details. An error after the stream has started
+// cannot change the status, so the connection is aborted instead, which clients see as an
+// incomplete response rather than a clean end of stream.
+func Middleware() func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		if _, ok := ctx.Operation().Metadata[RecordKey]; !ok {
+			next(ctx)
+			return
+		}
+		mediaType := Negotiate(ctx.Header("Accept"))
+		if mediaType == "" {
+			next(ctx)
+			return
+		}
+
+		w := &Writer{ctx: ctx, mediaType: mediaType}
+		next(&streamContext{
+			humaContext: ctx,
+			ctx:         context.WithValue(ctx.Context(), writerKey{}, w),
+			writer:      w,
+		})
+		if w.failed != 0 {
+			obs.Logger(ctx.Context()).Warn("stream aborted after it started",
+				zap.Int("status", w.failed), zap.Int("records", w.records))
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// DocumentResponses adds the streaming media types, described by their record schema, to the
+// successful response of every operation with a RecordKey. Call it before operations are registered.
+func DocumentResponses(api huma.API) {
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		value, ok := op.Metadata[RecordKey]
+		if !ok {
+			return
+		}
+		recordType, ok := value.(reflect.Type)
+		if !ok {
+			panic(op.Method + " " + op.Path + ": " + RecordKey + " must be a reflect.Type")
+		}
+		response := op.Responses["200"]
+		if response == nil {
+			return
+		}
+		if response.Content == nil {
+			response.Content = map[string]*huma.MediaType{}
+		}
+		schema := oapi.Components.Schemas.Schema(recordType, true, "")
+		for _, mediaType := range []string{NDJSON, CBORSeq} {
+			response.Content[mediaType] = &huma.MediaType{Schema: schema}
+		}
+		response.Description = strings.TrimSuffix(response.Description, ".") +
+			". Request " + NDJSON + " or " + CBORSeq + " to receive every record from the cursor onward, one per " +
+			"line or CBOR data item, instead of a page; limit is ignored."
+	})
+}
+
+// humaContext lets streamContext embed huma.Context without the embedded field name colliding with
+// the interface's Context method.
+type humaContext = huma.Context
+
+// streamContext carries the Writer to the handler and keeps the response from being rewritten once
+// the stream has started.
+type streamContext struct {
+	humaContext
+	ctx    context.Context
+	writer *Writer
+}
+
+func (c *streamContext) Context() context.Context {
+	return c.ctx
+}
+
+// Header reports the single-body equivalent of the streaming Accept type, so responses written
+// instead of a stream are negotiated as JSON or CBOR.
+func (c *streamContext) Header(name string) string {
+	if strings.EqualFold(name, "Accept") {
+		return bodyTypes[c.writer.mediaType]
+	}
+	return c.humaContext.Header(name)
+}
+
+// SetStatus starts an empty stream when a handler that claimed the writer succeeds without writing a
+// record, and records errors that arrive after the stream has started.
+func (c *streamContext) SetStatus(status int) {
+	if c.writer.claimed && !c.writer.started && status == http.StatusOK {
+		c.writer.start()
+		return
+	}
+	if c.writer.started {
+		if status >= http.StatusBadRequest {
+			c.writer.failed = status
+		}
+		return
+	}
+	c.humaContext.SetStatus(status)
+}
+
+func (c *streamContext) SetHeader(name, value string) {
+	if !c.writer.started {
+		c.humaContext.SetHeader(name, value)
+	}
+}
+
+func (c *streamContext) AppendHeader(name, value string) {
+	if !c.writer.started {
+		c.humaContext.AppendHeader(name, value)
+	}
+}
+
+func (c *streamContext) BodyWriter() io.Writer {
+	if c.writer.started {
+		return io.Discard
+	}
+	return c.humaContext.BodyWriter()
+}
+
+// Unwrap lets adapter helpers such as humachi.Unwrap reach the router context.
+func (c *streamContext) Unwrap() huma.Context {
+	return c.humaContext
+}
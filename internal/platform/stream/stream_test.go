@@ -0,0 +1,257 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	_ "github.com/danielgtaylor/huma/v2/formats/cbor"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-chi/chi/v5"
+)
+
+type record struct {
+	N int `json:"n"`
+}
+
+type listOutput struct {
+	Body struct {
+		Records []record `json:"records"`
+	}
+}
+
+type listInput struct {
+	FailAt int `query:"failAt"`
+	Pages  int `query:"pages"  default:"3"`
+}
+
+// fetchPages serves pages of two records each; page failAt, if set, fails instead.
+func fetchPages(input *listInput) func(context.Context, string) ([]record, string, error) {
+	return func(_ context.Context, cursor string) ([]record, string, error) {
+		page := 1
+		if cursor != "" {
+			page = int(cursor[0] - '0')
+		}
+		if page == input.FailAt {
+			return nil, "", huma.Error502BadGateway("upstream failed")
+		}
+		next := ""
+		if page < input.Pages {
+			next = string(rune('0' + page + 1))
+		}
+		return []record{{N: page*10 + 1}, {N: page*10 + 2}}, next, nil
+	}
+}
+
+func newTestAPI(t *testing.T) http.Handler {
+	t.Helper()
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("StreamTest", "test"))
+	DocumentResponses(api)
+	api.UseMiddleware(Middleware())
+	handler := func(ctx context.Context, input *listInput) (*listOutput, error) {
+		if w, ok := FromContext(ctx); ok {
+			return nil, Pages(ctx, w, "", fetchPages(input))
+		}
+		out := &listOutput{}
+		out.Body.Records, _, _ = fetchPages(input)(ctx, "")
+		return out, nil
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "list-records",
+		Method:      http.MethodGet,
+		Path:        "/records",
+		Metadata:    map[string]any{RecordKey: reflect.TypeFor[record]()},
+	}, handler)
+	huma.Register(api, huma.Operation{OperationID: "list-plain", Method: http.MethodGet, Path: "/plain"}, handler)
+	return router
+}
+
+func get(t *testing.T, handler http.Handler, path, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	req.Header.Set("Accept", accept)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"*/*", ""},
+		{"application/json", ""},
+		{"application/cbor", ""},
+		{NDJSON, NDJSON},
+		{CBORSeq, CBORSeq},
+		{"application/json, " + NDJSON, ""},
+		{"application/json;q=0.5, " + NDJSON, NDJSON},
+		{CBORSeq + ", application/cbor;q=0.9", CBORSeq},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.accept); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestStreamNDJSONWritesEveryPage(t *testing.T) {
+	resp := get(t, newTestAPI(t), "/records", NDJSON)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if got := resp.Header().Get("Content-Type"); got != NDJSON {
+		t.Fatalf("expected Content-Type %q, got %q", NDJSON, got)
+	}
+	want := "{\"n\":11}\n{\"n\":12}\n{\"n\":21}\n{\"n\":22}\n{\"n\":31}\n{\"n\":32}\n"
+	if got := resp.Body.String(); got != want {
+		t.Fatalf("expected body %q, got %q", want, got)
+	}
+	if !resp.Flushed {
+		t.Fatal("expected the stream to be flushed")
+	}
+}
+
+func TestStreamCBORSequence(t *testing.T) {
+	resp := get(t, newTestAPI(t), "/records?pages=2", CBORSeq)
+
+	if got := resp.Header().Get("Content-Type"); got != CBORSeq {
+		t.Fatalf("expected Content-Type %q, got %q", CBORSeq, got)
+	}
+	dec := cbor.NewDecoder(bytes.NewReader(resp.Body.Bytes()))
+	var got []int
+	for {
+		var r record
+		if err := dec.Decode(&r); err != nil {
+			break
+		}
+		got = append(got, r.N)
+	}
+	if !reflect.DeepEqual(got, []int{11, 12, 21, 22}) {
+		t.Fatalf("expected records 11, 12, 21, 22, got %v", got)
+	}
+}
+
+func TestStreamEmpty(t *testing.T) {
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("StreamTest", "test"))
+	api.UseMiddleware(Middleware())
+	huma.Register(api, huma.Operation{
+		OperationID: "list-none",
+		Method:      http.MethodGet,
+		Path:        "/none",
+		Metadata:    map[string]any{RecordKey: reflect.TypeFor[record]()},
+	}, func(ctx context.Context, _ *struct{}) (*listOutput, error) {
+		if _, ok := FromContext(ctx); !ok {
+			t.Error("expected a stream writer")
+		}
+		return nil, nil
+	})
+
+	resp := get(t, router, "/none", NDJSON)
+
+	if resp.Code != http.StatusOK || resp.Header().Get("Content-Type") != NDJSON || resp.Body.Len() != 0 {
+		t.Fatalf("expected an empty %s stream, got %d %q %q",
+			NDJSON, resp.Code, resp.Header().Get("Content-Type"), resp.Body.String())
+	}
+}
+
+func TestStreamErrorBeforeFirstRecordIsProblemDetails(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{NDJSON, "application/problem+json"},
+		{CBORSeq, "application/problem+cbor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			resp := get(t, newTestAPI(t), "/records?failAt=1", tt.accept)
+
+			if resp.Code != http.StatusBadGateway {
+				t.Fatalf("expected 502, got %d: %s", resp.Code, resp.Body.String())
+			}
+			if got := resp.Header().Get("Content-Type"); got != tt.contentType {
+				t.Fatalf("expected Content-Type %q, got %q", tt.contentType, got)
+			}
+		})
+	}
+}
+
+func TestStreamErrorAfterFirstRecordAborts(t *testing.T) {
+	handler := newTestAPI(t)
+	defer func() {
+		recovered := recover()
+		err, ok := recovered.(error)
+		if !ok || !errors.Is(err, http.ErrAbortHandler) {
+			t.Fatalf("expected http.ErrAbortHandler, got %v", recovered)
+		}
+	}()
+
+	get(t, handler, "/records?failAt=2", NDJSON)
+	t.Fatal("expected the handler to abort")
+}
+
+func TestStreamNotNegotiated(t *testing.T) {
+	handler := newTestAPI(t)
+
+	for _, tt := range []struct{ path, accept string }{
+		{"/records", "application/json"},
+		{"/plain", NDJSON},
+	} {
+		resp := get(t, handler, tt.path, tt.accept)
+		if got := resp.Header().Get("Content-Type"); got != "application/json" {
+			t.Fatalf("%s with Accept %q: expected application/json, got %q", tt.path, tt.accept, got)
+		}
+		var body listOutput
+		if err := json.Unmarshal(resp.Body.Bytes(), &body.Body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if len(body.Body.Records) != 2 {
+			t.Fatalf("expected one page of 2 records, got %d", len(body.Body.Records))
+		}
+	}
+}
+
+func TestDocumentResponses(t *testing.T) {
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("StreamTest", "test"))
+	DocumentResponses(api)
+	huma.Register(api, huma.Operation{
+		OperationID: "list-records",
+		Method:      http.MethodGet,
+		Path:        "/records",
+		Metadata:    map[string]any{RecordKey: reflect.TypeFor[record]()},
+	}, func(context.Context, *struct{}) (*listOutput, error) { return nil, nil })
+	huma.Register(api, huma.Operation{
+		OperationID: "list-plain",
+		Method:      http.MethodGet,
+		Path:        "/plain",
+	}, func(context.Context, *struct{}) (*listOutput, error) { return nil, nil })
+
+	response := api.OpenAPI().Paths["/records"].Get.Responses["200"]
+	for _, mediaType := range []string{NDJSON, CBORSeq} {
+		content := response.Content[mediaType]
+		if content == nil || content.Schema == nil || content.Schema.Ref != "#/components/schemas/Record" {
+			t.Fatalf("expected %s content with the Record schema, got %+v", mediaType, content)
+		}
+	}
+	if !strings.Contains(response.Description, NDJSON) {
+		t.Fatalf("expected the description to mention %s, got %q", NDJSON, response.Description)
+	}
+	if _, ok := api.OpenAPI().Paths["/plain"].Get.Responses["200"].Content[NDJSON]; ok {
+		t.Fatal("expected operations without a record type to keep their content types")
+	}
+}
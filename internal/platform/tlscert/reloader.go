@@ -0,0 +1,105 @@
+package tlscert
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reloader serves a certificate and key pair from disk, replacing it when either file changes, so
+// rotated certificates take effect without a restart.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+
+	mu        sync.Mutex
+	certStamp fileStamp
+	keyStamp  fileStamp
+}
+
+// fileStamp identifies a file version. Stat follows symlinks, so the atomic symlink swaps used by
+// Kubernetes secret volumes change it too.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewReloader loads the pair once and fails if it is unusable.
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate returns the current certificate for tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// TLSConfig returns a server configuration that always presents the current certificate.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: r.GetCertificate}
+}
+
+// Reload loads the pair if either file changed since the last successful load and reports whether it
+// did. On error the previous certificate stays in use.
+func (r *Reloader) Reload() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	certStamp, err := stat(r.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyStamp, err := stat(r.keyFile)
+	if err != nil {
+		return false, err
+	}
+	if r.cert.Load() != nil && certStamp == r.certStamp && keyStamp == r.keyStamp {
+		return false, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("load TLS key pair: %w", err)
+	}
+	r.cert.Store(&cert)
+	r.certStamp, r.keyStamp = certStamp, keyStamp
+	return true, nil
+}
+
+// Watch checks the files every interval until ctx is done. A failed reload is logged and retried on
+// the next tick, which also covers the window where a rotation has replaced only one of the files.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		reloaded, err := r.Reload()
+		switch {
+		case err != nil:
+			logger.Warn("TLS certificate reload failed; keeping current certificate", zap.Error(err))
+		case reloaded:
+			logger.Info("TLS certificate reloaded", zap.String("cert_file", r.certFile))
+		}
+	}
+}
+
+func stat(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, fmt.Errorf("stat TLS file: %w", err)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
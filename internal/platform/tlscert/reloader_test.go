@@ -0,0 +1,158 @@
+package tlscert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// writePair writes a self-signed certificate for commonName and returns the file paths.
+func writePair(t *testing.T, dir, commonName string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{commonName},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	writeFile(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	return certFile, keyFile
+}
+
+// touches makes every written file look newer than the last, even within the filesystem's timestamp
+// granularity.
+var touches atomic.Int64
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	stamp := time.Now().Add(time.Duration(touches.Add(1)) * time.Second)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatalf("touch %s: %v", path, err)
+	}
+}
+
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("get certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestNewReloaderRejectsMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewReloader(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")); err == nil {
+		t.Fatal("expected missing files to be rejected")
+	}
+	certFile, _ := writePair(t, dir, "one.test")
+	if _, err := NewReloader(certFile, certFile); err == nil {
+		t.Fatal("expected a certificate without its key to be rejected")
+	}
+}
+
+func TestReloaderPicksUpRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writePair(t, dir, "one.test")
+	reloader, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	if got := commonName(t, reloader); got != "one.test" {
+		t.Fatalf("expected one.test, got %q", got)
+	}
+	if reloaded, err := reloader.Reload(); err != nil || reloaded {
+		t.Fatalf("expected unchanged files to be skipped, got %v %v", reloaded, err)
+	}
+
+	writePair(t, dir, "two.test")
+	if reloaded, err := reloader.Reload(); err != nil || !reloaded {
+		t.Fatalf("expected rotated files to be loaded, got %v %v", reloaded, err)
+	}
+	if got := commonName(t, reloader); got != "two.test" {
+		t.Fatalf("expected two.test, got %q", got)
+	}
+}
+
+func TestReloaderKeepsCertificateWhenReloadFails(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writePair(t, dir, "one.test")
+	reloader, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	writeFile(t, keyFile, []byte("not a key"))
+	if _, err := reloader.Reload(); err == nil {
+		t.Fatal("expected a mismatched pair to fail")
+	}
+	if got := commonName(t, reloader); got != "one.test" {
+		t.Fatalf("expected the previous certificate to stay in use, got %q", got)
+	}
+
+	writePair(t, dir, "two.test")
+	if reloaded, err := reloader.Reload(); err != nil || !reloaded {
+		t.Fatalf("expected the repaired pair to load, got %v %v", reloaded, err)
+	}
+}
+
+func TestReloaderWatchStopsWithContext(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writePair(t, dir, "one.test")
+	reloader, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("new reloader: %v", err)
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		reloader.Watch(ctx, time.Millisecond, zap.NewNop())
+		close(done)
+	}()
+
+	writePair(t, dir, "two.test")
+	deadline := time.Now().Add(5 * time.Second)
+	for commonName(t, reloader) != "two.test" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected Watch to load the rotated certificate")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,149 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName identifies tracers created by this application.
+const InstrumentationName = "github.com/janisto/huma-playground"
+
+// Supported span exporters.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Config selects the span exporter and describes the service resource.
+type Config struct {
+	Exporter       string
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+}
+
+// Provider is the application's tracer provider. Shutdown flushes buffered spans.
+type Provider struct {
+	trace.TracerProvider
+	shutdown func(context.Context) error
+}
+
+// New creates a tracer provider for cfg.Exporter. ExporterNone returns a no-op provider, so
+// instrumentation stays in place at negligible cost. The OTLP exporter sends over HTTP and reads
+// its endpoint and headers from the standard OTEL_EXPORTER_OTLP_* environment variables.
+func New(ctx context.Context, cfg Config) (*Provider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.Exporter {
+	case ExporterNone:
+		return &Provider{
+			TracerProvider: noop.NewTracerProvider(),
+			shutdown:       func(context.Context) error { return nil },
+		}, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		semconv.DeploymentEnvironmentNameKey.String(cfg.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create trace resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+	)
+	return &Provider{TracerProvider: tp, shutdown: tp.Shutdown}, nil
+}
+
+// Shutdown flushes and stops the exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx)
+}
+
+// Middleware starts a server span for each Huma operation and continues W3C trace context sent by
+// the caller. Spans are named after the method and path template, never the raw path.
+func Middleware(tp trace.TracerProvider) func(huma.Context, func(huma.Context)) {
+	tracer := tp.Tracer(InstrumentationName)
+	propagator := propagation.TraceContext{}
+	return func(ctx huma.Context, next func(huma.Context)) {
+		var operationID, route string
+		if op := ctx.Operation(); op != nil {
+			operationID, route = op.OperationID, op.Path
+		}
+		parent := propagator.Extract(ctx.Context(), headerCarrier{ctx: ctx})
+		spanCtx, span := tracer.Start(parent, ctx.Method()+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Method()),
+				semconv.HTTPRoute(route),
+				attribute.String("huma.operation_id", operationID),
+			),
+		)
+		completed := false
+		defer func() {
+			status := ctx.Status()
+			if !completed {
+				status = http.StatusInternalServerError
+			}
+			if status != 0 {
+				span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			}
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			span.End()
+		}()
+
+		next(huma.WithContext(ctx, spanCtx))
+		completed = true
+	}
+}
+
+// headerCarrier reads propagation headers from a Huma context. Set is a no-op because server
+// spans only extract context.
+type headerCarrier struct {
+	ctx huma.Context
+}
+
+func (c headerCarrier) Get(key string) string {
+	return c.ctx.Header(key)
+}
+
+func (headerCarrier) Set(string, string) {}
+
+func (c headerCarrier) Keys() []string {
+	var keys []string
+	c.ctx.EachHeader(func(name, _ string) {
+		keys = append(keys, name)
+	})
+	return keys
+}
@@ -0,0 +1,155 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type pingOutput struct {
+	Body struct {
+		OK bool `json:"ok"`
+	}
+}
+
+func newTestAPI(exporter *tracetest.InMemoryExporter) http.Handler {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	router := chi.NewRouter()
+	api := humachi.New(router, huma.DefaultConfig("TracingTest", "test"))
+	api.UseMiddleware(Middleware(tp))
+	huma.Register(api, huma.Operation{
+		OperationID: "get-ping",
+		Method:      http.MethodGet,
+		Path:        "/ping/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	},
+	) (*pingOutput, error) {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			return nil, huma.Error500InternalServerError("missing span context")
+		}
+		switch input.ID {
+		case "missing":
+			return nil, huma.Error404NotFound("not found")
+		case "broken":
+			return nil, huma.Error503ServiceUnavailable("unavailable")
+		case "panic":
+			panic("boom")
+		}
+		return &pingOutput{}, nil
+	})
+	return router
+}
+
+func serve(t *testing.T, handler http.Handler, path, traceparent string) {
+	t.Helper()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+	if traceparent != "" {
+		req.Header.Set("traceparent", traceparent)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func attributeValue(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddlewareStartsServerSpanPerOperation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	serve(t, newTestAPI(exporter), "/ping/1", "")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /ping/{id}" || span.SpanKind != trace.SpanKindServer {
+		t.Fatalf("unexpected span %q of kind %v", span.Name, span.SpanKind)
+	}
+	if got := attributeValue(span, "http.route").AsString(); got != "/ping/{id}" {
+		t.Errorf("expected http.route /ping/{id}, got %q", got)
+	}
+	if got := attributeValue(span, "huma.operation_id").AsString(); got != "get-ping" {
+		t.Errorf("expected operation ID get-ping, got %q", got)
+	}
+	if got := attributeValue(span, "http.response.status_code").AsInt64(); got != http.StatusOK {
+		t.Errorf("expected status 200, got %d", got)
+	}
+	if span.Status.Code != codes.Unset {
+		t.Errorf("expected unset status, got %v", span.Status)
+	}
+}
+
+func TestMiddlewareContinuesIncomingTraceContext(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	serve(t, newTestAPI(exporter), "/ping/1", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	span := exporter.GetSpans()[0]
+	if got := span.SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected incoming trace ID, got %s", got)
+	}
+	if got := span.Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("expected incoming parent span ID, got %s", got)
+	}
+}
+
+func TestMiddlewareMarksServerErrors(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	handler := newTestAPI(exporter)
+
+	serve(t, handler, "/ping/missing", "")
+	serve(t, handler, "/ping/broken", "")
+	func() {
+		defer func() { _ = recover() }()
+		serve(t, handler, "/ping/panic", "")
+	}()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	if spans[0].Status.Code != codes.Unset {
+		t.Errorf("expected 404 to leave status unset, got %v", spans[0].Status)
+	}
+	for _, span := range spans[1:] {
+		if span.Status.Code != codes.Error {
+			t.Errorf("expected error status, got %v", span.Status)
+		}
+	}
+	if got := attributeValue(spans[2], "http.response.status_code").AsInt64(); got != http.StatusInternalServerError {
+		t.Errorf("expected panic to be recorded as 500, got %d", got)
+	}
+}
+
+func TestNewSelectsExporter(t *testing.T) {
+	for _, exporter := range []string{ExporterNone, ExporterStdout} {
+		t.Run(exporter, func(t *testing.T) {
+			provider, err := New(t.Context(), Config{Exporter: exporter, ServiceName: "test"})
+			if err != nil {
+				t.Fatalf("new provider: %v", err)
+			}
+			if err := provider.Shutdown(t.Context()); err != nil {
+				t.Fatalf("shutdown: %v", err)
+			}
+		})
+	}
+
+	if _, err := New(t.Context(), Config{Exporter: "jaeger"}); err == nil {
+		t.Fatal("expected unsupported exporter error")
+	}
+}
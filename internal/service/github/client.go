@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -23,6 +24,10 @@ const (
 	acceptHeader    = "application/vnd.github+json"
 	maxResponseSize = 4 << 20
 	maxDrainSize    = 32 << 10
+
+	// activityPollSize is the page size of activity polls, GitHub's maximum, so a burst of events
+	// between polls is not missed.
+	activityPollSize = 100
 )
 
 // Client implements Service using the GitHub REST API.
@@ -133,7 +138,122 @@ type githubTag struct {
 	} `json:"commit"`
 }
 
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubRelease struct {
+	ID          int64                `json:"id"`
+	TagName     string               `json:"tag_name"`
+	Name        string               `json:"name"`
+	Body        string               `json:"body"`
+	HTMLURL     string               `json:"html_url"`
+	Draft       bool                 `json:"draft"`
+	Prerelease  bool                 `json:"prerelease"`
+	CreatedAt   string               `json:"created_at"`
+	PublishedAt string               `json:"published_at"`
+	Author      *githubUser          `json:"author"`
+	Assets      []githubReleaseAsset `json:"assets"`
+}
+
+type githubReleaseAsset struct {
+	Name               string `json:"name"`
+	ContentType        string `json:"content_type"`
+	Size               int64  `json:"size"`
+	DownloadCount      int64  `json:"download_count"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+type githubPullRequest struct {
+	Number    int           `json:"number"`
+	Title     string        `json:"title"`
+	State     string        `json:"state"`
+	Draft     bool          `json:"draft"`
+	HTMLURL   string        `json:"html_url"`
+	User      *githubUser   `json:"user"`
+	Labels    []githubLabel `json:"labels"`
+	Assignees []githubUser  `json:"assignees"`
+	Base      struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	Head struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	ClosedAt  string `json:"closed_at"`
+	MergedAt  string `json:"merged_at"`
+}
+
+type githubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	State       string          `json:"state"`
+	HTMLURL     string          `json:"html_url"`
+	User        *githubUser     `json:"user"`
+	Labels      []githubLabel   `json:"labels"`
+	Assignees   []githubUser    `json:"assignees"`
+	Comments    int             `json:"comments"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+	ClosedAt    string          `json:"closed_at"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+type githubCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  *struct {
+			Name string `json:"name"`
+			Date string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *githubUser `json:"author"`
+}
+
+type githubComparison struct {
+	Status       string         `json:"status"`
+	AheadBy      int            `json:"ahead_by"`
+	BehindBy     int            `json:"behind_by"`
+	TotalCommits int            `json:"total_commits"`
+	HTMLURL      string         `json:"html_url"`
+	Commits      []githubCommit `json:"commits"`
+	Files        []struct {
+		Filename         string `json:"filename"`
+		PreviousFilename string `json:"previous_filename"`
+		Status           string `json:"status"`
+		Additions        int    `json:"additions"`
+		Deletions        int    `json:"deletions"`
+		Changes          int    `json:"changes"`
+	} `json:"files"`
+}
+
+type githubContent struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	SHA      string `json:"sha"`
+	Size     int64  `json:"size"`
+	HTMLURL  string `json:"html_url"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
 func (c *Client) doRequest(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	return c.doConditionalRequest(ctx, path, query, "")
+}
+
+// doConditionalRequest sends If-None-Match when etag is set, so an unchanged resource returns 304,
+// which does not count against the primary rate limit.
+func (c *Client) doConditionalRequest(
+	ctx context.Context, path string, query url.Values, etag string,
+) (*http.Response, error) {
 	reference, err := url.Parse(path)
 	if err != nil {
 		return nil, fmt.Errorf("parse request path: %w", err)
@@ -152,6 +272,9 @@ func (c *Client) doRequest(ctx context.Context, path string, query url.Values) (
 	if c.token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	return c.httpClient.Do(req)
 }
@@ -201,6 +324,19 @@ func closeResponse(resp *http.Response) {
 	_ = resp.Body.Close()
 }
 
+// Check reports whether the GitHub API is reachable. The rate limit endpoint does not count against
+// the caller's quota.
+func (c *Client) Check(ctx context.Context) error {
+	resp, err := c.doRequest(ctx, "/rate_limit", nil)
+	if err != nil {
+		return fmt.Errorf("checking github: %w", err)
+	}
+	defer closeResponse(resp)
+
+	var discard json.RawMessage
+	return c.decodeResponse(ctx, resp, &discard)
+}
+
 func (c *Client) GetOwner(ctx context.Context, owner string) (*Owner, error) {
 	resp, err := c.doRequest(ctx, "/users/"+url.PathEscape(owner), nil)
 	if err != nil {
@@ -317,7 +453,47 @@ func (c *Client) ListActivity(
 	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
 		return nil, err
 	}
+	activities, err := toActivities(gh)
+	if err != nil {
+		return nil, err
+	}
 
+	nextCursor := parseLinkHeader(linkHeader)
+
+	return &ActivityPage{
+		Activities: activities,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// PollActivity fetches the newest page of activity unless it still matches etag.
+func (c *Client) PollActivity(ctx context.Context, owner, repo, etag string) (*ActivityPoll, error) {
+	q := url.Values{"per_page": {strconv.Itoa(activityPollSize)}}
+	resp, err := c.doConditionalRequest(
+		ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/activity", q, etag,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("polling activity: %w", err)
+	}
+	defer closeResponse(resp)
+
+	poll := &ActivityPoll{ETag: resp.Header.Get("ETag"), Interval: pollInterval(resp.Header)}
+	if resp.StatusCode == http.StatusNotModified {
+		poll.ETag = etag
+		poll.NotModified = true
+		return poll, nil
+	}
+	var gh []githubActivity
+	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
+		return nil, err
+	}
+	if poll.Activities, err = toActivities(gh); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+func toActivities(gh []githubActivity) ([]Activity, error) {
 	activities := make([]Activity, len(gh))
 	for i, a := range gh {
 		ts, err := parseTime(a.Timestamp)
@@ -339,13 +515,16 @@ func (c *Client) ListActivity(
 			ActorAvatarURL: avatarURL,
 		}
 	}
+	return activities, nil
+}
 
-	nextCursor := parseLinkHeader(linkHeader)
-
-	return &ActivityPage{
-		Activities: activities,
-		NextCursor: nextCursor,
-	}, nil
+// pollInterval parses GitHub's X-Poll-Interval header, in seconds.
+func pollInterval(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header.Get("X-Poll-Interval")))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
 }
 
 func (c *Client) ListLanguages(ctx context.Context, owner, repo string) (map[string]int64, error) {
@@ -387,15 +566,335 @@ func (c *Client) ListTags(ctx context.Context, owner, repo string) ([]Tag, error
 	return tags, nil
 }
 
+func (c *Client) ListReleases(
+	ctx context.Context, owner, repo string, limit int, pageCursor string,
+) (*ReleasePage, error) {
+	q := url.Values{"per_page": {strconv.Itoa(limit)}}
+	if pageCursor != "" {
+		q.Set("page", pageCursor)
+	}
+
+	resp, err := c.doRequest(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases", q)
+	if err != nil {
+		return nil, fmt.Errorf("fetching releases: %w", err)
+	}
+	defer closeResponse(resp)
+
+	linkHeader := resp.Header.Get("Link")
+
+	var gh []githubRelease
+	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
+		return nil, err
+	}
+
+	releases := make([]Release, len(gh))
+	for i, r := range gh {
+		release, err := toRelease(r)
+		if err != nil {
+			return nil, fmt.Errorf("decoding release %d: %w", i, err)
+		}
+		releases[i] = release
+	}
+
+	return &ReleasePage{
+		Releases:   releases,
+		NextCursor: parseLinkHeaderParam(linkHeader, "page"),
+		LastCursor: parseLinkRelParam(linkHeader, "last", "page"),
+	}, nil
+}
+
+func (c *Client) GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	return c.getRelease(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases/latest")
+}
+
+func (c *Client) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error) {
+	return c.getRelease(
+		ctx,
+		"/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases/tags/"+url.PathEscape(tag),
+	)
+}
+
+func (c *Client) getRelease(ctx context.Context, path string) (*Release, error) {
+	resp, err := c.doRequest(ctx, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching release: %w", err)
+	}
+	defer closeResponse(resp)
+
+	var gh githubRelease
+	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
+		return nil, err
+	}
+
+	release, err := toRelease(gh)
+	if err != nil {
+		return nil, fmt.Errorf("decoding release: %w", err)
+	}
+	return &release, nil
+}
+
+func (c *Client) ListPullRequests(
+	ctx context.Context, owner, repo string, params PullRequestListParams,
+) (*PullRequestPage, error) {
+	q := url.Values{"per_page": {strconv.Itoa(params.Limit)}}
+	setIfNotEmpty(q, "state", params.State)
+	setIfNotEmpty(q, "base", params.Base)
+	setIfNotEmpty(q, "sort", params.Sort)
+	setIfNotEmpty(q, "direction", params.Direction)
+	setIfNotEmpty(q, "page", params.PageCursor)
+
+	resp, err := c.doRequest(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/pulls", q)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pull requests: %w", err)
+	}
+	defer closeResponse(resp)
+
+	linkHeader := resp.Header.Get("Link")
+
+	var gh []githubPullRequest
+	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
+		return nil, err
+	}
+
+	pulls := make([]PullRequest, len(gh))
+	for i, p := range gh {
+		pull, err := toPullRequest(p)
+		if err != nil {
+			return nil, fmt.Errorf("decoding pull request %d: %w", i, err)
+		}
+		pulls[i] = pull
+	}
+
+	return &PullRequestPage{
+		PullRequests: pulls,
+		NextCursor:   parseLinkHeaderParam(linkHeader, "page"),
+		LastCursor:   parseLinkRelParam(linkHeader, "last", "page"),
+	}, nil
+}
+
+// ListIssues lists repository issues. GitHub's issues endpoint also returns pull
+// requests; they are dropped, so a page can hold fewer than params.Limit issues.
+func (c *Client) ListIssues(ctx context.Context, owner, repo string, params IssueListParams) (*IssuePage, error) {
+	q := url.Values{"per_page": {strconv.Itoa(params.Limit)}}
+	setIfNotEmpty(q, "state", params.State)
+	setIfNotEmpty(q, "labels", strings.Join(params.Labels, ","))
+	setIfNotEmpty(q, "assignee", params.Assignee)
+	setIfNotEmpty(q, "sort", params.Sort)
+	setIfNotEmpty(q, "direction", params.Direction)
+	setIfNotEmpty(q, "page", params.PageCursor)
+
+	resp, err := c.doRequest(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/issues", q)
+	if err != nil {
+		return nil, fmt.Errorf("fetching issues: %w", err)
+	}
+	defer closeResponse(resp)
+
+	linkHeader := resp.Header.Get("Link")
+
+	var gh []githubIssue
+	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(gh))
+	for i, is := range gh {
+		if len(is.PullRequest) > 0 && string(is.PullRequest) != "null" {
+			continue
+		}
+		issue, err := toIssue(is)
+		if err != nil {
+			return nil, fmt.Errorf("decoding issue %d: %w", i, err)
+		}
+		issues = append(issues, issue)
+	}
+
+	return &IssuePage{
+		Issues:     issues,
+		NextCursor: parseLinkHeaderParam(linkHeader, "page"),
+		LastCursor: parseLinkRelParam(linkHeader, "last", "page"),
+	}, nil
+}
+
+func (c *Client) ListCommits(
+	ctx context.Context, owner, repo string, params CommitListParams,
+) (*CommitPage, error) {
+	q := url.Values{"per_page": {strconv.Itoa(params.Limit)}}
+	setIfNotEmpty(q, "sha", params.Branch)
+	setIfNotEmpty(q, "path", params.Path)
+	setIfNotEmpty(q, "author", params.Author)
+	if !params.Since.IsZero() {
+		q.Set("since", params.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !params.Until.IsZero() {
+		q.Set("until", params.Until.UTC().Format(time.RFC3339Nano))
+	}
+	setIfNotEmpty(q, "page", params.PageCursor)
+
+	resp, err := c.doRequest(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/commits", q)
+	if err != nil {
+		return nil, fmt.Errorf("fetching commits: %w", err)
+	}
+	defer closeResponse(resp)
+
+	linkHeader := resp.Header.Get("Link")
+
+	var gh []githubCommit
+	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
+		return nil, err
+	}
+
+	commits, err := toCommitSummaries(gh)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitPage{
+		Commits:    commits,
+		NextCursor: parseLinkHeaderParam(linkHeader, "page"),
+		LastCursor: parseLinkRelParam(linkHeader, "last", "page"),
+	}, nil
+}
+
+// CompareCommits compares two refs. The response stays subject to maxResponseSize,
+// so comparisons with very large file lists fail rather than being buffered.
+func (c *Client) CompareCommits(ctx context.Context, owner, repo, base, head string) (*Comparison, error) {
+	path := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) +
+		"/compare/" + url.PathEscape(base) + "..." + url.PathEscape(head)
+	resp, err := c.doRequest(ctx, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching comparison: %w", err)
+	}
+	defer closeResponse(resp)
+
+	var gh githubComparison
+	if err := c.decodeResponse(ctx, resp, &gh); err != nil {
+		return nil, err
+	}
+
+	commits, err := toCommitSummaries(gh.Commits)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]FileChange, len(gh.Files))
+	for i, f := range gh.Files {
+		files[i] = FileChange{
+			Filename:         f.Filename,
+			PreviousFilename: f.PreviousFilename,
+			Status:           f.Status,
+			Additions:        f.Additions,
+			Deletions:        f.Deletions,
+			Changes:          f.Changes,
+		}
+	}
+
+	return &Comparison{
+		Status:       gh.Status,
+		AheadBy:      gh.AheadBy,
+		BehindBy:     gh.BehindBy,
+		TotalCommits: gh.TotalCommits,
+		HTMLURL:      gh.HTMLURL,
+		Commits:      commits,
+		Files:        files,
+	}, nil
+}
+
+// GetContents returns the file or directory at path; an empty path lists the repository root.
+func (c *Client) GetContents(ctx context.Context, owner, repo, path, ref string) (*Content, error) {
+	endpoint := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/contents"
+	if path != "" {
+		for segment := range strings.SplitSeq(path, "/") {
+			endpoint += "/" + url.PathEscape(segment)
+		}
+	}
+	return c.getContent(ctx, endpoint, ref)
+}
+
+// GetReadme returns the repository's preferred README file.
+func (c *Client) GetReadme(ctx context.Context, owner, repo, ref string) (*Content, error) {
+	return c.getContent(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/readme", ref)
+}
+
+func (c *Client) getContent(ctx context.Context, path, ref string) (*Content, error) {
+	q := url.Values{}
+	setIfNotEmpty(q, "ref", ref)
+	resp, err := c.doRequest(ctx, path, q)
+	if err != nil {
+		return nil, fmt.Errorf("fetching content: %w", err)
+	}
+	defer closeResponse(resp)
+
+	var raw json.RawMessage
+	if err := c.decodeResponse(ctx, resp, &raw); err != nil {
+		return nil, err
+	}
+
+	// Directories are returned as arrays, everything else as a single object.
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		var gh []githubContent
+		if err := json.Unmarshal(raw, &gh); err != nil {
+			return nil, fmt.Errorf("decoding directory: %w", err)
+		}
+		entries := make([]ContentEntry, len(gh))
+		for i, e := range gh {
+			entries[i] = ContentEntry{Type: e.Type, Name: e.Name, Path: e.Path, SHA: e.SHA, Size: e.Size}
+		}
+		return &Content{Type: ContentTypeDir, Entries: entries}, nil
+	}
+
+	var gh githubContent
+	if err := json.Unmarshal(raw, &gh); err != nil {
+		return nil, fmt.Errorf("decoding content: %w", err)
+	}
+	if gh.Type != ContentTypeFile {
+		return nil, ErrUnsupportedContent
+	}
+	// GitHub omits the content of files over 1 MB and reports encoding "none".
+	if gh.Size > MaxContentSize || gh.Encoding != "base64" {
+		return nil, ErrContentTooLarge
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(gh.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("decoding content: %w", err)
+	}
+	if len(data) > MaxContentSize {
+		return nil, ErrContentTooLarge
+	}
+
+	return &Content{
+		Type:    ContentTypeFile,
+		Name:    gh.Name,
+		Path:    gh.Path,
+		SHA:     gh.SHA,
+		Size:    gh.Size,
+		HTMLURL: gh.HTMLURL,
+		Data:    data,
+	}, nil
+}
+
+func setIfNotEmpty(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
+
 // parseLinkHeader extracts the "after" cursor from a GitHub Link header.
 func parseLinkHeader(header string) string {
+	return parseLinkHeaderParam(header, "after")
+}
+
+// parseLinkHeaderParam extracts a query parameter from the rel="next" URL of a GitHub Link header.
+func parseLinkHeaderParam(header, param string) string {
+	return parseLinkRelParam(header, "next", param)
+}
+
+// parseLinkRelParam extracts a query parameter from the URL with relation rel in a GitHub Link header.
+func parseLinkRelParam(header, rel, param string) string {
 	if header == "" {
 		return ""
 	}
 
 	for raw := range strings.SplitSeq(header, ",") {
 		part := strings.TrimSpace(raw)
-		if !strings.Contains(part, `rel="next"`) {
+		if !strings.Contains(part, `rel="`+rel+`"`) {
 			continue
 		}
 
@@ -410,8 +909,8 @@ func parseLinkHeader(header string) string {
 			continue
 		}
 
-		if after := linkURL.Query().Get("after"); after != "" {
-			return after
+		if value := linkURL.Query().Get(param); value != "" {
+			return value
 		}
 	}
 	return ""
@@ -440,6 +939,151 @@ func toRepoSummary(r githubRepoSummary) (RepoSummary, error) {
 	}, nil
 }
 
+func toRelease(r githubRelease) (Release, error) {
+	createdAt, err := parseTime(r.CreatedAt)
+	if err != nil {
+		return Release{}, err
+	}
+	// Draft releases have no publication time.
+	publishedAt, err := parseOptionalTime(r.PublishedAt)
+	if err != nil {
+		return Release{}, err
+	}
+
+	assets := make([]ReleaseAsset, len(r.Assets))
+	for i, a := range r.Assets {
+		assets[i] = ReleaseAsset{
+			Name:          a.Name,
+			ContentType:   a.ContentType,
+			Size:          a.Size,
+			DownloadCount: a.DownloadCount,
+			DownloadURL:   a.BrowserDownloadURL,
+		}
+	}
+
+	return Release{
+		ID:          r.ID,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		HTMLURL:     r.HTMLURL,
+		Author:      userLogin(r.Author),
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		CreatedAt:   createdAt,
+		PublishedAt: publishedAt,
+		Assets:      assets,
+	}, nil
+}
+
+func toPullRequest(p githubPullRequest) (PullRequest, error) {
+	createdAt, err := parseTime(p.CreatedAt)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	updatedAt, err := parseTime(p.UpdatedAt)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	closedAt, err := parseOptionalTime(p.ClosedAt)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	mergedAt, err := parseOptionalTime(p.MergedAt)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	return PullRequest{
+		Number:    p.Number,
+		Title:     p.Title,
+		State:     p.State,
+		Draft:     p.Draft,
+		Author:    userLogin(p.User),
+		HTMLURL:   p.HTMLURL,
+		BaseRef:   p.Base.Ref,
+		HeadRef:   p.Head.Ref,
+		Labels:    labelNames(p.Labels),
+		Assignees: userLogins(p.Assignees),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		ClosedAt:  closedAt,
+		MergedAt:  mergedAt,
+	}, nil
+}
+
+func toIssue(i githubIssue) (Issue, error) {
+	createdAt, err := parseTime(i.CreatedAt)
+	if err != nil {
+		return Issue{}, err
+	}
+	updatedAt, err := parseTime(i.UpdatedAt)
+	if err != nil {
+		return Issue{}, err
+	}
+	closedAt, err := parseOptionalTime(i.ClosedAt)
+	if err != nil {
+		return Issue{}, err
+	}
+	return Issue{
+		Number:    i.Number,
+		Title:     i.Title,
+		State:     i.State,
+		Author:    userLogin(i.User),
+		HTMLURL:   i.HTMLURL,
+		Labels:    labelNames(i.Labels),
+		Assignees: userLogins(i.Assignees),
+		Comments:  i.Comments,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		ClosedAt:  closedAt,
+	}, nil
+}
+
+func toCommitSummaries(gh []githubCommit) ([]CommitSummary, error) {
+	commits := make([]CommitSummary, len(gh))
+	for i, c := range gh {
+		if c.Commit.Author == nil {
+			return nil, fmt.Errorf("decoding commit %d: missing author", i)
+		}
+		authoredAt, err := parseTime(c.Commit.Author.Date)
+		if err != nil {
+			return nil, fmt.Errorf("decoding commit %d: %w", i, err)
+		}
+		commits[i] = CommitSummary{
+			SHA:         c.SHA,
+			Message:     c.Commit.Message,
+			AuthorName:  c.Commit.Author.Name,
+			AuthorLogin: userLogin(c.Author),
+			AuthoredAt:  authoredAt,
+			HTMLURL:     c.HTMLURL,
+		}
+	}
+	return commits, nil
+}
+
+func userLogin(u *githubUser) string {
+	if u == nil {
+		return ""
+	}
+	return u.Login
+}
+
+func userLogins(users []githubUser) []string {
+	logins := make([]string, len(users))
+	for i, u := range users {
+		logins[i] = u.Login
+	}
+	return logins
+}
+
+func labelNames(labels []githubLabel) []string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+	return names
+}
+
 func parseTime(s string) (time.Time, error) {
 	if s == "" {
 		return time.Time{}, errors.New("missing required timestamp")
@@ -451,6 +1095,14 @@ func parseTime(s string) (time.Time, error) {
 	return t, nil
 }
 
+// parseOptionalTime parses s as RFC 3339, returning the zero time when s is empty.
+func parseOptionalTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return parseTime(s)
+}
+
 func upstreamErrorFromResponse(resp *http.Response, kind UpstreamErrorKind, cause error) *UpstreamError {
 	return &UpstreamError{
 		Kind:           kind,
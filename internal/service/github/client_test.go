@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func newTestServer(handler http.HandlerFunc) *httptest.Server {
@@ -228,6 +229,57 @@ func TestListActivity(t *testing.T) {
 	}
 }
 
+func TestPollActivity(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("expected per_page=100, got %s", r.URL.Query().Get("per_page"))
+		}
+		w.Header().Set("X-Poll-Interval", "60")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"id": 7, "ref": "refs/heads/main", "timestamp": "2024-01-15T10:30:00Z", "activity_type": "push"},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	poll, err := client.PollActivity(t.Context(), "octocat", "hello-world", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if poll.NotModified || poll.ETag != `"v1"` || len(poll.Activities) != 1 || poll.Activities[0].ID != 7 {
+		t.Fatalf("expected one activity with ETag \"v1\", got %+v", poll)
+	}
+	if poll.Interval != time.Minute {
+		t.Errorf("expected a 1m poll interval, got %s", poll.Interval)
+	}
+
+	poll, err = client.PollActivity(t.Context(), "octocat", "hello-world", `"v1"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !poll.NotModified || poll.ETag != `"v1"` || poll.Activities != nil {
+		t.Fatalf("expected an unmodified poll keeping its ETag, got %+v", poll)
+	}
+}
+
+func TestPollActivityNotFound(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	if _, err := client.PollActivity(t.Context(), "octocat", "missing", ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestListActivityWithCursor(t *testing.T) {
 	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Query().Get("after") != "cursor-xyz" {
@@ -335,6 +387,621 @@ func TestListTags(t *testing.T) {
 	}
 }
 
+func TestListReleases(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello-world/releases" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("per_page") != "10" {
+			t.Errorf("expected per_page=10, got %s", r.URL.Query().Get("per_page"))
+		}
+		if r.URL.Query().Get("page") != "2" {
+			t.Errorf("expected page=2, got %s", r.URL.Query().Get("page"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", `<https://api.github.com/repositories/1/releases?per_page=10&page=3>; rel="next"`)
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"id":           1,
+				"tag_name":     "v1.0.0",
+				"name":         "First release",
+				"body":         "Initial release",
+				"html_url":     "https://github.com/octocat/hello-world/releases/tag/v1.0.0",
+				"draft":        false,
+				"prerelease":   false,
+				"created_at":   "2024-01-15T10:30:00Z",
+				"published_at": "2024-01-15T11:00:00Z",
+				"author":       map[string]any{"login": "octocat"},
+				"assets": []map[string]any{
+					{
+						"name":                 "hello.tar.gz",
+						"content_type":         "application/gzip",
+						"size":                 1024,
+						"download_count":       42,
+						"browser_download_url": "https://github.com/octocat/hello-world/releases/download/v1.0.0/hello.tar.gz",
+					},
+				},
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	page, err := client.ListReleases(t.Context(), "octocat", "hello-world", 10, "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Releases) != 1 {
+		t.Fatalf("expected 1 release, got %d", len(page.Releases))
+	}
+	release := page.Releases[0]
+	if release.TagName != "v1.0.0" || release.Author != "octocat" {
+		t.Errorf("unexpected release: %+v", release)
+	}
+	if release.PublishedAt.IsZero() {
+		t.Error("expected PublishedAt to be set")
+	}
+	if len(release.Assets) != 1 {
+		t.Fatalf("expected 1 asset, got %d", len(release.Assets))
+	}
+	asset := release.Assets[0]
+	if asset.Name != "hello.tar.gz" || asset.ContentType != "application/gzip" ||
+		asset.Size != 1024 || asset.DownloadCount != 42 {
+		t.Errorf("unexpected asset: %+v", asset)
+	}
+	if page.NextCursor != "3" {
+		t.Errorf("expected next cursor 3, got %s", page.NextCursor)
+	}
+}
+
+func TestListReleasesDraftWithoutPublishedAt(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("page") {
+			t.Errorf("expected no page param, got %s", r.URL.Query().Get("page"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"id":           2,
+				"tag_name":     "v2.0.0",
+				"draft":        true,
+				"created_at":   "2024-02-01T00:00:00Z",
+				"published_at": nil,
+				"author":       nil,
+				"assets":       []map[string]any{},
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	page, err := client.ListReleases(t.Context(), "octocat", "hello-world", 10, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release := page.Releases[0]
+	if !release.Draft || !release.PublishedAt.IsZero() || release.Author != "" {
+		t.Errorf("unexpected draft release: %+v", release)
+	}
+	if page.NextCursor != "" {
+		t.Errorf("expected empty next cursor, got %s", page.NextCursor)
+	}
+}
+
+func TestGetLatestRelease(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello-world/releases/latest" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":           3,
+			"tag_name":     "v3.0.0",
+			"created_at":   "2024-03-01T00:00:00Z",
+			"published_at": "2024-03-01T00:00:00Z",
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	release, err := client.GetLatestRelease(t.Context(), "octocat", "hello-world")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if release.TagName != "v3.0.0" {
+		t.Errorf("expected tag v3.0.0, got %s", release.TagName)
+	}
+	if release.Assets == nil {
+		t.Error("expected non-nil assets")
+	}
+}
+
+func TestGetReleaseByTag(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repos/octocat/hello-world/releases/tags/v1.0.0+build" {
+			t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":           1,
+			"tag_name":     "v1.0.0+build",
+			"created_at":   "2024-01-15T10:30:00Z",
+			"published_at": "2024-01-15T11:00:00Z",
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	release, err := client.GetReleaseByTag(t.Context(), "octocat", "hello-world", "v1.0.0+build")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if release.TagName != "v1.0.0+build" {
+		t.Errorf("expected tag v1.0.0+build, got %s", release.TagName)
+	}
+}
+
+func TestGetReleaseNotFound(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	_, err := client.GetLatestRelease(t.Context(), "octocat", "hello-world")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestListPullRequests(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello-world/pulls" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		want := "base=main&direction=asc&page=2&per_page=10&sort=updated&state=closed"
+		if r.URL.RawQuery != want {
+			t.Errorf("expected query %s, got %s", want, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", `<https://api.github.com/repositories/1/pulls?page=3>; rel="next"`)
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"number":     1347,
+				"title":      "Amazing new feature",
+				"state":      "closed",
+				"draft":      false,
+				"html_url":   "https://github.com/octocat/hello-world/pull/1347",
+				"user":       map[string]any{"login": "octocat"},
+				"labels":     []map[string]any{{"name": "enhancement"}},
+				"assignees":  []map[string]any{{"login": "hubot"}},
+				"base":       map[string]any{"ref": "main"},
+				"head":       map[string]any{"ref": "new-topic"},
+				"created_at": "2024-01-15T10:30:00Z",
+				"updated_at": "2024-01-16T10:30:00Z",
+				"closed_at":  "2024-01-16T10:30:00Z",
+				"merged_at":  nil,
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	page, err := client.ListPullRequests(t.Context(), "octocat", "hello-world", PullRequestListParams{
+		State:      "closed",
+		Base:       "main",
+		Sort:       "updated",
+		Direction:  "asc",
+		Limit:      10,
+		PageCursor: "2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.PullRequests) != 1 {
+		t.Fatalf("expected 1 pull request, got %d", len(page.PullRequests))
+	}
+	pull := page.PullRequests[0]
+	if pull.Number != 1347 || pull.Author != "octocat" || pull.BaseRef != "main" || pull.HeadRef != "new-topic" {
+		t.Errorf("unexpected pull request: %+v", pull)
+	}
+	if len(pull.Labels) != 1 || pull.Labels[0] != "enhancement" {
+		t.Errorf("unexpected labels: %v", pull.Labels)
+	}
+	if len(pull.Assignees) != 1 || pull.Assignees[0] != "hubot" {
+		t.Errorf("unexpected assignees: %v", pull.Assignees)
+	}
+	if pull.ClosedAt.IsZero() || !pull.MergedAt.IsZero() {
+		t.Errorf("unexpected closed/merged times: %v %v", pull.ClosedAt, pull.MergedAt)
+	}
+	if page.NextCursor != "3" {
+		t.Errorf("expected next cursor 3, got %s", page.NextCursor)
+	}
+}
+
+func TestListPullRequestsOmitsEmptyFilters(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "per_page=20" {
+			t.Errorf("expected only per_page, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	page, err := client.ListPullRequests(t.Context(), "octocat", "hello-world", PullRequestListParams{Limit: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.PullRequests) != 0 || page.NextCursor != "" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello-world/issues" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("labels") != "bug,ui" || query.Get("assignee") != "octocat" ||
+			query.Get("state") != "all" || query.Get("sort") != "comments" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"number":     1,
+				"title":      "Found a bug",
+				"state":      "open",
+				"html_url":   "https://github.com/octocat/hello-world/issues/1",
+				"user":       map[string]any{"login": "octocat"},
+				"labels":     []map[string]any{{"name": "bug"}},
+				"assignees":  []map[string]any{},
+				"comments":   3,
+				"created_at": "2024-01-15T10:30:00Z",
+				"updated_at": "2024-01-16T10:30:00Z",
+				"closed_at":  nil,
+			},
+			{
+				"number":       2,
+				"title":        "A pull request",
+				"state":        "open",
+				"created_at":   "2024-01-15T10:30:00Z",
+				"updated_at":   "2024-01-16T10:30:00Z",
+				"pull_request": map[string]any{"url": "https://api.github.com/repos/octocat/hello-world/pulls/2"},
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	page, err := client.ListIssues(t.Context(), "octocat", "hello-world", IssueListParams{
+		State:    "all",
+		Labels:   []string{"bug", "ui"},
+		Assignee: "octocat",
+		Sort:     "comments",
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Issues) != 1 {
+		t.Fatalf("expected pull requests to be filtered out, got %d issues", len(page.Issues))
+	}
+	issue := page.Issues[0]
+	if issue.Number != 1 || issue.Author != "octocat" || issue.Comments != 3 || !issue.ClosedAt.IsZero() {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if len(issue.Assignees) != 0 || issue.Assignees == nil {
+		t.Errorf("expected empty non-nil assignees, got %v", issue.Assignees)
+	}
+}
+
+func TestListIssuesRejectsInvalidTimestamps(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"number":     1,
+				"created_at": "2024-01-15T10:30:00Z",
+				"updated_at": "2024-01-16T10:30:00Z",
+				"closed_at":  "bad",
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	if _, err := client.ListIssues(t.Context(), "octocat", "hello-world", IssueListParams{Limit: 10}); err == nil {
+		t.Fatal("expected error for invalid closed_at")
+	}
+}
+
+func TestListCommits(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello-world/commits" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		want := "author=octocat&page=2&path=docs%2FREADME.md&per_page=10&sha=main" +
+			"&since=2024-01-01T00%3A00%3A00.123Z&until=2024-02-01T00%3A00%3A00Z"
+		if r.URL.RawQuery != want {
+			t.Errorf("expected query %s, got %s", want, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", `<https://api.github.com/repositories/1/commits?page=3>; rel="next"`)
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"sha":      "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+				"html_url": "https://github.com/octocat/hello-world/commit/6dcb09b",
+				"commit": map[string]any{
+					"message": "Fix all the bugs",
+					"author":  map[string]any{"name": "Monalisa Octocat", "date": "2024-01-15T10:30:00Z"},
+				},
+				"author": map[string]any{"login": "octocat"},
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	page, err := client.ListCommits(t.Context(), "octocat", "hello-world", CommitListParams{
+		Branch:     "main",
+		Path:       "docs/README.md",
+		Author:     "octocat",
+		Since:      time.Date(2024, 1, 1, 0, 0, 0, 123_000_000, time.UTC),
+		Until:      time.Date(2024, 2, 1, 2, 0, 0, 0, time.FixedZone("EET", 2*60*60)),
+		Limit:      10,
+		PageCursor: "2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Commits) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(page.Commits))
+	}
+	commit := page.Commits[0]
+	if commit.Message != "Fix all the bugs" || commit.AuthorName != "Monalisa Octocat" ||
+		commit.AuthorLogin != "octocat" || commit.AuthoredAt.IsZero() {
+		t.Errorf("unexpected commit: %+v", commit)
+	}
+	if page.NextCursor != "3" {
+		t.Errorf("expected next cursor 3, got %s", page.NextCursor)
+	}
+}
+
+func TestListCommitsWithoutLinkedAccount(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{
+				"sha": "abc",
+				"commit": map[string]any{
+					"message": "Anonymous",
+					"author":  map[string]any{"name": "Someone", "date": "2024-01-15T10:30:00Z"},
+				},
+				"author": nil,
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	page, err := client.ListCommits(t.Context(), "octocat", "hello-world", CommitListParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Commits[0].AuthorLogin != "" || page.Commits[0].AuthorName != "Someone" {
+		t.Errorf("unexpected commit: %+v", page.Commits[0])
+	}
+}
+
+func TestCompareCommits(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repos/octocat/hello-world/compare/main...feature%2Fx" {
+			t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status":        "diverged",
+			"ahead_by":      2,
+			"behind_by":     1,
+			"total_commits": 2,
+			"html_url":      "https://github.com/octocat/hello-world/compare/main...feature/x",
+			"commits": []map[string]any{
+				{
+					"sha": "abc",
+					"commit": map[string]any{
+						"message": "Add feature",
+						"author":  map[string]any{"name": "Octocat", "date": "2024-01-15T10:30:00Z"},
+					},
+				},
+			},
+			"files": []map[string]any{
+				{
+					"filename":          "new.go",
+					"previous_filename": "old.go",
+					"status":            "renamed",
+					"additions":         3,
+					"deletions":         1,
+					"changes":           4,
+					"patch":             "@@ -1 +1 @@",
+				},
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	comparison, err := client.CompareCommits(t.Context(), "octocat", "hello-world", "main", "feature/x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comparison.Status != "diverged" || comparison.AheadBy != 2 || comparison.BehindBy != 1 ||
+		comparison.TotalCommits != 2 {
+		t.Errorf("unexpected comparison: %+v", comparison)
+	}
+	if len(comparison.Commits) != 1 || comparison.Commits[0].Message != "Add feature" {
+		t.Errorf("unexpected commits: %+v", comparison.Commits)
+	}
+	want := FileChange{
+		Filename:         "new.go",
+		PreviousFilename: "old.go",
+		Status:           "renamed",
+		Additions:        3,
+		Deletions:        1,
+		Changes:          4,
+	}
+	if len(comparison.Files) != 1 || comparison.Files[0] != want {
+		t.Errorf("unexpected files: %+v", comparison.Files)
+	}
+}
+
+func TestCompareCommitsOversizedResponse(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"files":[{"patch":"` + strings.Repeat("a", maxResponseSize) + `"}]}`))
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	if _, err := client.CompareCommits(t.Context(), "octocat", "hello-world", "main", "dev"); err == nil {
+		t.Fatal("expected size limit error")
+	}
+}
+
+func TestGetContentsFile(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/repos/octocat/hello-world/contents/docs/my%20notes.md" {
+			t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		if r.URL.Query().Get("ref") != "main" {
+			t.Errorf("expected ref=main, got %s", r.URL.Query().Get("ref"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type":     "file",
+			"name":     "my notes.md",
+			"path":     "docs/my notes.md",
+			"sha":      "3d21ec53a331a6f037a91c368710b99387d012c1",
+			"size":     12,
+			"encoding": "base64",
+			"content":  "IyBIZWxs\nbyB3b3Js\nZAo=\n",
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	content, err := client.GetContents(t.Context(), "octocat", "hello-world", "docs/my notes.md", "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content.Type != ContentTypeFile || string(content.Data) != "# Hello world\n" {
+		t.Errorf("unexpected content: %+v %q", content, content.Data)
+	}
+}
+
+func TestGetContentsDirectory(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello-world/contents" || r.URL.RawQuery != "" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"type": "dir", "name": "docs", "path": "docs", "sha": "a", "size": 0},
+			{"type": "file", "name": "README.md", "path": "README.md", "sha": "b", "size": 12},
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	content, err := client.GetContents(t.Context(), "octocat", "hello-world", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content.Type != ContentTypeDir || len(content.Entries) != 2 || content.Entries[1].Size != 12 {
+		t.Errorf("unexpected listing: %+v", content)
+	}
+}
+
+func TestGetContentsLimits(t *testing.T) {
+	tests := map[string]struct {
+		body map[string]any
+		want error
+	}{
+		"reported size over limit": {
+			body: map[string]any{"type": "file", "size": MaxContentSize + 1, "encoding": "base64"},
+			want: ErrContentTooLarge,
+		},
+		"content omitted upstream": {
+			body: map[string]any{"type": "file", "size": 10, "encoding": "none"},
+			want: ErrContentTooLarge,
+		},
+		"symlink": {
+			body: map[string]any{"type": "symlink", "target": "../README.md"},
+			want: ErrUnsupportedContent,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(test.body)
+			})
+			defer srv.Close()
+
+			client := newTestClient(srv.URL)
+			_, err := client.GetContents(t.Context(), "octocat", "hello-world", "file", "")
+			if !errors.Is(err, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestGetReadme(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello-world/readme" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type":     "file",
+			"name":     "README.md",
+			"path":     "README.md",
+			"size":     5,
+			"encoding": "base64",
+			"content":  "SGVsbG8=",
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	content, err := client.GetReadme(t.Context(), "octocat", "hello-world", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content.Name != "README.md" || string(content.Data) != "Hello" {
+		t.Errorf("unexpected readme: %+v", content)
+	}
+}
+
+func TestGetReadmeInvalidBase64(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type": "file", "size": 5, "encoding": "base64", "content": "not base64!",
+		})
+	})
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	if _, err := client.GetReadme(t.Context(), "octocat", "hello-world", ""); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
+
 func TestNotFoundError(t *testing.T) {
 	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -615,6 +1282,33 @@ func TestContextCancellation(t *testing.T) {
 	}
 }
 
+func TestCheck(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"resources":{"core":{"limit":60,"remaining":60}}}`))
+	})
+	defer srv.Close()
+
+	if err := newTestClient(srv.URL).Check(t.Context()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckUpstreamFailure(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer srv.Close()
+
+	if err := newTestClient(srv.URL).Check(t.Context()); !errors.Is(err, ErrUpstream) {
+		t.Fatalf("expected ErrUpstream, got %v", err)
+	}
+}
+
 func TestListLanguagesContextCancellation(t *testing.T) {
 	client := newTestClient("http://127.0.0.1")
 	ctx, cancel := context.WithCancel(t.Context())
@@ -751,6 +1445,21 @@ func TestParseLinkHeader(t *testing.T) {
 	}
 }
 
+func TestParseLinkHeaderParam(t *testing.T) {
+	header := `<https://api.github.com/repositories/1/releases?page=1>; rel="prev", ` +
+		`<https://api.github.com/repositories/1/releases?page=3>; rel="next", ` +
+		`<https://api.github.com/repositories/1/releases?page=9>; rel="last"`
+	if got := parseLinkHeaderParam(header, "page"); got != "3" {
+		t.Errorf("expected page 3, got %q", got)
+	}
+	if got := parseLinkRelParam(header, "last", "page"); got != "9" {
+		t.Errorf("expected last page 9, got %q", got)
+	}
+	if got := parseLinkHeaderParam(header, "after"); got != "" {
+		t.Errorf("expected empty after cursor, got %q", got)
+	}
+}
+
 func TestInterfaceCompliance(t *testing.T) {
 	var _ Service = (*Client)(nil)
 }
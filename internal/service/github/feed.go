@@ -0,0 +1,354 @@
+package github
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Activity feed errors
+var (
+	ErrFeedClosed     = errors.New("activity feed closed")
+	ErrSlowSubscriber = errors.New("activity subscriber fell behind")
+)
+
+const (
+	// feedHistory is how many recent activities a repository feed keeps for subscribers that resume.
+	feedHistory = 100
+	// subscriberBuffer holds a full replay of the history plus the activities of a few polls.
+	subscriberBuffer = 2 * feedHistory
+	// maxPollBackoff bounds the wait between polls after consecutive failures.
+	maxPollBackoff = 5 * time.Minute
+)
+
+// ActivityFeed polls the activity of each watched repository once, however many subscribers watch
+// it, and fans activities it has not seen before out to all of them. A repository is polled only
+// while it has subscribers. Its ETag, history, and poll schedule are kept for one more interval after
+// the last subscriber leaves, so a client that reconnects meanwhile neither triggers an early poll
+// nor misses activities.
+type ActivityFeed struct {
+	svc      Service
+	interval time.Duration
+	logger   *zap.Logger
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+	repos  map[string]*repoFeed
+}
+
+// repoFeed is the poller state of one repository. Its fields other than ready are guarded by the
+// ActivityFeed's mutex.
+type repoFeed struct {
+	key         string
+	owner, repo string
+	// wake is set while the poller waits for a subscriber, and closed when one arrives.
+	wake chan struct{}
+	// ready is closed once the first poll succeeds or the feed fails or closes, after err is set.
+	ready   chan struct{}
+	settled bool
+	err     error
+	// history holds the most recent activities in ascending ID order.
+	history []Activity
+	seen    map[int64]struct{}
+	subs    map[*Subscription]struct{}
+}
+
+// Subscription receives the new activities of one repository.
+type Subscription struct {
+	feed   *ActivityFeed
+	repo   *repoFeed
+	events chan Activity
+	after  int64
+	ended  bool
+	err    error
+}
+
+// NewActivityFeed creates a feed that polls svc at least interval apart per repository. Call Close
+// to stop it.
+func NewActivityFeed(svc Service, interval time.Duration, logger *zap.Logger) *ActivityFeed {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ActivityFeed{
+		svc:      svc,
+		interval: interval,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		repos:    make(map[string]*repoFeed),
+	}
+}
+
+// Subscribe watches a repository, starting its poller if it has none, and returns once the repository
+// has been polled. Activities already present then are not sent, except that a nonzero after
+// replays the remembered activities with a greater ID, for clients resuming a stream. A first poll
+// that fails returns its error.
+func (f *ActivityFeed) Subscribe(ctx context.Context, owner, repo string, after int64) (*Subscription, error) {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil, ErrFeedClosed
+	}
+	// GitHub owner and repository names are case-insensitive.
+	key := strings.ToLower(owner) + "/" + strings.ToLower(repo)
+	rf, ok := f.repos[key]
+	if !ok {
+		rf = &repoFeed{
+			key:   key,
+			owner: owner,
+			repo:  repo,
+			ready: make(chan struct{}),
+			seen:  make(map[int64]struct{}),
+			subs:  make(map[*Subscription]struct{}),
+		}
+		f.repos[key] = rf
+		f.wg.Add(1)
+		go f.poll(f.ctx, rf)
+	}
+	if rf.wake != nil {
+		close(rf.wake)
+		rf.wake = nil
+	}
+	sub := &Subscription{feed: f, repo: rf, events: make(chan Activity, subscriberBuffer), after: after}
+	rf.subs[sub] = struct{}{}
+	if rf.settled {
+		sub.replay()
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-rf.ready:
+	case <-ctx.Done():
+		sub.Close()
+		return nil, ctx.Err()
+	}
+	if rf.err != nil {
+		return nil, rf.err
+	}
+	return sub, nil
+}
+
+// Close ends every subscription with ErrFeedClosed and stops the pollers. Later subscriptions fail
+// with ErrFeedClosed. It is safe to call more than once.
+func (f *ActivityFeed) Close() {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		for key, rf := range f.repos {
+			for sub := range rf.subs {
+				sub.end(ErrFeedClosed)
+			}
+			rf.settle(ErrFeedClosed)
+			delete(f.repos, key)
+		}
+	}
+	f.mu.Unlock()
+	f.cancel()
+	f.wg.Wait()
+}
+
+func (f *ActivityFeed) poll(ctx context.Context, rf *repoFeed) {
+	defer f.wg.Done()
+	etag := ""
+	failures := 0
+	for {
+		result, err := f.svc.PollActivity(ctx, rf.owner, rf.repo, etag)
+		if ctx.Err() != nil {
+			return
+		}
+		wait := f.interval
+		if err != nil {
+			if f.fail(rf, err) {
+				return
+			}
+			failures = min(failures+1, 8)
+			wait = min(f.interval<<failures, maxPollBackoff)
+			f.logger.Warn("github activity poll failed",
+				zap.String("owner", rf.owner), zap.String("repo", rf.repo),
+				zap.Int("failures", failures), zap.Duration("retry_in", wait), zap.Error(err))
+		} else {
+			failures = 0
+			etag = result.ETag
+			wait = max(wait, result.Interval)
+			f.deliver(rf, result)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if !f.awaitSubscriber(ctx, rf) {
+			return
+		}
+	}
+}
+
+// awaitSubscriber reports whether rf has a subscriber to poll for, waiting up to one interval for one
+// when it has none. A repository that stays unwatched is removed, so its state is forgotten.
+func (f *ActivityFeed) awaitSubscriber(ctx context.Context, rf *repoFeed) bool {
+	f.mu.Lock()
+	if len(rf.subs) > 0 {
+		f.mu.Unlock()
+		return true
+	}
+	wake := make(chan struct{})
+	rf.wake = wake
+	f.mu.Unlock()
+
+	timer := time.NewTimer(f.interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-wake:
+		return true
+	case <-timer.C:
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(rf.subs) > 0 {
+		return true
+	}
+	rf.wake = nil
+	if f.repos[rf.key] == rf {
+		delete(f.repos, rf.key)
+	}
+	return false
+}
+
+// fail ends a repository feed whose first poll failed and reports true. Failures of later polls
+// are retried, so it reports false for them.
+func (f *ActivityFeed) fail(rf *repoFeed, err error) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if rf.settled {
+		return false
+	}
+	for sub := range rf.subs {
+		sub.end(err)
+	}
+	if f.repos[rf.key] == rf {
+		delete(f.repos, rf.key)
+	}
+	rf.settle(err)
+	return true
+}
+
+// deliver sends the activities of a poll that the feed has not seen before. The first poll only
+// records what already exists, and replays it to resuming subscribers.
+func (f *ActivityFeed) deliver(rf *repoFeed, result *ActivityPoll) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	first := !rf.settled
+	if !result.NotModified {
+		var fresh []Activity
+		for _, activity := range result.Activities {
+			if _, ok := rf.seen[activity.ID]; !ok {
+				fresh = append(fresh, activity)
+			}
+		}
+		slices.SortFunc(fresh, func(a, b Activity) int { return cmp.Compare(a.ID, b.ID) })
+		rf.history = append(rf.history, fresh...)
+		rf.history = rf.history[max(len(rf.history)-feedHistory, 0):]
+
+		// Activities that left both the page and the history cannot reappear, so they are forgotten.
+		seen := make(map[int64]struct{}, len(result.Activities)+len(rf.history))
+		for _, activity := range result.Activities {
+			seen[activity.ID] = struct{}{}
+		}
+		for _, activity := range rf.history {
+			seen[activity.ID] = struct{}{}
+		}
+		rf.seen = seen
+
+		if !first {
+			for sub := range rf.subs {
+				for _, activity := range fresh {
+					sub.send(activity)
+				}
+			}
+		}
+	}
+	if first {
+		rf.settle(nil)
+		for sub := range rf.subs {
+			sub.replay()
+		}
+	}
+}
+
+func (rf *repoFeed) settle(err error) {
+	if rf.settled {
+		return
+	}
+	rf.settled = true
+	rf.err = err
+	close(rf.ready)
+}
+
+// Events returns the channel of new activities, which is closed when the subscription ends.
+func (s *Subscription) Events() <-chan Activity {
+	return s.events
+}
+
+// Err reports why the feed ended the subscription: ErrFeedClosed, ErrSlowSubscriber, or nil when
+// it has not ended or the subscriber closed it.
+func (s *Subscription) Err() error {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription. The repository's poller stops polling once it has no subscribers.
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.end(nil)
+}
+
+// replay sends the remembered activities after the subscriber's resume point.
+func (s *Subscription) replay() {
+	if s.after == 0 {
+		return
+	}
+	for _, activity := range s.repo.history {
+		if activity.ID > s.after {
+			s.send(activity)
+		}
+	}
+	s.after = 0
+}
+
+// send queues an activity without blocking the poller. A subscriber whose buffer is full is ended,
+// so it can resume from its last event instead of silently missing some.
+func (s *Subscription) send(activity Activity) {
+	if s.ended {
+		return
+	}
+	select {
+	case s.events <- activity:
+	default:
+		s.end(ErrSlowSubscriber)
+	}
+}
+
+func (s *Subscription) end(err error) {
+	if s.ended {
+		return
+	}
+	s.ended = true
+	s.err = err
+	close(s.events)
+	delete(s.repo.subs, s)
+}
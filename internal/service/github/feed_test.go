@@ -0,0 +1,327 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type pollResponse struct {
+	poll *ActivityPoll
+	err  error
+}
+
+// scriptedPoller answers activity polls with the responses queued by the test, in order, and
+// records the ETag each poll sent. Only PollActivity is implemented.
+type scriptedPoller struct {
+	Service
+	responses chan pollResponse
+
+	mu    sync.Mutex
+	etags []string
+}
+
+func newScriptedPoller() *scriptedPoller {
+	return &scriptedPoller{responses: make(chan pollResponse, 16)}
+}
+
+func (s *scriptedPoller) PollActivity(ctx context.Context, _, _, etag string) (*ActivityPoll, error) {
+	s.mu.Lock()
+	s.etags = append(s.etags, etag)
+	s.mu.Unlock()
+	select {
+	case response := <-s.responses:
+		return response.poll, response.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// waitForETag waits until a poll sends etag.
+func (s *scriptedPoller) waitForETag(t *testing.T, etag string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		sent := slices.Contains(s.etags, etag)
+		s.mu.Unlock()
+		if sent {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected a poll with ETag %s", etag)
+}
+
+func (s *scriptedPoller) page(etag string, ids ...int64) {
+	activities := make([]Activity, len(ids))
+	for i, id := range ids {
+		activities[i] = Activity{ID: id, ActivityType: "push"}
+	}
+	s.responses <- pollResponse{poll: &ActivityPoll{Activities: activities, ETag: etag}}
+}
+
+func (s *scriptedPoller) notModified(etag string) {
+	s.responses <- pollResponse{poll: &ActivityPoll{ETag: etag, NotModified: true}}
+}
+
+func (s *scriptedPoller) fail(err error) {
+	s.responses <- pollResponse{err: err}
+}
+
+func newTestFeed(t *testing.T, svc Service) *ActivityFeed {
+	t.Helper()
+	feed := NewActivityFeed(svc, time.Millisecond, zap.NewNop())
+	t.Cleanup(feed.Close)
+	return feed
+}
+
+func subscribe(t *testing.T, feed *ActivityFeed, after int64) *Subscription {
+	t.Helper()
+	sub, err := feed.Subscribe(t.Context(), "octocat", "hello-world", after)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	return sub
+}
+
+// receive returns the IDs of the next n activities of sub.
+func receive(t *testing.T, sub *Subscription, n int) []int64 {
+	t.Helper()
+	var ids []int64
+	timeout := time.After(5 * time.Second)
+	for len(ids) < n {
+		select {
+		case activity, ok := <-sub.Events():
+			if !ok {
+				t.Fatalf("subscription ended after %v: %v", ids, sub.Err())
+			}
+			ids = append(ids, activity.ID)
+		case <-timeout:
+			t.Fatalf("expected %d activities, got %v", n, ids)
+		}
+	}
+	return ids
+}
+
+// eventually fails the test unless cond, called under the feed's mutex, holds within five seconds.
+func eventually(t *testing.T, feed *ActivityFeed, msg string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		feed.mu.Lock()
+		ok := cond()
+		feed.mu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal(msg)
+}
+
+func TestActivityFeedFansOutNewActivities(t *testing.T) {
+	svc := newScriptedPoller()
+	feed := newTestFeed(t, svc)
+	svc.page(`"e1"`, 2, 1)
+
+	first := subscribe(t, feed, 0)
+	second, err := feed.Subscribe(t.Context(), "OctoCat", "Hello-World", 0)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	svc.notModified(`"e1"`)
+	svc.page(`"e2"`, 4, 3, 2, 1)
+
+	for _, sub := range []*Subscription{first, second} {
+		if got := receive(t, sub, 2); !slices.Equal(got, []int64{3, 4}) {
+			t.Fatalf("expected only the new activities 3 and 4, got %v", got)
+		}
+	}
+	svc.mu.Lock()
+	etags := slices.Clone(svc.etags[:3])
+	svc.mu.Unlock()
+	if !slices.Equal(etags, []string{"", `"e1"`, `"e1"`}) {
+		t.Fatalf("expected polls to send the last ETag, got %q", etags)
+	}
+	feed.mu.Lock()
+	repos := len(feed.repos)
+	feed.mu.Unlock()
+	if repos != 1 {
+		t.Fatalf("expected one poller for both subscribers, got %d", repos)
+	}
+}
+
+func TestActivityFeedResumesAfterLastEventID(t *testing.T) {
+	svc := newScriptedPoller()
+	feed := newTestFeed(t, svc)
+	svc.page(`"e1"`, 3, 2, 1)
+
+	// A resuming subscriber that starts the poller replays what the first poll found.
+	resumed := subscribe(t, feed, 1)
+	if got := receive(t, resumed, 2); !slices.Equal(got, []int64{2, 3}) {
+		t.Fatalf("expected activities 2 and 3, got %v", got)
+	}
+	// One that joins a running poller replays its history.
+	joined := subscribe(t, feed, 2)
+	if got := receive(t, joined, 1); !slices.Equal(got, []int64{3}) {
+		t.Fatalf("expected activity 3, got %v", got)
+	}
+}
+
+func TestActivityFeedFirstPollFailure(t *testing.T) {
+	svc := newScriptedPoller()
+	feed := newTestFeed(t, svc)
+	svc.fail(ErrNotFound)
+
+	if _, err := feed.Subscribe(t.Context(), "octocat", "hello-world", 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// The failed poller is discarded, so the next subscriber polls again.
+	svc.page(`"e1"`, 1)
+	subscribe(t, feed, 0)
+}
+
+func TestActivityFeedRetriesLaterFailures(t *testing.T) {
+	svc := newScriptedPoller()
+	feed := newTestFeed(t, svc)
+	svc.page(`"e1"`, 1)
+	sub := subscribe(t, feed, 0)
+
+	svc.fail(ErrUpstream)
+	svc.page(`"e2"`, 2, 1)
+	if got := receive(t, sub, 1); !slices.Equal(got, []int64{2}) {
+		t.Fatalf("expected activity 2 after the failed poll, got %v", got)
+	}
+}
+
+func TestActivityFeedEndsSlowSubscribers(t *testing.T) {
+	svc := newScriptedPoller()
+	feed := newTestFeed(t, svc)
+	svc.page(`"e1"`, 1)
+	slow := subscribe(t, feed, 0)
+
+	ids := make([]int64, subscriberBuffer+1)
+	for i := range ids {
+		ids[i] = int64(i + 2)
+	}
+	svc.page(`"e2"`, ids...)
+	// Nothing reads until the page has been delivered and has ended the subscription.
+	eventually(t, feed, "expected the page to be delivered", func() bool { return len(slow.repo.subs) == 0 })
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-slow.Events():
+			if !ok {
+				if !errors.Is(slow.Err(), ErrSlowSubscriber) {
+					t.Fatalf("expected ErrSlowSubscriber, got %v", slow.Err())
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("expected the slow subscription to end")
+		}
+	}
+}
+
+func TestActivityFeedStopsPollerWithoutSubscribers(t *testing.T) {
+	svc := newScriptedPoller()
+	feed := NewActivityFeed(svc, 50*time.Millisecond, zap.NewNop())
+	t.Cleanup(feed.Close)
+	svc.page(`"e1"`, 1)
+
+	sub := subscribe(t, feed, 0)
+	sub.Close()
+
+	eventually(t, feed, "expected the unwatched repository to be forgotten", func() bool {
+		return len(feed.repos) == 0
+	})
+	svc.mu.Lock()
+	polls := len(svc.etags)
+	svc.mu.Unlock()
+	if polls != 1 {
+		t.Fatalf("expected no polls without subscribers, got %d polls", polls)
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("expected a closed subscription to have no events")
+	}
+	if sub.Err() != nil {
+		t.Fatalf("expected no error for a subscriber that closed, got %v", sub.Err())
+	}
+}
+
+func TestActivityFeedKeepsStateForReturningSubscribers(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	svc := newScriptedPoller()
+	feed := NewActivityFeed(svc, interval, zap.NewNop())
+	t.Cleanup(feed.Close)
+	svc.page(`"e1"`, 1)
+
+	start := time.Now()
+	subscribe(t, feed, 0).Close()
+
+	// Resubscribing within the interval neither polls early nor forgets the ETag and seen activities.
+	sub := subscribe(t, feed, 0)
+	svc.mu.Lock()
+	polls := len(svc.etags)
+	svc.mu.Unlock()
+	if polls != 1 {
+		t.Fatalf("expected the resubscription to reuse the first poll, got %d polls", polls)
+	}
+	svc.page(`"e2"`, 2, 1)
+	if got := receive(t, sub, 1); !slices.Equal(got, []int64{2}) {
+		t.Fatalf("expected only the new activity 2, got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Fatalf("expected the next poll to wait out the interval, got %v", elapsed)
+	}
+	svc.waitForETag(t, `"e1"`)
+
+	// A subscriber arriving while the poller waits for one is polled for at once, with the last ETag.
+	sub.Close()
+	eventually(t, feed, "expected the poller to wait for a subscriber", func() bool {
+		rf := feed.repos["octocat/hello-world"]
+		return rf != nil && rf.wake != nil
+	})
+	svc.page(`"e3"`, 3, 2, 1)
+	sub = subscribe(t, feed, 0)
+	if got := receive(t, sub, 1); !slices.Equal(got, []int64{3}) {
+		t.Fatalf("expected only the new activity 3, got %v", got)
+	}
+	svc.waitForETag(t, `"e2"`)
+}
+
+func TestActivityFeedClose(t *testing.T) {
+	svc := newScriptedPoller()
+	feed := NewActivityFeed(svc, time.Millisecond, zap.NewNop())
+	svc.page(`"e1"`, 1)
+	sub := subscribe(t, feed, 0)
+
+	// A subscriber still waiting for its first poll is released too.
+	pending := make(chan error, 1)
+	go func() {
+		_, err := feed.Subscribe(t.Context(), "octocat", "other", 0)
+		pending <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	feed.Close()
+	feed.Close()
+
+	if _, ok := <-sub.Events(); ok || !errors.Is(sub.Err(), ErrFeedClosed) {
+		t.Fatalf("expected the subscription to end with ErrFeedClosed, got %v", sub.Err())
+	}
+	if err := <-pending; !errors.Is(err, ErrFeedClosed) {
+		t.Fatalf("expected the pending subscriber to get ErrFeedClosed, got %v", err)
+	}
+	if _, err := feed.Subscribe(t.Context(), "octocat", "hello-world", 0); !errors.Is(err, ErrFeedClosed) {
+		t.Fatalf("expected ErrFeedClosed after Close, got %v", err)
+	}
+}
@@ -13,6 +13,18 @@ var (
 	ErrForbidden   = errors.New("github access forbidden")
 	ErrRateLimited = errors.New("github rate limit exceeded")
 	ErrUpstream    = errors.New("github upstream error")
+
+	ErrContentTooLarge    = errors.New("github content exceeds size limit")
+	ErrUnsupportedContent = errors.New("github content is not a file or directory")
+)
+
+// MaxContentSize is the largest file, in decoded bytes, returned by GetContents and GetReadme.
+const MaxContentSize = 1 << 20
+
+// Content types reported by GitHub's contents API.
+const (
+	ContentTypeFile = "file"
+	ContentTypeDir  = "dir"
 )
 
 // UpstreamErrorKind classifies GitHub upstream failures.
@@ -106,6 +118,18 @@ type ActivityPage struct {
 	NextCursor string
 }
 
+// ActivityPoll holds the newest page of activity fetched with a conditional request.
+type ActivityPoll struct {
+	// Activities is nil when NotModified is set.
+	Activities []Activity
+	// ETag validates the page in the next poll.
+	ETag string
+	// NotModified reports that the page still matches the ETag the poll sent.
+	NotModified bool
+	// Interval is the minimum time GitHub asks pollers to wait, or zero when it names none.
+	Interval time.Duration
+}
+
 // Tag represents a repository tag.
 type Tag struct {
 	Name   string
@@ -117,12 +141,191 @@ type TagCommit struct {
 	SHA string
 }
 
+// Release represents a repository release.
+type Release struct {
+	ID          int64
+	TagName     string
+	Name        string
+	Body        string
+	HTMLURL     string
+	Author      string
+	Draft       bool
+	Prerelease  bool
+	CreatedAt   time.Time
+	PublishedAt time.Time
+	Assets      []ReleaseAsset
+}
+
+// ReleaseAsset represents a downloadable file attached to a release.
+type ReleaseAsset struct {
+	Name          string
+	ContentType   string
+	Size          int64
+	DownloadCount int64
+	DownloadURL   string
+}
+
+// ReleasePage holds a page of release results with cursor for next page.
+type ReleasePage struct {
+	Releases   []Release
+	NextCursor string
+	LastCursor string
+}
+
+// PullRequest represents a repository pull request.
+type PullRequest struct {
+	Number    int
+	Title     string
+	State     string
+	Draft     bool
+	Author    string
+	HTMLURL   string
+	BaseRef   string
+	HeadRef   string
+	Labels    []string
+	Assignees []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  time.Time
+	MergedAt  time.Time
+}
+
+// PullRequestPage holds a page of pull request results with cursor for next page.
+type PullRequestPage struct {
+	PullRequests []PullRequest
+	NextCursor   string
+	LastCursor   string
+}
+
+// PullRequestListParams for listing pull requests. Empty filters use GitHub's defaults.
+type PullRequestListParams struct {
+	State      string
+	Base       string
+	Sort       string
+	Direction  string
+	Limit      int
+	PageCursor string
+}
+
+// Issue represents a repository issue. Pull requests are never returned as issues.
+type Issue struct {
+	Number    int
+	Title     string
+	State     string
+	Author    string
+	HTMLURL   string
+	Labels    []string
+	Assignees []string
+	Comments  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  time.Time
+}
+
+// IssuePage holds a page of issue results with cursor for next page.
+type IssuePage struct {
+	Issues     []Issue
+	NextCursor string
+	LastCursor string
+}
+
+// IssueListParams for listing issues. Empty filters use GitHub's defaults.
+type IssueListParams struct {
+	State      string
+	Labels     []string
+	Assignee   string
+	Sort       string
+	Direction  string
+	Limit      int
+	PageCursor string
+}
+
+// CommitSummary represents a commit in a history listing or comparison.
+type CommitSummary struct {
+	SHA         string
+	Message     string
+	AuthorName  string
+	AuthorLogin string
+	AuthoredAt  time.Time
+	HTMLURL     string
+}
+
+// CommitPage holds a page of commit results with cursor for next page.
+type CommitPage struct {
+	Commits    []CommitSummary
+	NextCursor string
+	LastCursor string
+}
+
+// CommitListParams for listing commits. Empty filters and zero times are not sent upstream.
+type CommitListParams struct {
+	Branch     string
+	Path       string
+	Author     string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	PageCursor string
+}
+
+// FileChange summarizes the changes to one file in a comparison.
+type FileChange struct {
+	Filename         string
+	PreviousFilename string
+	Status           string
+	Additions        int
+	Deletions        int
+	Changes          int
+}
+
+// Comparison describes how a head ref differs from a base ref.
+type Comparison struct {
+	Status       string
+	AheadBy      int
+	BehindBy     int
+	TotalCommits int
+	HTMLURL      string
+	Commits      []CommitSummary
+	Files        []FileChange
+}
+
+// Content is either a file with its decoded bytes or a directory listing.
+type Content struct {
+	Type    string
+	Name    string
+	Path    string
+	SHA     string
+	Size    int64
+	HTMLURL string
+	Data    []byte
+	Entries []ContentEntry
+}
+
+// ContentEntry describes one item in a directory listing.
+type ContentEntry struct {
+	Type string
+	Name string
+	Path string
+	SHA  string
+	Size int64
+}
+
 // Service defines GitHub API operations.
 type Service interface {
 	GetOwner(ctx context.Context, owner string) (*Owner, error)
 	ListRepos(ctx context.Context, owner string) ([]RepoSummary, error)
 	GetRepo(ctx context.Context, owner, repo string) (*Repo, error)
 	ListActivity(ctx context.Context, owner, repo string, limit int, afterCursor string) (*ActivityPage, error)
+	PollActivity(ctx context.Context, owner, repo, etag string) (*ActivityPoll, error)
 	ListLanguages(ctx context.Context, owner, repo string) (map[string]int64, error)
 	ListTags(ctx context.Context, owner, repo string) ([]Tag, error)
+	ListReleases(ctx context.Context, owner, repo string, limit int, pageCursor string) (*ReleasePage, error)
+	GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error)
+	GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error)
+	ListPullRequests(ctx context.Context, owner, repo string, params PullRequestListParams) (*PullRequestPage, error)
+	ListIssues(ctx context.Context, owner, repo string, params IssueListParams) (*IssuePage, error)
+	ListCommits(ctx context.Context, owner, repo string, params CommitListParams) (*CommitPage, error)
+	CompareCommits(ctx context.Context, owner, repo, base, head string) (*Comparison, error)
+	GetContents(ctx context.Context, owner, repo, path, ref string) (*Content, error)
+	GetReadme(ctx context.Context, owner, repo, ref string) (*Content, error)
 }
@@ -0,0 +1,302 @@
+package items
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/janisto/huma-playground/internal/platform/audit"
+	"github.com/janisto/huma-playground/internal/platform/money"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/platform/pagination/firestorepage"
+)
+
+const itemsCollection = "items"
+
+// categorizeError converts errors to audit-safe categories.
+func categorizeError(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrUnavailable):
+		return "unavailable"
+	default:
+		return "internal_error"
+	}
+}
+
+func classifyDependencyError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.Join(ErrUnavailable, err)
+	}
+	switch status.Code(err) {
+	case codes.Aborted, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Unavailable:
+		return errors.Join(ErrUnavailable, err)
+	default:
+		return err
+	}
+}
+
+// firestoreItem maps to Firestore document structure. The price is stored as an integer amount of
+// minor units next to its currency code, so Firestore can filter and order by it.
+type firestoreItem struct {
+	Name        string    `firestore:"name"`
+	Category    string    `firestore:"category"`
+	PriceMinor  int64     `firestore:"price_minor"`
+	Currency    string    `firestore:"currency"`
+	InStock     bool      `firestore:"in_stock"`
+	Description string    `firestore:"description"`
+	CreatedAt   time.Time `firestore:"created_at"`
+	UpdatedAt   time.Time `firestore:"updated_at"`
+}
+
+func toFirestoreItem(item *Item) firestoreItem {
+	return firestoreItem{
+		Name:        item.Name,
+		Category:    item.Category,
+		PriceMinor:  item.Price.Minor(),
+		Currency:    item.Price.Currency(),
+		InStock:     item.InStock,
+		Description: item.Description,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
+
+func toItem(id string, fi firestoreItem) (*Item, error) {
+	price, err := money.New(fi.PriceMinor, fi.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("decode item %s price: %w", id, err)
+	}
+	return &Item{
+		ID:          id,
+		Name:        fi.Name,
+		Category:    fi.Category,
+		Price:       price,
+		InStock:     fi.InStock,
+		Description: fi.Description,
+		CreatedAt:   fi.CreatedAt,
+		UpdatedAt:   fi.UpdatedAt,
+	}, nil
+}
+
+// FirestoreStore implements Store using Firestore.
+type FirestoreStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreStore creates a new Firestore-backed store.
+func NewFirestoreStore(client *firestore.Client) *FirestoreStore {
+	return &FirestoreStore{client: client}
+}
+
+// Create stores a new item under a generated ID.
+func (s *FirestoreStore) Create(ctx context.Context, actorID string, params CreateParams) (*Item, error) {
+	now := time.Now().UTC()
+	item := &Item{
+		ID:          newID(),
+		Name:        params.Name,
+		Category:    params.Category,
+		Price:       params.Price,
+		InStock:     params.InStock,
+		Description: params.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	id := item.ID
+	if _, err := s.client.Collection(itemsCollection).Doc(id).Create(ctx, toFirestoreItem(item)); err != nil {
+		err = classifyDependencyError(err)
+		audit.LogEvent(ctx, "create", actorID, "item", id, "failure",
+			map[string]any{"error": categorizeError(err)})
+		return nil, fmt.Errorf("create item: %w", err)
+	}
+
+	audit.LogEvent(ctx, "create", actorID, "item", id, "success", nil)
+
+	return item, nil
+}
+
+// Get retrieves an item by ID.
+func (s *FirestoreStore) Get(ctx context.Context, id string) (*Item, error) {
+	doc, err := s.client.Collection(itemsCollection).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get item: %w", classifyDependencyError(err))
+	}
+
+	var fi firestoreItem
+	if err := doc.DataTo(&fi); err != nil {
+		return nil, fmt.Errorf("decode item: %w", err)
+	}
+
+	return toItem(id, fi)
+}
+
+// List runs params as a Firestore query and returns the page at params.Cursor. Category and
+// availability filters are query filters; the currency and price range are ones only when items are
+// sorted by price, since Firestore must order by a range filter's field first and the indexes that
+// sort by price also cover the currency. Other conditions, such as the text search, are applied to the
+// documents the query returns.
+func (s *FirestoreStore) List(ctx context.Context, params ListParams) (pagination.Result[Item], error) {
+	q := s.listQuery(params)
+	result, err := firestorepage.Paginate(
+		ctx,
+		params.Codec,
+		q,
+		params.Cursor,
+		params.Limit,
+		params.CursorType,
+		params.BaseURL,
+		params.Query,
+	)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		return pagination.Result[Item]{}, err
+	}
+	if err != nil {
+		return pagination.Result[Item]{}, fmt.Errorf("list items: %w", classifyDependencyError(err))
+	}
+	if params.Total {
+		if result.Total, err = firestorepage.Count(ctx, q); err != nil {
+			return pagination.Result[Item]{}, fmt.Errorf("count items: %w", classifyDependencyError(err))
+		}
+	}
+	return result, nil
+}
+
+// sortPaths maps ListParams sort fields to the document fields they order by.
+var sortPaths = map[string]string{
+	SortName:      "name",
+	SortPrice:     "price_minor",
+	SortCreatedAt: "created_at",
+}
+
+func (s *FirestoreStore) listQuery(params ListParams) firestorepage.Query[Item] {
+	base := s.client.Collection(itemsCollection).Query
+	if len(params.Categories) > 0 {
+		base = base.Where("category", "in", params.Categories)
+	}
+	if params.InStock != nil {
+		base = base.Where("in_stock", "==", *params.InStock)
+	}
+	if params.Sort == SortPrice {
+		if params.Currency != "" {
+			base = base.Where("currency", "==", params.Currency)
+		}
+		if params.MinPriceMinor > 0 {
+			base = base.Where("price_minor", ">=", params.MinPriceMinor)
+		}
+		if params.MaxPriceMinor != nil {
+			base = base.Where("price_minor", "<=", *params.MaxPriceMinor)
+		}
+	}
+
+	direction := firestore.Asc
+	if params.Desc {
+		direction = firestore.Desc
+	}
+	q := firestorepage.Query[Item]{Base: base, IDDirection: direction, Decode: decodeItem}
+	if path, ok := sortPaths[params.Sort]; ok {
+		q.SortKeys = []firestorepage.SortKey{{Path: path, Direction: direction}}
+	}
+	priceFilter := params.Currency != "" || params.MinPriceMinor > 0 || params.MaxPriceMinor != nil
+	if params.Q != "" || (priceFilter && params.Sort != SortPrice) {
+		q.Match = params.match
+	}
+	return q
+}
+
+func decodeItem(doc *firestore.DocumentSnapshot) (Item, error) {
+	var fi firestoreItem
+	if err := doc.DataTo(&fi); err != nil {
+		return Item{}, err
+	}
+	item, err := toItem(doc.Ref.ID, fi)
+	if err != nil {
+		return Item{}, err
+	}
+	return *item, nil
+}
+
+// Update updates an item using a transaction for atomicity.
+func (s *FirestoreStore) Update(ctx context.Context, actorID, id string, params UpdateParams) (*Item, error) {
+	docRef := s.client.Collection(itemsCollection).Doc(id)
+
+	var result *Item
+
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var fi firestoreItem
+		if err := doc.DataTo(&fi); err != nil {
+			return err
+		}
+
+		item, err := toItem(id, fi)
+		if err != nil {
+			return err
+		}
+		params.apply(item)
+		item.UpdatedAt = time.Now().UTC()
+		if err := tx.Set(docRef, toFirestoreItem(item)); err != nil {
+			return err
+		}
+
+		result = item
+		return nil
+	})
+	if err != nil {
+		err = classifyDependencyError(err)
+		audit.LogEvent(ctx, "update", actorID, "item", id, "failure",
+			map[string]any{"error": categorizeError(err)})
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("update item: %w", err)
+	}
+
+	audit.LogEvent(ctx, "update", actorID, "item", id, "success", nil)
+
+	return result, nil
+}
+
+// Delete atomically removes an existing item.
+func (s *FirestoreStore) Delete(ctx context.Context, actorID, id string) error {
+	_, err := s.client.Collection(itemsCollection).Doc(id).Delete(ctx, firestore.Exists)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.FailedPrecondition, codes.NotFound:
+			err = ErrNotFound
+		default:
+			err = classifyDependencyError(err)
+		}
+		audit.LogEvent(ctx, "delete", actorID, "item", id, "failure",
+			map[string]any{"error": categorizeError(err)})
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("delete item: %w", err)
+	}
+
+	audit.LogEvent(ctx, "delete", actorID, "item", id, "success", nil)
+
+	return nil
+}
+
+// Compile-time interface check
+var _ Store = (*FirestoreStore)(nil)
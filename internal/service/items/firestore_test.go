@@ -0,0 +1,143 @@
+package items
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/janisto/huma-playground/internal/platform/money"
+	"github.com/janisto/huma-playground/internal/testutil"
+)
+
+func setupFirestoreTest(t *testing.T) *FirestoreStore {
+	t.Helper()
+
+	testutil.SkipIfEmulatorUnavailable(t)
+	testutil.SetupEmulator(t)
+	testutil.ClearFirestore(t)
+
+	client, err := firestore.NewClient(t.Context(), testutil.ProjectID)
+	if err != nil {
+		t.Fatalf("failed to create Firestore client: %v", err)
+	}
+	t.Cleanup(func() {
+		testutil.ClearFirestore(t)
+		if err := client.Close(); err != nil {
+			t.Errorf("close Firestore client: %v", err)
+		}
+	})
+	return NewFirestoreStore(client)
+}
+
+func TestFirestoreLifecycle(t *testing.T) {
+	store := setupFirestoreTest(t)
+	ctx := t.Context()
+
+	created, err := store.Create(ctx, "admin-1", CreateParams{
+		Name:        "Omega Drill",
+		Category:    "tools",
+		Price:       money.MustNew(8900, "EUR"),
+		InStock:     true,
+		Description: "Cordless drill",
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := store.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != "Omega Drill" || got.Price != money.MustNew(8900, "EUR") || !got.InStock {
+		t.Fatalf("unexpected stored item %+v", got)
+	}
+
+	inStock := false
+	updated, err := store.Update(ctx, "admin-1", created.ID, UpdateParams{InStock: &inStock})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.InStock || updated.Name != "Omega Drill" || !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("unexpected updated item %+v", updated)
+	}
+
+	result, err := store.List(ctx, ListParams{
+		Q:          "drill",
+		Sort:       SortPrice,
+		Total:      true,
+		Limit:      10,
+		CursorType: "item",
+		Codec:      testutil.Cursors(),
+	})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if items := result.Items; len(items) != 1 || items[0].ID != created.ID || items[0].InStock || result.Total != 1 {
+		t.Fatalf("unexpected list %+v", result)
+	}
+	inStock = true
+	result, err = store.List(ctx, ListParams{
+		InStock:    &inStock,
+		Limit:      10,
+		CursorType: "item",
+		Codec:      testutil.Cursors(),
+	})
+	if err != nil {
+		t.Fatalf("list in stock: %v", err)
+	}
+	if len(result.Items) != 0 {
+		t.Fatalf("expected no items in stock, got %+v", result.Items)
+	}
+
+	if err := store.Delete(ctx, "admin-1", created.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestFirestoreMissingItem(t *testing.T) {
+	store := setupFirestoreTest(t)
+	ctx := t.Context()
+
+	if _, err := store.Get(ctx, "item-missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get: expected ErrNotFound, got %v", err)
+	}
+	name := "renamed"
+	if _, err := store.Update(ctx, "admin-1", "item-missing", UpdateParams{Name: &name}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("update: expected ErrNotFound, got %v", err)
+	}
+	if err := store.Delete(ctx, "admin-1", "item-missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClassifyDependencyError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		unavailable bool
+	}{
+		{"unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "quota"), true},
+		{"permission denied", status.Error(codes.PermissionDenied, "denied"), false},
+		{"canceled", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyDependencyError(tt.err)
+			if errors.Is(err, ErrUnavailable) != tt.unavailable {
+				t.Fatalf("expected unavailable=%v, got %v", tt.unavailable, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected the original error to be preserved, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package items
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+)
+
+// Sort fields for ListParams. An empty Sort orders items by ID alone.
+const (
+	SortName      = "name"
+	SortPrice     = "priceMinor"
+	SortCreatedAt = "createdAt"
+)
+
+// ListParams selects, orders, and pages the items List returns. Unset filters match every item. Prices
+// in different currencies do not compare, so a price range or price sort should come with a Currency.
+type ListParams struct {
+	Categories    []string
+	InStock       *bool
+	Currency      string
+	MinPriceMinor int64
+	MaxPriceMinor *int64
+	// Q matches items whose name or description contains it, ignoring case.
+	Q string
+
+	Sort string
+	Desc bool
+	// Total counts every matching item into the result, at the cost of an extra query.
+	Total bool
+
+	Cursor     pagination.Cursor
+	Limit      int
+	CursorType string
+	// Codec signs the page cursors, binding them to Query; BaseURL and Query build the Link header.
+	Codec   *pagination.Codec
+	BaseURL string
+	Query   url.Values
+}
+
+func (p ListParams) match(item Item) bool {
+	if len(p.Categories) > 0 && !slices.Contains(p.Categories, item.Category) {
+		return false
+	}
+	if p.InStock != nil && item.InStock != *p.InStock {
+		return false
+	}
+	if p.Currency != "" && item.Price.Currency() != p.Currency {
+		return false
+	}
+	if price := item.Price.Minor(); price < p.MinPriceMinor || (p.MaxPriceMinor != nil && price > *p.MaxPriceMinor) {
+		return false
+	}
+	if p.Q != "" {
+		q := strings.ToLower(p.Q)
+		return strings.Contains(strings.ToLower(item.Name), q) || strings.Contains(strings.ToLower(item.Description), q)
+	}
+	return true
+}
+
+// itemOrder sorts items by a field, breaking ties by ID so every item has a unique position. An empty
+// field orders by ID alone.
+type itemOrder struct {
+	field string
+	desc  bool
+}
+
+// sortKey holds the fields of an item that an itemOrder compares.
+type sortKey struct {
+	id      string
+	name    string
+	price   int64
+	created time.Time
+}
+
+func (o itemOrder) key(item Item) sortKey {
+	return sortKey{id: item.ID, name: item.Name, price: item.Price.Minor(), created: item.CreatedAt}
+}
+
+func (o itemOrder) compare(a, b Item) int {
+	return o.compareKeys(o.key(a), o.key(b))
+}
+
+func (o itemOrder) compareKeys(a, b sortKey) int {
+	var c int
+	switch o.field {
+	case SortName:
+		c = strings.Compare(a.name, b.name)
+	case SortPrice:
+		c = cmp.Compare(a.price, b.price)
+	case SortCreatedAt:
+		c = a.created.Compare(b.created)
+	}
+	if c == 0 {
+		c = strings.Compare(a.id, b.id)
+	}
+	if o.desc {
+		return -c
+	}
+	return c
+}
+
+// position encodes the sort key and ID of item for a cursor, so the next page resumes after that key
+// even if the item has since changed. Items ordered by ID alone use the bare ID.
+func (o itemOrder) position(item Item) string {
+	var key string
+	switch o.field {
+	case SortName:
+		key = item.Name
+	case SortPrice:
+		key = strconv.FormatInt(item.Price.Minor(), 10)
+	case SortCreatedAt:
+		key = item.CreatedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		return item.ID
+	}
+	data, _ := json.Marshal([2]string{key, item.ID})
+	return string(data)
+}
+
+// at decodes a position into the sort key it was made from.
+func (o itemOrder) at(position string) (sortKey, error) {
+	if o.field == "" {
+		return sortKey{id: position}, nil
+	}
+	var parts [2]string
+	if err := json.Unmarshal([]byte(position), &parts); err != nil || parts[1] == "" {
+		return sortKey{}, pagination.ErrInvalidCursor
+	}
+	key := sortKey{id: parts[1]}
+	var err error
+	switch o.field {
+	case SortName:
+		key.name = parts[0]
+	case SortPrice:
+		key.price, err = strconv.ParseInt(parts[0], 10, 64)
+	case SortCreatedAt:
+		key.created, err = time.Parse(time.RFC3339Nano, parts[0])
+	}
+	if err != nil {
+		return sortKey{}, pagination.ErrInvalidCursor
+	}
+	return key, nil
+}
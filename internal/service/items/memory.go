@@ -0,0 +1,120 @@
+package items
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+)
+
+// MemoryStore implements Store in process memory. Changes are lost on restart and are not shared
+// between instances, so it suits local development and tests.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	items map[string]Item
+}
+
+// NewMemoryStore creates a store holding items.
+func NewMemoryStore(items ...Item) *MemoryStore {
+	s := &MemoryStore{items: make(map[string]Item, len(items))}
+	for _, item := range items {
+		s.items[item.ID] = item
+	}
+	return s
+}
+
+// Create stores a new item under a generated ID.
+func (s *MemoryStore) Create(_ context.Context, _ string, params CreateParams) (*Item, error) {
+	now := time.Now().UTC()
+	item := Item{
+		ID:          newID(),
+		Name:        params.Name,
+		Category:    params.Category,
+		Price:       params.Price,
+		InStock:     params.InStock,
+		Description: params.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[item.ID] = item
+	return &item, nil
+}
+
+// Get retrieves an item by ID.
+func (s *MemoryStore) Get(_ context.Context, id string) (*Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.items[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &item, nil
+}
+
+// List filters and sorts the whole store in memory, then returns the page at params.Cursor.
+func (s *MemoryStore) List(_ context.Context, params ListParams) (pagination.Result[Item], error) {
+	order := itemOrder{field: params.Sort, desc: params.Desc}
+	var from sortKey
+	if params.Cursor.Value != "" {
+		var err error
+		if from, err = order.at(params.Cursor.Value); err != nil {
+			return pagination.Result[Item]{}, err
+		}
+	}
+
+	s.mu.RLock()
+	items := make([]Item, 0, len(s.items))
+	for _, item := range s.items {
+		if params.match(item) {
+			items = append(items, item)
+		}
+	}
+	s.mu.RUnlock()
+	slices.SortFunc(items, order.compare)
+
+	// The cursor holds a position rather than an index, so the next page still starts in the right
+	// place when the item it names has since been deleted.
+	return pagination.PaginateAfter(
+		params.Codec,
+		items,
+		params.Cursor,
+		params.Limit,
+		params.CursorType,
+		order.position,
+		func(item Item) bool { return order.compareKeys(order.key(item), from) > 0 },
+		params.BaseURL,
+		params.Query,
+	), nil
+}
+
+// Update applies the provided fields to an existing item.
+func (s *MemoryStore) Update(_ context.Context, _, id string, params UpdateParams) (*Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	params.apply(&item)
+	item.UpdatedAt = time.Now().UTC()
+	s.items[id] = item
+	return &item, nil
+}
+
+// Delete removes an existing item.
+func (s *MemoryStore) Delete(_ context.Context, _, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.items[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.items, id)
+	return nil
+}
+
+// Compile-time interface check
+var _ Store = (*MemoryStore)(nil)
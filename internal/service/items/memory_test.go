@@ -0,0 +1,138 @@
+package items
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/janisto/huma-playground/internal/platform/money"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+	"github.com/janisto/huma-playground/internal/testutil"
+)
+
+func TestMemoryStoreLifecycle(t *testing.T) {
+	store := NewMemoryStore(SampleItems()...)
+	ctx := t.Context()
+
+	created, err := store.Create(ctx, "admin-1", CreateParams{
+		Name:     "Omega Drill",
+		Category: "tools",
+		Price:    money.MustNew(8900, "EUR"),
+		InStock:  true,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.HasPrefix(created.ID, "item-") || created.CreatedAt.IsZero() {
+		t.Fatalf("unexpected created item %+v", created)
+	}
+
+	price := money.MustNew(0, "EUR")
+	updated, err := store.Update(ctx, "admin-1", created.ID, UpdateParams{Price: &price})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Price.Minor() != 0 || updated.Name != "Omega Drill" || updated.UpdatedAt.Before(created.UpdatedAt) {
+		t.Fatalf("unexpected updated item %+v", updated)
+	}
+
+	result, err := store.List(ctx, ListParams{Limit: 100, CursorType: "item", Codec: testutil.Cursors()})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	items := result.Items
+	if len(items) != len(SampleItems())+1 {
+		t.Fatalf("expected the new item to be listed, got %d items", len(items))
+	}
+	for i := 1; i < len(items); i++ {
+		if items[i-1].ID >= items[i].ID {
+			t.Fatalf("expected items ordered by ID, got %s before %s", items[i-1].ID, items[i].ID)
+		}
+	}
+
+	if err := store.Delete(ctx, "admin-1", created.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := store.Delete(ctx, "admin-1", created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on second delete, got %v", err)
+	}
+	if _, err := store.Update(ctx, "admin-1", created.ID, UpdateParams{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on update, got %v", err)
+	}
+}
+
+func TestMemoryStoreReturnsCopies(t *testing.T) {
+	store := NewMemoryStore(SampleItems()...)
+	item, err := store.Get(t.Context(), "item-001")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	item.Name = "changed"
+	again, err := store.Get(t.Context(), "item-001")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if again.Name != "Alpha Widget" {
+		t.Fatalf("expected the stored item to be unchanged, got %q", again.Name)
+	}
+}
+
+func TestMemoryStoreListFiltersAndPages(t *testing.T) {
+	store := NewMemoryStore(SampleItems()...)
+	inStock := true
+	maxPrice := int64(5000)
+	params := ListParams{
+		Categories:    []string{"electronics", "tools"},
+		InStock:       &inStock,
+		MaxPriceMinor: &maxPrice,
+		Sort:          SortPrice,
+		Desc:          true,
+		Limit:         2,
+		CursorType:    "item",
+		Codec:         testutil.Cursors(),
+	}
+
+	var seen []Item
+	for {
+		result, err := store.List(t.Context(), params)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		seen = append(seen, result.Items...)
+		if result.NextCursor == "" {
+			break
+		}
+		if params.Cursor, err = params.Codec.Decode(result.NextCursor, params.Query); err != nil {
+			t.Fatalf("decode next cursor: %v", err)
+		}
+	}
+	if len(seen) == 0 {
+		t.Fatal("expected matching items")
+	}
+	for i, item := range seen {
+		if !item.InStock || item.Price.Minor() > maxPrice ||
+			(item.Category != "electronics" && item.Category != "tools") {
+			t.Fatalf("unexpected item %+v", item)
+		}
+		if i > 0 && seen[i-1].Price.Minor() < item.Price.Minor() {
+			t.Fatalf("expected descending prices, got %s before %s", seen[i-1].Price, item.Price)
+		}
+	}
+}
+
+func TestMemoryStoreListRejectsInvalidPositions(t *testing.T) {
+	store := NewMemoryStore(SampleItems()...)
+	_, err := store.List(t.Context(), ListParams{
+		Sort:       SortName,
+		Cursor:     pagination.Cursor{Type: "item", Value: "item-001"},
+		Limit:      10,
+		CursorType: "item",
+		Codec:      testutil.Cursors(),
+	})
+	if !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+package items
+
+import (
+	"time"
+
+	"github.com/janisto/huma-playground/internal/platform/money"
+)
+
+// SampleItems returns the demo catalog the in-memory store is seeded with. Each call returns a fresh
+// copy, so stores never share entries.
+func SampleItems() []Item {
+	items := []Item{
+		{
+			ID:          "item-001",
+			Name:        "Alpha Widget",
+			Category:    "electronics",
+			Price:       money.MustNew(2999, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			Description: "A versatile electronic widget for everyday use",
+		},
+		{
+			ID:          "item-002",
+			Name:        "Beta Gadget",
+			Category:    "electronics",
+			Price:       money.MustNew(4999, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 16, 11, 0, 0, 0, time.UTC),
+			Description: "Advanced gadget with smart features",
+		},
+		{
+			ID:          "item-003",
+			Name:        "Gamma Tool",
+			Category:    "tools",
+			Price:       money.MustNew(1550, "USD"),
+			InStock:     false,
+			CreatedAt:   time.Date(2024, 1, 17, 9, 15, 0, 0, time.UTC),
+			Description: "Precision tool for professional work",
+		},
+		{
+			ID:          "item-004",
+			Name:        "Delta Component",
+			Category:    "electronics",
+			Price:       money.MustNew(899, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 18, 14, 45, 0, 0, time.UTC),
+			Description: "Essential component for electronics projects",
+		},
+		{
+			ID:          "item-005",
+			Name:        "Epsilon Sensor",
+			Category:    "electronics",
+			Price:       money.MustNew(3499, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 19, 8, 0, 0, 0, time.UTC),
+			Description: "High-precision environmental sensor",
+		},
+		{
+			ID:          "item-006",
+			Name:        "Zeta Cable",
+			Category:    "accessories",
+			Price:       money.MustNew(1299, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 20, 16, 30, 0, 0, time.UTC),
+			Description: "Premium quality data cable",
+		},
+		{
+			ID:          "item-007",
+			Name:        "Eta Adapter",
+			Category:    "accessories",
+			Price:       money.MustNew(999, "USD"),
+			InStock:     false,
+			CreatedAt:   time.Date(2024, 1, 21, 10, 0, 0, 0, time.UTC),
+			Description: "Universal power adapter",
+		},
+		{
+			ID:          "item-008",
+			Name:        "Theta Board",
+			Category:    "electronics",
+			Price:       money.MustNew(8999, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 22, 11, 30, 0, 0, time.UTC),
+			Description: "Development board for prototyping",
+		},
+		{
+			ID:          "item-009",
+			Name:        "Iota Switch",
+			Category:    "electronics",
+			Price:       money.MustNew(599, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 23, 9, 45, 0, 0, time.UTC),
+			Description: "Tactile push button switch",
+		},
+		{
+			ID:          "item-010",
+			Name:        "Kappa Display",
+			Category:    "electronics",
+			Price:       money.MustNew(4599, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 24, 13, 0, 0, 0, time.UTC),
+			Description: "OLED display module",
+		},
+		{
+			ID:          "item-011",
+			Name:        "Lambda Motor",
+			Category:    "robotics",
+			Price:       money.MustNew(2499, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 25, 8, 30, 0, 0, time.UTC),
+			Description: "DC motor for robotics projects",
+		},
+		{
+			ID:          "item-012",
+			Name:        "Mu Servo",
+			Category:    "robotics",
+			Price:       money.MustNew(1899, "USD"),
+			InStock:     false,
+			CreatedAt:   time.Date(2024, 1, 26, 15, 0, 0, 0, time.UTC),
+			Description: "High-torque servo motor",
+		},
+		{
+			ID:          "item-013",
+			Name:        "Nu Battery",
+			Category:    "power",
+			Price:       money.MustNew(1499, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 27, 10, 15, 0, 0, time.UTC),
+			Description: "Rechargeable lithium battery pack",
+		},
+		{
+			ID:          "item-014",
+			Name:        "Xi Charger",
+			Category:    "power",
+			Price:       money.MustNew(2299, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 28, 11, 45, 0, 0, time.UTC),
+			Description: "Smart battery charger",
+		},
+		{
+			ID:          "item-015",
+			Name:        "Omicron Relay",
+			Category:    "electronics",
+			Price:       money.MustNew(799, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 29, 9, 0, 0, 0, time.UTC),
+			Description: "5V relay module",
+		},
+		{
+			ID:          "item-016",
+			Name:        "Pi Controller",
+			Category:    "electronics",
+			Price:       money.MustNew(5599, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 1, 30, 14, 30, 0, 0, time.UTC),
+			Description: "Microcontroller board",
+		},
+		{
+			ID:          "item-017",
+			Name:        "Rho Resistor Kit",
+			Category:    "components",
+			Price:       money.MustNew(1199, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC),
+			Description: "Assorted resistor pack",
+		},
+		{
+			ID:          "item-018",
+			Name:        "Sigma Capacitor Set",
+			Category:    "components",
+			Price:       money.MustNew(1399, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 2, 10, 30, 0, 0, time.UTC),
+			Description: "Electrolytic capacitor assortment",
+		},
+		{
+			ID:          "item-019",
+			Name:        "Tau LED Pack",
+			Category:    "components",
+			Price:       money.MustNew(699, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 3, 11, 0, 0, 0, time.UTC),
+			Description: "Multi-color LED assortment",
+		},
+		{
+			ID:          "item-020",
+			Name:        "Upsilon Wire Set",
+			Category:    "accessories",
+			Price:       money.MustNew(899, "USD"),
+			InStock:     false,
+			CreatedAt:   time.Date(2024, 2, 4, 9, 15, 0, 0, time.UTC),
+			Description: "Jumper wire kit",
+		},
+		{
+			ID:          "item-021",
+			Name:        "Phi Breadboard",
+			Category:    "tools",
+			Price:       money.MustNew(499, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 5, 13, 45, 0, 0, time.UTC),
+			Description: "Solderless breadboard",
+		},
+		{
+			ID:          "item-022",
+			Name:        "Chi Soldering Iron",
+			Category:    "tools",
+			Price:       money.MustNew(3599, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 6, 10, 0, 0, 0, time.UTC),
+			Description: "Temperature-controlled soldering station",
+		},
+		{
+			ID:          "item-023",
+			Name:        "Psi Multimeter",
+			Category:    "tools",
+			Price:       money.MustNew(4299, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 7, 11, 30, 0, 0, time.UTC),
+			Description: "Digital multimeter with auto-ranging",
+		},
+		{
+			ID:          "item-024",
+			Name:        "Omega Oscilloscope",
+			Category:    "tools",
+			Price:       money.MustNew(29999, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 8, 14, 0, 0, 0, time.UTC),
+			Description: "Portable digital oscilloscope",
+		},
+		{
+			ID:          "item-025",
+			Name:        "Alpha Pro Widget",
+			Category:    "electronics",
+			Price:       money.MustNew(5999, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 9, 8, 30, 0, 0, time.UTC),
+			Description: "Professional-grade widget with extended features",
+		},
+		{
+			ID:          "item-026",
+			Name:        "Beta Max Gadget",
+			Category:    "electronics",
+			Price:       money.MustNew(7999, "USD"),
+			InStock:     false,
+			CreatedAt:   time.Date(2024, 2, 10, 9, 0, 0, 0, time.UTC),
+			Description: "Maximum performance gadget",
+		},
+		{
+			ID:          "item-027",
+			Name:        "Gamma Plus Tool",
+			Category:    "tools",
+			Price:       money.MustNew(2599, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 11, 10, 15, 0, 0, time.UTC),
+			Description: "Enhanced precision tool",
+		},
+		{
+			ID:          "item-028",
+			Name:        "Delta Ultra Component",
+			Category:    "electronics",
+			Price:       money.MustNew(1699, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 12, 11, 45, 0, 0, time.UTC),
+			Description: "Ultra-reliable component",
+		},
+		{
+			ID:          "item-029",
+			Name:        "Epsilon HD Sensor",
+			Category:    "electronics",
+			Price:       money.MustNew(5499, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 13, 13, 0, 0, 0, time.UTC),
+			Description: "High-definition sensor array",
+		},
+		{
+			ID:          "item-030",
+			Name:        "Zeta Premium Cable",
+			Category:    "accessories",
+			Price:       money.MustNew(1999, "USD"),
+			InStock:     true,
+			CreatedAt:   time.Date(2024, 2, 14, 15, 30, 0, 0, time.UTC),
+			Description: "Gold-plated premium cable",
+		},
+	}
+	for i := range items {
+		items[i].UpdatedAt = items[i].CreatedAt
+	}
+	return items
+}
@@ -0,0 +1,86 @@
+package items
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/janisto/huma-playground/internal/platform/money"
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+)
+
+// Service errors
+var (
+	ErrNotFound    = errors.New("item not found")
+	ErrUnavailable = errors.New("item store unavailable")
+)
+
+// Item represents a stored catalog item.
+type Item struct {
+	ID          string
+	Name        string
+	Category    string
+	Price       money.Money
+	InStock     bool
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CreateParams for creating an item.
+type CreateParams struct {
+	Name        string
+	Category    string
+	Price       money.Money
+	InStock     bool
+	Description string
+}
+
+// UpdateParams for updating an item.
+type UpdateParams struct {
+	Name        *string
+	Category    *string
+	Price       *money.Money
+	InStock     *bool
+	Description *string
+}
+
+// Store defines item persistence operations. actorID identifies the user making a change for the
+// audit log.
+//
+// List returns one page of the items matching params, in params order. A cursor that does not
+// describe a position in that order returns pagination.ErrInvalidCursor.
+type Store interface {
+	Create(ctx context.Context, actorID string, params CreateParams) (*Item, error)
+	Get(ctx context.Context, id string) (*Item, error)
+	List(ctx context.Context, params ListParams) (pagination.Result[Item], error)
+	Update(ctx context.Context, actorID, id string, params UpdateParams) (*Item, error)
+	Delete(ctx context.Context, actorID, id string) error
+}
+
+// newID returns a random item ID in the same item- namespace as the sample catalog.
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "item-" + hex.EncodeToString(b)
+}
+
+func (p UpdateParams) apply(item *Item) {
+	if p.Name != nil {
+		item.Name = *p.Name
+	}
+	if p.Category != nil {
+		item.Category = *p.Category
+	}
+	if p.Price != nil {
+		item.Price = *p.Price
+	}
+	if p.InStock != nil {
+		item.InStock = *p.InStock
+	}
+	if p.Description != nil {
+		item.Description = *p.Description
+	}
+}
@@ -78,6 +78,15 @@ func NewFirestoreStore(client *firestore.Client) *FirestoreStore {
 	return &FirestoreStore{client: client}
 }
 
+// Check performs a lightweight keys-only read of at most one profile so readiness reflects whether
+// Firestore is reachable and authorized.
+func (s *FirestoreStore) Check(ctx context.Context) error {
+	if _, err := s.client.Collection(profilesCollection).Select().Limit(1).Documents(ctx).GetAll(); err != nil {
+		return fmt.Errorf("check profiles collection: %w", classifyDependencyError(err))
+	}
+	return nil
+}
+
 // Create atomically creates a profile if it does not already exist.
 func (s *FirestoreStore) Create(ctx context.Context, userID string, params CreateParams) (*Profile, error) {
 	docRef := s.client.Collection(profilesCollection).Doc(userID)
@@ -140,6 +140,19 @@ func TestFirestoreCreateDuplicate(t *testing.T) {
 	}
 }
 
+func TestFirestoreCheck(t *testing.T) {
+	store, cleanup := setupFirestoreTest(t)
+	defer cleanup()
+
+	if err := store.Check(t.Context()); err != nil {
+		t.Fatalf("expected empty collection to pass, got %v", err)
+	}
+	createTestProfile(t, store, t.Context(), "user-check", CreateParams{FirstName: "Jane", LastName: "Smith"})
+	if err := store.Check(t.Context()); err != nil {
+		t.Fatalf("expected populated collection to pass, got %v", err)
+	}
+}
+
 func TestFirestoreGet(t *testing.T) {
 	store, cleanup := setupFirestoreTest(t)
 	defer cleanup()
@@ -0,0 +1,19 @@
+package testutil
+
+import (
+	"bytes"
+
+	"github.com/janisto/huma-playground/internal/platform/pagination"
+)
+
+// Cursors returns a pagination cursor codec signed with a fixed test key, so a test can mint the
+// cursors a router under test accepts.
+func Cursors() *pagination.Codec {
+	codec, err := pagination.NewCodec(pagination.CodecConfig{
+		Keys: []pagination.Key{{ID: "test", Secret: bytes.Repeat([]byte("t"), 32)}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return codec
+}